@@ -0,0 +1,151 @@
+package api
+
+import (
+	"errors"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+)
+
+var errAPIKeyManagement = errors.New("API keys can not be managed using an API key")
+
+func (v *v1) initAPIKeyTypes() apiKeyTypes {
+	ret := apiKeyTypes{}
+
+	ret.apiKeyType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "APIKey",
+		Description: "An API key used by integrations to access the organisation. The key itself is only available when created",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.ID),
+				Description: "Unique ID",
+			},
+			"name": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "Name describing what the key is used for",
+			},
+			"scopes": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.String)),
+				Description: "The roles granted to the key, the key acts with the most permissive role",
+			},
+			"created": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "When the key was created",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					obj, ok := p.Source.(impact.APIKey)
+					if !ok {
+						return nil, errors.New("Expecting an impact.APIKey")
+					}
+					return obj.Created.Format(time.RFC3339), nil
+				},
+			},
+			"user": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "The user who created the key",
+			},
+			"revoked": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.Boolean),
+				Description: "Revoked keys can no longer be used",
+			},
+		},
+	})
+
+	ret.newAPIKeyType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "NewAPIKey",
+		Description: "A newly created API key along with the key itself",
+		Fields: graphql.Fields{
+			"key": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "The API key, provided as a bearer token in the Authorization header. It is not stored so can not be retrieved again",
+			},
+			"apiKey": &graphql.Field{
+				Type:        graphql.NewNonNull(ret.apiKeyType),
+				Description: "The API key's details",
+			},
+		},
+	})
+
+	return ret
+}
+
+// apiKeyManagementResolver only allows admin users, not API keys, to manage API keys
+func apiKeyManagementResolver(fn userAuthenticatedResolver) graphql.FieldResolveFn {
+	return roleRestrictedResolver(auth.ADMIN, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+		if auth.IsAPIKey(u) {
+			return nil, errAPIKeyManagement
+		}
+		return fn(p, u)
+	})
+}
+
+func (v *v1) getAPIKeyQueries(keyTypes apiKeyTypes) graphql.Fields {
+	return graphql.Fields{
+		"apiKeys": &graphql.Field{
+			Type:        graphql.NewList(keyTypes.apiKeyType),
+			Description: "Get the organisation's API keys",
+			Resolve: apiKeyManagementResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				return v.db.GetAPIKeys(u)
+			}),
+		},
+	}
+}
+
+func (v *v1) getAPIKeyMutations(keyTypes apiKeyTypes) graphql.Fields {
+	return graphql.Fields{
+		"CreateAPIKey": &graphql.Field{
+			Type:        keyTypes.newAPIKeyType,
+			Description: "Create an API key for an integration to access the organisation",
+			Args: graphql.FieldConfigArgument{
+				"name": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "Name describing what the key is used for",
+				},
+				"scopes": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+					Description: "The roles granted to the key, either admin, practitioner or analyst. Use analyst for read only access, including reports",
+				},
+			},
+			Resolve: apiKeyManagementResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				name := p.Args["name"].(string)
+				scopes := []string{}
+				for _, s := range p.Args["scopes"].([]interface{}) {
+					scopes = append(scopes, s.(string))
+				}
+				if _, err := auth.ParseScopes(scopes); err != nil {
+					return nil, err
+				}
+				key, hash, err := auth.GenerateAPIKey()
+				if err != nil {
+					return nil, err
+				}
+				apiKey, err := v.db.NewAPIKey(name, hash, scopes, u)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]interface{}{
+					"key":    key,
+					"apiKey": apiKey,
+				}, nil
+			}),
+		},
+		"RevokeAPIKey": &graphql.Field{
+			Type:        graphql.ID,
+			Description: "Revokes an API key, preventing it from being used. Returns the ID of the revoked key",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.ID),
+					Description: "The ID of the API key",
+				},
+			},
+			Resolve: apiKeyManagementResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				id := p.Args["id"].(string)
+				if err := v.db.RevokeAPIKey(id, u); err != nil {
+					return nil, err
+				}
+				return id, nil
+			}),
+		},
+	}
+}
@@ -0,0 +1,43 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/impactasaurus/server/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeys(t *testing.T) {
+	h, issue := setupOrgUsers(t)
+	admin := issue(auth.ADMIN)
+
+	res := query(t, h, issue(auth.PRACTITIONER), `mutation { CreateAPIKey(name: "crm", scopes: ["analyst"]) { key } }`)
+	assert.NotEmpty(t, res.Errors, "only admins can create API keys")
+	res = query(t, h, admin, `mutation { CreateAPIKey(name: "crm", scopes: ["superuser"]) { key } }`)
+	assert.NotEmpty(t, res.Errors, "scopes must be known roles")
+
+	res = query(t, h, admin, `mutation { CreateAPIKey(name: "crm", scopes: ["analyst"]) { key apiKey { id } } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	created := res.Data["CreateAPIKey"].(map[string]interface{})
+	key := created["key"].(string)
+	id := created["apiKey"].(map[string]interface{})["id"].(string)
+
+	res = query(t, h, key, `{ outcomesets { id } }`)
+	assert.Len(t, res.Errors, 0, "analyst keys can read")
+	res = query(t, h, key, `mutation { AddOutcomeSet(name: "os") { id } }`)
+	assert.NotEmpty(t, res.Errors, "analyst keys can not edit")
+	res = query(t, h, key, `{ apiKeys { id } }`)
+	assert.NotEmpty(t, res.Errors, "keys can not manage keys")
+
+	res = query(t, h, admin, `{ apiKeys { id name scopes revoked } }`)
+	if assert.Len(t, res.Errors, 0) {
+		assert.Len(t, res.Data["apiKeys"], 1)
+	}
+
+	res = query(t, h, admin, `mutation { RevokeAPIKey(id: "`+id+`") }`)
+	assert.Len(t, res.Errors, 0)
+	res = query(t, h, key, `{ outcomesets { id } }`)
+	assert.NotEmpty(t, res.Errors, "revoked keys can not be used")
+}
@@ -0,0 +1,236 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	"github.com/impactasaurus/server/log"
+)
+
+const defaultAuditLimit = 100
+
+// erasures are recorded without their arguments or snapshots, as they would hold the data which was erased.
+// The erased data's existing entries are removed by the store.
+var erasures = map[string]bool{
+	"EraseBeneficiary": true,
+}
+
+// snapshotter returns the entity changed by a mutation, or nil if it does not exist.
+// It is called before the mutation with a nil result, then after with the mutation's result.
+type snapshotter func(p graphql.ResolveParams, u auth.User, result interface{}) interface{}
+
+func (v *v1) outcomeSetSnapshot(p graphql.ResolveParams, u auth.User, result interface{}) interface{} {
+	if id, ok := p.Args["outcomeSetID"].(string); ok {
+		if os, err := v.db.GetOutcomeSet(id, u); err == nil {
+			return os
+		}
+		return nil
+	}
+	if os, ok := result.(impact.OutcomeSet); ok {
+		return os
+	}
+	return nil
+}
+
+func (v *v1) meetingSnapshot(p graphql.ResolveParams, u auth.User, result interface{}) interface{} {
+	if id, ok := p.Args["meetingID"].(string); ok {
+		if m, err := v.db.GetMeeting(id, u); err == nil {
+			return m
+		}
+		return nil
+	}
+	switch r := result.(type) {
+	case impact.Meeting:
+		return r
+	case map[string]interface{}:
+		// remote meetings are returned alongside their JWT, which must not be recorded
+		return r["meeting"]
+	}
+	return nil
+}
+
+func (v *v1) beneficiarySnapshot(p graphql.ResolveParams, u auth.User, result interface{}) interface{} {
+	// renamed beneficiaries can no longer be found by the ID argument, so the result is preferred
+	if b, ok := result.(impact.Beneficiary); ok {
+		return b
+	}
+	if id, ok := p.Args["beneficiaryID"].(string); ok {
+		if b, err := v.db.GetBeneficiary(id, u); err == nil {
+			return b
+		}
+	}
+	return nil
+}
+
+func noSnapshot(p graphql.ResolveParams, u auth.User, result interface{}) interface{} {
+	return nil
+}
+
+func toJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		log.Error(err, nil)
+		return "null"
+	}
+	return string(b)
+}
+
+// audited records an audit entry for each successful call of the mutations
+func (v *v1) audited(mutations graphql.Fields, snapshot snapshotter) graphql.Fields {
+	for name, f := range mutations {
+		f.Resolve = v.auditResolver(name, snapshot, f.Resolve)
+	}
+	return mutations
+}
+
+func (v *v1) auditResolver(mutation string, snapshot snapshotter, fn graphql.FieldResolveFn) graphql.FieldResolveFn {
+	if erasures[mutation] {
+		snapshot = noSnapshot
+	}
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		u, err := auth.GetUser(p.Context)
+		if err != nil {
+			return fn(p)
+		}
+		if u.IsBeneficiary() {
+			// fn rejects beneficiaries who are acting outside of their assessment
+			if u, err = v.getAssessmentUser(u); err != nil {
+				return fn(p)
+			}
+		}
+		userOrg, err := u.Organisation()
+		if err != nil {
+			return fn(p)
+		}
+
+		before := snapshot(p, u, nil)
+		result, err := fn(p)
+		if err != nil {
+			return result, err
+		}
+		after := snapshot(p, u, result)
+		arguments := p.Args
+		if erasures[mutation] {
+			arguments = nil
+		}
+
+		// the mutation has been applied, so failing to record it is logged rather than returned
+		if _, err := v.db.NewAuditEntry(impact.AuditEntry{
+			OrganisationID: userOrg,
+			User:           u.UserID(),
+			Mutation:       mutation,
+			Arguments:      toJSON(arguments),
+			Before:         toJSON(before),
+			After:          toJSON(after),
+		}); err != nil {
+			log.Error(err, map[string]string{
+				"mutation": mutation,
+			})
+		}
+		return result, nil
+	}
+}
+
+func (v *v1) initAuditTypes() auditTypes {
+	return auditTypes{
+		auditEntryType: graphql.NewObject(graphql.ObjectConfig{
+			Name:        "AuditEntry",
+			Description: "A record of a change made to the organisation's data",
+			Fields: graphql.Fields{
+				"id": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.ID),
+					Description: "Unique ID",
+				},
+				"user": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the user who made the change",
+				},
+				"mutation": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The name of the mutation which made the change",
+				},
+				"arguments": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "JSON encoded arguments provided to the mutation",
+				},
+				"before": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "JSON encoded snapshot of the changed entity before the change, null if it did not exist",
+				},
+				"after": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "JSON encoded snapshot of the changed entity after the change",
+				},
+				"timestamp": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "When the change was made",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						obj, ok := p.Source.(impact.AuditEntry)
+						if !ok {
+							return nil, errors.New("Expecting an impact.AuditEntry")
+						}
+						return obj.Timestamp.Format(time.RFC3339), nil
+					},
+				},
+			},
+		}),
+	}
+}
+
+func (v *v1) getAuditQueries(audTypes auditTypes) graphql.Fields {
+	return graphql.Fields{
+		"auditLog": &graphql.Field{
+			Type:        graphql.NewList(audTypes.auditEntryType),
+			Description: "Get the changes made to the organisation's outcome sets, meetings and beneficiaries, newest first",
+			Args: graphql.FieldConfigArgument{
+				"mutation": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "Only include changes made by the named mutation",
+				},
+				"userID": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "Only include changes made by the user",
+				},
+				"start": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "Only include changes made at or after this ISO standard timestamp",
+				},
+				"end": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "Only include changes made at or before this ISO standard timestamp",
+				},
+				"limit": &graphql.ArgumentConfig{
+					Type:        graphql.Int,
+					Description: "The maximum number of changes to return, defaults to 100",
+				},
+			},
+			Resolve: roleRestrictedResolver(auth.ADMIN, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				filter := data.AuditFilter{
+					Mutation: getNullableString(p.Args, "mutation"),
+					UserID:   getNullableString(p.Args, "userID"),
+					Limit:    getNullableInt(p.Args, "limit"),
+				}
+				if filter.Limit <= 0 {
+					filter.Limit = defaultAuditLimit
+				}
+				var err error
+				if s, ok := getNullOrString(p.Args, "start"); ok {
+					if filter.Start, err = time.Parse(time.RFC3339, s); err != nil {
+						return nil, err
+					}
+				}
+				if s, ok := getNullOrString(p.Args, "end"); ok {
+					if filter.End, err = time.Parse(time.RFC3339, s); err != nil {
+						return nil, err
+					}
+				}
+				return v.db.GetAuditEntries(filter, u)
+			}),
+		},
+	}
+}
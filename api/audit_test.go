@@ -0,0 +1,59 @@
+package api_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/impactasaurus/server/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLog(t *testing.T) {
+	h, issue := setupOrgUsers(t)
+	admin := issue(auth.ADMIN)
+
+	res := query(t, h, admin, `mutation { AddOutcomeSet(name: "Wellbeing", description: "") { id } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	osID := res.Data["AddOutcomeSet"].(map[string]interface{})["id"].(string)
+	res = query(t, h, admin, `mutation { EditOutcomeSet(outcomeSetID: "`+osID+`", name: "Renamed", description: "") { id } }`)
+	assert.Len(t, res.Errors, 0)
+	res = query(t, h, admin, `mutation { EditOutcomeSet(outcomeSetID: "unknown", name: "Renamed", description: "") { id } }`)
+	assert.NotEmpty(t, res.Errors)
+
+	logQuery := `{ auditLog { user mutation arguments before after timestamp } }`
+	res = query(t, h, issue(auth.PRACTITIONER), logQuery)
+	assert.NotEmpty(t, res.Errors, "only admins can view the audit log")
+
+	res = query(t, h, admin, logQuery)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	entries := res.Data["auditLog"].([]interface{})
+	if !assert.Len(t, entries, 2, "failed mutations are not recorded") {
+		return
+	}
+	edit := entries[0].(map[string]interface{})
+	assert.Equal(t, "EditOutcomeSet", edit["mutation"])
+	assert.Equal(t, "admin-user", edit["user"])
+	assert.NotEmpty(t, edit["timestamp"])
+
+	before := map[string]interface{}{}
+	after := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal([]byte(edit["before"].(string)), &before))
+	assert.Nil(t, json.Unmarshal([]byte(edit["after"].(string)), &after))
+	assert.Equal(t, "Wellbeing", before["name"])
+	assert.Equal(t, "Renamed", after["name"])
+
+	add := entries[1].(map[string]interface{})
+	assert.Equal(t, "AddOutcomeSet", add["mutation"])
+	assert.Equal(t, "null", add["before"])
+	assert.JSONEq(t, `{"name": "Wellbeing", "description": ""}`, add["arguments"].(string))
+
+	res = query(t, h, admin, `{ auditLog(mutation: "AddOutcomeSet", limit: 5) { mutation } }`)
+	assert.Len(t, res.Errors, 0)
+	assert.Len(t, res.Data["auditLog"].([]interface{}), 1)
+	res = query(t, h, admin, `{ auditLog(start: "yesterday") { mutation } }`)
+	assert.NotEmpty(t, res.Errors)
+}
@@ -0,0 +1,336 @@
+package api
+
+import (
+	"errors"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/logic"
+)
+
+// assessmentUser is a beneficiary user acting within the organisation which owns their assessment
+type assessmentUser struct {
+	auth.User
+	organisation string
+}
+
+func (a *assessmentUser) Organisation() (string, error) {
+	return a.organisation, nil
+}
+
+func (a *assessmentUser) Organisations() []string {
+	return []string{a.organisation}
+}
+
+// getAssessmentUser returns the beneficiary user as a member of the organisation owning their assessment
+func (v *v1) getAssessmentUser(u auth.User) (auth.User, error) {
+	meeting, err := v.db.GetAssessmentMeeting(u)
+	if err != nil {
+		return nil, err
+	}
+	return &assessmentUser{
+		User:         u,
+		organisation: meeting.OrganisationID,
+	}, nil
+}
+
+// argMeetingID gets the meeting ID from the named argument
+func argMeetingID(name string) meetingIDGetter {
+	return func(p graphql.ResolveParams) (string, error) {
+		id, ok := p.Args[name].(string)
+		if !ok {
+			return "", errors.New("Expecting a meeting ID")
+		}
+		return id, nil
+	}
+}
+
+// sourceMeetingID gets the meeting ID from the meeting being resolved
+func sourceMeetingID(p graphql.ResolveParams) (string, error) {
+	obj, ok := p.Source.(impact.Meeting)
+	if !ok {
+		return "", errors.New("Expecting an impact.Meeting")
+	}
+	return obj.ID, nil
+}
+
+const (
+	exportJSON = "JSON"
+	exportCSV  = "CSV"
+)
+
+func (v *v1) initBeneficiaryTypes(meetTypes meetingTypes) beneficiaryTypes {
+	ret := beneficiaryTypes{}
+
+	ret.beneficiaryStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+		Name:        "BeneficiaryStatus",
+		Description: "Whether a beneficiary is part of the organisation's caseload",
+		Values: graphql.EnumValueConfigMap{
+			string(impact.ACTIVE): &graphql.EnumValueConfig{
+				Value:       impact.ACTIVE,
+				Description: "The beneficiary is being supported by the organisation",
+			},
+			string(impact.CLOSED): &graphql.EnumValueConfig{
+				Value:       impact.CLOSED,
+				Description: "The organisation is no longer supporting the beneficiary",
+			},
+		},
+	})
+
+	ret.beneficiaryType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Beneficiary",
+		Description: "A person supported by the organisation. Beneficiaries are created when they are first met",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.ID),
+				Description: "The ID the organisation uses to identify the beneficiary",
+			},
+			"created": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "When the beneficiary was first entered into the system",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					obj, ok := p.Source.(impact.Beneficiary)
+					if !ok {
+						return nil, errors.New("Expecting an impact.Beneficiary")
+					}
+					return obj.Created.Format(time.RFC3339), nil
+				},
+			},
+			"tags": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+				Description: "Tags used to group beneficiaries",
+			},
+			"status": &graphql.Field{
+				Type:        graphql.NewNonNull(ret.beneficiaryStatusEnum),
+				Description: "Whether the beneficiary is part of the organisation's caseload",
+			},
+			"meetings": &graphql.Field{
+				Type:        graphql.NewList(meetTypes.meetingType),
+				Description: "The meetings conducted with the beneficiary",
+				Resolve: roleRestrictedResolver(auth.ANALYST, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+					obj, ok := p.Source.(impact.Beneficiary)
+					if !ok {
+						return nil, errors.New("Expecting an impact.Beneficiary")
+					}
+					return v.db.GetMeetingsForBeneficiary(obj.ID, u)
+				}),
+			},
+		},
+	})
+
+	ret.exportFormatEnum = graphql.NewEnum(graphql.EnumConfig{
+		Name:        "ExportFormat",
+		Description: "The formats beneficiary data can be exported in",
+		Values: graphql.EnumValueConfigMap{
+			exportJSON: &graphql.EnumValueConfig{
+				Value:       exportJSON,
+				Description: "A JSON document containing the beneficiary's record and meetings",
+			},
+			exportCSV: &graphql.EnumValueConfig{
+				Value:       exportCSV,
+				Description: "A CSV file with a row per answer",
+			},
+		},
+	})
+
+	ret.beneficiaryExportType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "BeneficiaryExport",
+		Description: "Everything held about a beneficiary",
+		Fields: graphql.Fields{
+			"beneficiaryID": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.ID),
+				Description: "The ID of the beneficiary",
+			},
+			"format": &graphql.Field{
+				Type:        graphql.NewNonNull(ret.exportFormatEnum),
+				Description: "The format of the data",
+			},
+			"data": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "The exported data",
+			},
+		},
+	})
+
+	return ret
+}
+
+func (v *v1) getBeneficiaryQueries(meetTypes meetingTypes, benTypes beneficiaryTypes) graphql.Fields {
+	return graphql.Fields{
+		"beneficiary": &graphql.Field{
+			Type:        benTypes.beneficiaryType,
+			Description: "Get a beneficiary by ID",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the beneficiary",
+				},
+			},
+			Resolve: roleRestrictedResolver(auth.ANALYST, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				return v.db.GetBeneficiary(p.Args["id"].(string), u)
+			}),
+		},
+		"beneficiaries": &graphql.Field{
+			Type:        graphql.NewList(benTypes.beneficiaryType),
+			Description: "Get the organisation's beneficiaries, ordered by ID",
+			Args: graphql.FieldConfigArgument{
+				"status": &graphql.ArgumentConfig{
+					Type:        benTypes.beneficiaryStatusEnum,
+					Description: "Only include beneficiaries with this status",
+				},
+				"tag": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "Only include beneficiaries with this tag",
+				},
+			},
+			Resolve: roleRestrictedResolver(auth.ANALYST, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				all, err := v.db.GetBeneficiaries(u)
+				if err != nil {
+					return nil, err
+				}
+				status, filterStatus := p.Args["status"].(impact.BeneficiaryStatus)
+				tag, filterTag := getNullOrString(p.Args, "tag")
+				results := []impact.Beneficiary{}
+				for _, b := range all {
+					if filterStatus && b.Status != status {
+						continue
+					}
+					if filterTag && !b.HasTag(tag) {
+						continue
+					}
+					results = append(results, b)
+				}
+				return results, nil
+			}),
+		},
+		"assessment": &graphql.Field{
+			Type:        meetTypes.meetingType,
+			Description: "Get the meeting a beneficiary user has been asked to complete",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				u, err := auth.GetUser(p.Context)
+				if err != nil {
+					return nil, err
+				}
+				if !u.IsBeneficiary() {
+					return nil, errors.New("Only beneficiary users have an assessment")
+				}
+				scopedUser, err := v.getAssessmentUser(u)
+				if err != nil {
+					return nil, err
+				}
+				id, _ := u.GetAssessmentScope()
+				return v.db.GetMeeting(id, scopedUser)
+			},
+		},
+	}
+}
+
+func (v *v1) getBeneficiaryMutations(benTypes beneficiaryTypes) graphql.Fields {
+	return graphql.Fields{
+		"EditBeneficiary": &graphql.Field{
+			Type:        benTypes.beneficiaryType,
+			Description: "Edit the tags and status of a beneficiary",
+			Args: graphql.FieldConfigArgument{
+				"beneficiaryID": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the beneficiary",
+				},
+				"tags": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+					Description: "Tags used to group beneficiaries, replacing the existing tags",
+				},
+				"status": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(benTypes.beneficiaryStatusEnum),
+					Description: "Whether the beneficiary is part of the organisation's caseload",
+				},
+			},
+			Resolve: roleRestrictedResolver(auth.PRACTITIONER, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				id := p.Args["beneficiaryID"].(string)
+				tags := []string{}
+				for _, t := range p.Args["tags"].([]interface{}) {
+					tags = append(tags, t.(string))
+				}
+				status := p.Args["status"].(impact.BeneficiaryStatus)
+				return v.db.EditBeneficiary(id, logic.NormaliseTags(tags), status, u)
+			}),
+		},
+		"RenameBeneficiary": &graphql.Field{
+			Type:        benTypes.beneficiaryType,
+			Description: "Change the ID of a beneficiary, their meetings are updated to use the new ID",
+			Args: graphql.FieldConfigArgument{
+				"beneficiaryID": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The current ID of the beneficiary",
+				},
+				"newBeneficiaryID": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The new ID of the beneficiary, which must not already be in use",
+				},
+			},
+			Resolve: roleRestrictedResolver(auth.PRACTITIONER, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				id := p.Args["beneficiaryID"].(string)
+				newID := p.Args["newBeneficiaryID"].(string)
+				if err := logic.ValidateBeneficiaryID(newID); err != nil {
+					return nil, err
+				}
+				return v.db.RenameBeneficiary(id, newID, u)
+			}),
+		},
+		"ExportBeneficiary": &graphql.Field{
+			Type:        benTypes.beneficiaryExportType,
+			Description: "Export everything held about a beneficiary, including deleted meetings, to fulfil a subject access request",
+			Args: graphql.FieldConfigArgument{
+				"beneficiaryID": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the beneficiary",
+				},
+				"format": &graphql.ArgumentConfig{
+					Type:         benTypes.exportFormatEnum,
+					Description:  "The format of the export, defaults to JSON",
+					DefaultValue: exportJSON,
+				},
+			},
+			Resolve: roleRestrictedResolver(auth.ADMIN, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				id := p.Args["beneficiaryID"].(string)
+				format := p.Args["format"].(string)
+				export, err := logic.ExportBeneficiary(id, v.db, u)
+				if err != nil {
+					return nil, err
+				}
+				var out []byte
+				if format == exportCSV {
+					out, err = export.CSV()
+				} else {
+					out, err = export.JSON()
+				}
+				if err != nil {
+					return nil, err
+				}
+				return map[string]interface{}{
+					"beneficiaryID": id,
+					"format":        format,
+					"data":          string(out),
+				}, nil
+			}),
+		},
+		"EraseBeneficiary": &graphql.Field{
+			Type:        graphql.ID,
+			Description: "Permanently erase a beneficiary and all of their meetings, to fulfil a request for erasure. Returns the ID of the erased beneficiary",
+			Args: graphql.FieldConfigArgument{
+				"beneficiaryID": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the beneficiary",
+				},
+			},
+			Resolve: roleRestrictedResolver(auth.ADMIN, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				id := p.Args["beneficiaryID"].(string)
+				if err := v.db.EraseBeneficiary(id, u); err != nil {
+					return nil, err
+				}
+				return id, nil
+			}),
+		},
+	}
+}
@@ -0,0 +1,277 @@
+package api_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/api"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	"github.com/impactasaurus/server/data/memory"
+	"github.com/impactasaurus/server/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+const aud = "test-aud"
+const iss = "test-iss"
+
+type gqlResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func setupAssessment(t *testing.T) (http.Handler, data.Base, auth.User, impact.Meeting, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := auth.NewKeyring(key)
+	gen := auth.NewBeneficiaryJWTGenerator(aud, iss, keys)
+	db := memory.New(impact.Organisation{ID: "org1", Name: "Org"})
+
+	ctrl := gomock.NewController(t)
+	u := mock.NewMockUser(ctrl)
+	u.EXPECT().Organisation().Return("org1", nil).AnyTimes()
+	u.EXPECT().UserID().Return("user1").AnyTimes()
+	u.EXPECT().IsBeneficiary().Return(false).AnyTimes()
+	u.EXPECT().GetAssessmentScope().Return("", false).AnyTimes()
+
+	os, err := db.NewOutcomeSet("os", "", u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.NewQuestion(os.ID, "How are you?", "", impact.LIKERT, map[string]interface{}{
+		"minValue": 1,
+		"maxValue": 5,
+	}, u); err != nil {
+		t.Fatal(err)
+	}
+	meeting, err := db.NewMeeting("ben1", os.ID, time.Now(), u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwt, err := gen.GenerateBeneficiaryJWT("ben1", meeting.ID, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := api.NewV1(db, gen, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return auth.Middleware(h, auth.NewBeneficiaryAuthenticator(aud, iss, keys, db)), db, u, meeting, jwt
+}
+
+func query(t *testing.T, h http.Handler, jwt, q string) gqlResponse {
+	body, err := json.Marshal(map[string]string{"query": q})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest("POST", "/v1/graphql", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	res := gqlResponse{}
+	if err := json.NewDecoder(rec.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestBeneficiaryCanCompleteAssessment(t *testing.T) {
+	h, db, u, meeting, jwt := setupAssessment(t)
+
+	res := query(t, h, jwt, `{ assessment { id outcomeSet { questions { id } } } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	assessment := res.Data["assessment"].(map[string]interface{})
+	assert.Equal(t, meeting.ID, assessment["id"])
+	questions := assessment["outcomeSet"].(map[string]interface{})["questions"].([]interface{})
+	qID := questions[0].(map[string]interface{})["id"].(string)
+
+	res = query(t, h, jwt, `mutation { AddLikertAnswer(meetingID: "`+meeting.ID+`", questionID: "`+qID+`", value: 3) { id } }`)
+	assert.Len(t, res.Errors, 0)
+	res = query(t, h, jwt, `mutation { CompleteMeeting(meetingID: "`+meeting.ID+`") { status } }`)
+	assert.Len(t, res.Errors, 0)
+
+	stored, err := db.GetMeeting(meeting.ID, u)
+	assert.Nil(t, err)
+	assert.True(t, stored.IsComplete())
+
+	res = query(t, h, jwt, `{ assessment { id } }`)
+	assert.NotEmpty(t, res.Errors, "the JWT should be revoked once the meeting is completed")
+}
+
+func TestBeneficiaryDeniedOutsideAssessment(t *testing.T) {
+	h, db, u, meeting, jwt := setupAssessment(t)
+	other, err := db.NewMeeting("ben2", meeting.OutcomeSetID, time.Now(), u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, q := range []string{
+		`{ outcomesets { id } }`,
+		`{ meeting(id: "` + other.ID + `") { id } }`,
+		`{ meetings(beneficiary: "ben1") { id } }`,
+		`mutation { DeleteMeeting(meetingID: "` + meeting.ID + `") }`,
+		`mutation { AddLikertAnswer(meetingID: "` + other.ID + `", questionID: "q", value: 3) { id } }`,
+	} {
+		res := query(t, h, jwt, q)
+		assert.NotEmpty(t, res.Errors, q)
+	}
+}
+
+func TestBeneficiaryCaseload(t *testing.T) {
+	h, issue := setupOrgUsers(t)
+	practitioner := issue(auth.PRACTITIONER)
+
+	for _, ben := range []string{"ben2", "ben1"} {
+		res := query(t, h, practitioner, `mutation { AddMeeting(beneficiaryID: "`+ben+`", outcomeSetID: "os", conducted: "2017-10-01T12:00:00Z") { id } }`)
+		if !assert.Len(t, res.Errors, 0) {
+			return
+		}
+	}
+
+	res := query(t, h, issue(auth.ANALYST), `mutation { EditBeneficiary(beneficiaryID: "ben1", tags: ["a"], status: closed) { id } }`)
+	assert.NotEmpty(t, res.Errors, "analysts can not edit beneficiaries")
+	res = query(t, h, practitioner, `mutation { EditBeneficiary(beneficiaryID: "ben1", tags: [" a ", "a", "b"], status: closed) { tags status } }`)
+	if assert.Len(t, res.Errors, 0) {
+		edited := res.Data["EditBeneficiary"].(map[string]interface{})
+		assert.Equal(t, []interface{}{"a", "b"}, edited["tags"])
+		assert.Equal(t, "closed", edited["status"])
+	}
+
+	res = query(t, h, issue(auth.ANALYST), `{ beneficiaries { id status meetings { id } } }`)
+	if assert.Len(t, res.Errors, 0) {
+		bens := res.Data["beneficiaries"].([]interface{})
+		if assert.Len(t, bens, 2) {
+			assert.Equal(t, "ben1", bens[0].(map[string]interface{})["id"])
+			assert.Len(t, bens[0].(map[string]interface{})["meetings"], 1)
+		}
+	}
+	res = query(t, h, practitioner, `{ active: beneficiaries(status: active) { id } tagged: beneficiaries(tag: "a") { id } }`)
+	if assert.Len(t, res.Errors, 0) {
+		assert.Equal(t, []interface{}{map[string]interface{}{"id": "ben2"}}, res.Data["active"])
+		assert.Equal(t, []interface{}{map[string]interface{}{"id": "ben1"}}, res.Data["tagged"])
+	}
+
+	res = query(t, h, practitioner, `mutation { RenameBeneficiary(beneficiaryID: "ben1", newBeneficiaryID: "ben2") { id } }`)
+	assert.NotEmpty(t, res.Errors)
+	res = query(t, h, practitioner, `mutation { RenameBeneficiary(beneficiaryID: "ben1", newBeneficiaryID: " ") { id } }`)
+	assert.NotEmpty(t, res.Errors)
+	res = query(t, h, practitioner, `mutation { RenameBeneficiary(beneficiaryID: "ben1", newBeneficiaryID: "ben3") { id meetings { beneficiary } } }`)
+	if assert.Len(t, res.Errors, 0) {
+		renamed := res.Data["RenameBeneficiary"].(map[string]interface{})
+		assert.Equal(t, "ben3", renamed["id"])
+		assert.Equal(t, []interface{}{map[string]interface{}{"beneficiary": "ben3"}}, renamed["meetings"])
+	}
+	res = query(t, h, practitioner, `{ beneficiary(id: "ben1") { id } }`)
+	assert.NotEmpty(t, res.Errors)
+}
+
+func TestBeneficiaryExportAndErasure(t *testing.T) {
+	h, issue := setupOrgUsers(t)
+	admin := issue(auth.ADMIN)
+
+	res := query(t, h, admin, `mutation { AddOutcomeSet(name: "Wellbeing", description: "") { id } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	osID := res.Data["AddOutcomeSet"].(map[string]interface{})["id"].(string)
+	res = query(t, h, admin, `mutation { AddLikertQuestion(outcomeSetID: "`+osID+`", question: "How happy are you?", minValue: 1, maxValue: 5) { id questions { id } } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	questions := res.Data["AddLikertQuestion"].(map[string]interface{})["questions"].([]interface{})
+	qID := questions[0].(map[string]interface{})["id"].(string)
+	res = query(t, h, admin, `mutation { AddMeeting(beneficiaryID: "ben", outcomeSetID: "`+osID+`", conducted: "2017-10-01T12:00:00Z") { id } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	meetingID := res.Data["AddMeeting"].(map[string]interface{})["id"].(string)
+	res = query(t, h, admin, `mutation { AddLikertAnswer(meetingID: "`+meetingID+`", questionID: "`+qID+`", value: 4) { id } }`)
+	assert.Len(t, res.Errors, 0)
+
+	res = query(t, h, issue(auth.PRACTITIONER), `mutation { ExportBeneficiary(beneficiaryID: "ben") { data } }`)
+	assert.NotEmpty(t, res.Errors, "only admins can export beneficiary data")
+
+	res = query(t, h, admin, `mutation { ExportBeneficiary(beneficiaryID: "ben") { format data } }`)
+	if assert.Len(t, res.Errors, 0) {
+		export := res.Data["ExportBeneficiary"].(map[string]interface{})
+		assert.Equal(t, "JSON", export["format"])
+		decoded := struct {
+			Beneficiary struct {
+				ID string `json:"id"`
+			} `json:"beneficiary"`
+			Meetings []struct {
+				OutcomeSet string `json:"outcomeSet"`
+				Answers    []struct {
+					Question string  `json:"question"`
+					Answer   float64 `json:"answer"`
+				} `json:"answers"`
+			} `json:"meetings"`
+		}{}
+		assert.Nil(t, json.Unmarshal([]byte(export["data"].(string)), &decoded))
+		assert.Equal(t, "ben", decoded.Beneficiary.ID)
+		if assert.Len(t, decoded.Meetings, 1) && assert.Len(t, decoded.Meetings[0].Answers, 1) {
+			assert.Equal(t, "Wellbeing", decoded.Meetings[0].OutcomeSet)
+			assert.Equal(t, "How happy are you?", decoded.Meetings[0].Answers[0].Question)
+			assert.Equal(t, float64(4), decoded.Meetings[0].Answers[0].Answer)
+		}
+	}
+
+	res = query(t, h, admin, `mutation { ExportBeneficiary(beneficiaryID: "ben", format: CSV) { data } }`)
+	if assert.Len(t, res.Errors, 0) {
+		lines := strings.Split(strings.TrimSpace(res.Data["ExportBeneficiary"].(map[string]interface{})["data"].(string)), "\n")
+		if assert.Len(t, lines, 2) {
+			assert.True(t, strings.HasPrefix(lines[1], "ben,"+meetingID+","))
+			assert.True(t, strings.HasSuffix(lines[1], ",How happy are you?,4"))
+		}
+	}
+
+	res = query(t, h, issue(auth.PRACTITIONER), `mutation { EraseBeneficiary(beneficiaryID: "ben") }`)
+	assert.NotEmpty(t, res.Errors, "only admins can erase beneficiaries")
+	res = query(t, h, admin, `mutation { EraseBeneficiary(beneficiaryID: "ben") }`)
+	if assert.Len(t, res.Errors, 0) {
+		assert.Equal(t, "ben", res.Data["EraseBeneficiary"])
+	}
+	res = query(t, h, admin, `{ meeting(id: "`+meetingID+`") { id } }`)
+	assert.NotEmpty(t, res.Errors)
+	res = query(t, h, admin, `mutation { ExportBeneficiary(beneficiaryID: "ben") { data } }`)
+	assert.NotEmpty(t, res.Errors)
+
+	res = query(t, h, admin, `{ auditLog(mutation: "EraseBeneficiary") { user arguments before after } }`)
+	if assert.Len(t, res.Errors, 0) {
+		entries := res.Data["auditLog"].([]interface{})
+		if assert.Len(t, entries, 1) {
+			entry := entries[0].(map[string]interface{})
+			assert.Equal(t, "admin-user", entry["user"])
+			assert.Equal(t, "null", entry["arguments"])
+			assert.Equal(t, "null", entry["before"])
+			assert.Equal(t, "null", entry["after"])
+		}
+	}
+	res = query(t, h, admin, `{ auditLog { mutation arguments before after } }`)
+	if assert.Len(t, res.Errors, 0) {
+		for _, e := range res.Data["auditLog"].([]interface{}) {
+			entry := e.(map[string]interface{})
+			for _, field := range []string{"arguments", "before", "after"} {
+				assert.NotContains(t, entry[field], `"ben"`, entry["mutation"])
+				assert.NotContains(t, entry[field], meetingID, entry["mutation"])
+			}
+		}
+	}
+}
@@ -25,3 +25,8 @@ func getNullableInt(input map[string]interface{}, key string) int {
 	}
 	return s
 }
+
+func getNullableBool(input map[string]interface{}, key string) bool {
+	b, _ := input[key].(bool)
+	return b
+}
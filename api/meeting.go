@@ -30,6 +30,14 @@ func (v *v1) initMeetingTypes(orgTypes organisationTypes, osTypes outcomeSetType
 			switch obj.Type {
 			case impact.INT:
 				return ret.intAnswer
+			case impact.FLOAT:
+				return ret.floatAnswer
+			case impact.STRING:
+				return ret.stringAnswer
+			case impact.BOOL:
+				return ret.boolAnswer
+			case impact.STRING_LIST:
+				return ret.stringListAnswer
 			default:
 				return ret.intAnswer
 			}
@@ -65,6 +73,66 @@ func (v *v1) initMeetingTypes(orgTypes organisationTypes, osTypes outcomeSetType
 		},
 	})
 
+	ret.floatAnswer = newAnswerObject("FloatAnswer", "Answer containing a number", ret.answerInterface, &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.Float),
+		Description: "The provided numeric answer",
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			obj, ok := p.Source.(impact.Answer)
+			if !ok {
+				return nil, errors.New("Expecting an impact.Answer")
+			}
+			return obj.ToFloat()
+		},
+	})
+
+	ret.stringAnswer = newAnswerObject("StringAnswer", "Answer containing text, such as free text or a single choice", ret.answerInterface, &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.String),
+		Description: "The provided string answer",
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			obj, ok := p.Source.(impact.Answer)
+			if !ok {
+				return nil, errors.New("Expecting an impact.Answer")
+			}
+			s, ok := obj.Answer.(string)
+			if !ok {
+				return nil, errors.New("Expected a string value")
+			}
+			return s, nil
+		},
+	})
+
+	ret.boolAnswer = newAnswerObject("BoolAnswer", "Answer containing yes or no", ret.answerInterface, &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.Boolean),
+		Description: "The provided boolean answer",
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			obj, ok := p.Source.(impact.Answer)
+			if !ok {
+				return nil, errors.New("Expecting an impact.Answer")
+			}
+			b, ok := obj.Answer.(bool)
+			if !ok {
+				return nil, errors.New("Expected a bool value")
+			}
+			return b, nil
+		},
+	})
+
+	ret.stringListAnswer = newAnswerObject("StringListAnswer", "Answer containing a list of strings, such as the choices made", ret.answerInterface, &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+		Description: "The provided string list answer",
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			obj, ok := p.Source.(impact.Answer)
+			if !ok {
+				return nil, errors.New("Expecting an impact.Answer")
+			}
+			l := obj.ToStrings()
+			if l == nil {
+				return nil, errors.New("Expected a string list value")
+			}
+			return l, nil
+		},
+	})
+
 	ret.categoryAggregate = graphql.NewObject(graphql.ObjectConfig{
 		Name:        "CategoryAggregate",
 		Description: "An aggregation of answers to the category level",
@@ -91,6 +159,25 @@ func (v *v1) initMeetingTypes(orgTypes organisationTypes, osTypes outcomeSetType
 		},
 	})
 
+	ret.meetingStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+		Name:        "MeetingStatus",
+		Description: "The stages of a meeting's lifecycle",
+		Values: graphql.EnumValueConfigMap{
+			string(impact.IN_PROGRESS): &graphql.EnumValueConfig{
+				Value:       impact.IN_PROGRESS,
+				Description: "The meeting is being conducted",
+			},
+			string(impact.COMPLETED): &graphql.EnumValueConfig{
+				Value:       impact.COMPLETED,
+				Description: "All questions have been answered, the meeting is included in reports",
+			},
+			string(impact.ABANDONED): &graphql.EnumValueConfig{
+				Value:       impact.ABANDONED,
+				Description: "The meeting will not be completed",
+			},
+		},
+	})
+
 	ret.meetingType = graphql.NewObject(graphql.ObjectConfig{
 		Name:        "Meeting",
 		Description: "A set of answers for an outcome set",
@@ -113,15 +200,19 @@ func (v *v1) initMeetingTypes(orgTypes organisationTypes, osTypes outcomeSetType
 			},
 			"outcomeSet": &graphql.Field{
 				Type:        graphql.NewNonNull(osTypes.outcomeSetType),
-				Description: "The outcome set answered",
-				Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				Description: "The version of the outcome set answered",
+				Resolve: v.assessmentRestrictedResolver(auth.ANALYST, sourceMeetingID, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 					obj, ok := p.Source.(impact.Meeting)
 					if !ok {
 						return nil, errors.New("Expecting an impact.Meeting")
 					}
-					return v.db.GetOutcomeSet(obj.OutcomeSetID, u)
+					return logic.MeetingOutcomeSet(obj, v.db, u)
 				}),
 			},
+			"outcomeSetVersion": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.Int),
+				Description: "The published version of the outcome set the meeting was conducted against, 0 if the outcome set did not exist when the meeting was created",
+			},
 			"organisationID": &graphql.Field{
 				Type:        graphql.NewNonNull(graphql.String),
 				Description: "Organisation's unique ID",
@@ -129,7 +220,7 @@ func (v *v1) initMeetingTypes(orgTypes organisationTypes, osTypes outcomeSetType
 			"organisation": &graphql.Field{
 				Type:        graphql.NewNonNull(orgTypes.organisationType),
 				Description: "The owning organisation of the outcome set",
-				Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				Resolve: v.assessmentRestrictedResolver(auth.ANALYST, sourceMeetingID, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 					obj, ok := p.Source.(impact.Meeting)
 					if !ok {
 						return nil, errors.New("Expecting an impact.Meeting")
@@ -144,12 +235,12 @@ func (v *v1) initMeetingTypes(orgTypes organisationTypes, osTypes outcomeSetType
 			"aggregates": &graphql.Field{
 				Type:        ret.aggregates,
 				Description: "Aggregations of the meeting's answers",
-				Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				Resolve: v.assessmentRestrictedResolver(auth.ANALYST, sourceMeetingID, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 					obj, ok := p.Source.(impact.Meeting)
 					if !ok {
 						return nil, errors.New("Expecting an impact.Meeting")
 					}
-					os, err := v.db.GetOutcomeSet(obj.OutcomeSetID, u)
+					os, err := logic.MeetingOutcomeSet(obj, v.db, u)
 					if err != nil {
 						return nil, err
 					}
@@ -195,6 +286,10 @@ func (v *v1) initMeetingTypes(orgTypes organisationTypes, osTypes outcomeSetType
 					return obj.Modified.Format(time.RFC3339), nil
 				},
 			},
+			"status": &graphql.Field{
+				Type:        graphql.NewNonNull(ret.meetingStatusEnum),
+				Description: "The stage of the meeting's lifecycle. Only completed meetings are included in reports",
+			},
 		},
 	})
 
@@ -210,12 +305,77 @@ func (v *v1) initMeetingTypes(orgTypes organisationTypes, osTypes outcomeSetType
 				Type:        graphql.NewNonNull(ret.meetingType),
 				Description: "The meeting",
 			},
+			"shortCode": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "A short code which can be shared with the beneficiary and exchanged for a JWT at /v1/code/{shortCode}",
+			},
 		},
 	})
 
 	return ret
 }
 
+func newAnswerObject(name, description string, answerInterface *graphql.Interface, answer *graphql.Field) *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name:        name,
+		Description: description,
+		Interfaces: []*graphql.Interface{
+			answerInterface,
+		},
+		Fields: graphql.Fields{
+			"questionID": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "The ID of the question answered",
+			},
+			"answer": answer,
+		},
+	})
+}
+
+// answerArgs returns the arguments of an answer mutation, value describes the answer itself
+func answerArgs(valueName string, value *graphql.ArgumentConfig) graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"meetingID": &graphql.ArgumentConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the meeting the answer is associated with",
+		},
+		"questionID": &graphql.ArgumentConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the question being answered",
+		},
+		valueName: value,
+	}
+}
+
+// addAnswerResolver validates and stores the answer built from the mutation's arguments
+func (v *v1) addAnswerResolver(answerType impact.AnswerType, value func(args map[string]interface{}) interface{}) graphql.FieldResolveFn {
+	return v.assessmentRestrictedResolver(auth.PRACTITIONER, argMeetingID("meetingID"), func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+		meetingID := p.Args["meetingID"].(string)
+		questionID := p.Args["questionID"].(string)
+		answer := impact.Answer{
+			QuestionID: questionID,
+			Type:       answerType,
+			Answer:     value(p.Args),
+		}
+		meeting, err := v.db.GetMeeting(meetingID, u)
+		if err != nil {
+			return nil, err
+		}
+		os, err := logic.MeetingOutcomeSet(meeting, v.db, u)
+		if err != nil {
+			return nil, err
+		}
+		if err := logic.ValidateAnswer(os, answer); err != nil {
+			return nil, err
+		}
+		return v.db.NewAnswer(meetingID, answer, u)
+	})
+}
+
+func argValue(args map[string]interface{}) interface{} {
+	return args["value"]
+}
+
 func (v *v1) getMeetingQueries(meetTypes meetingTypes) graphql.Fields {
 	return graphql.Fields{
 		"meeting": &graphql.Field{
@@ -227,7 +387,7 @@ func (v *v1) getMeetingQueries(meetTypes meetingTypes) graphql.Fields {
 					Type:        graphql.NewNonNull(graphql.String),
 				},
 			},
-			Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+			Resolve: v.assessmentRestrictedResolver(auth.ANALYST, argMeetingID("id"), func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 				return v.db.GetMeeting(p.Args["id"].(string), u)
 			}),
 		},
@@ -240,7 +400,7 @@ func (v *v1) getMeetingQueries(meetTypes meetingTypes) graphql.Fields {
 					Type:        graphql.NewNonNull(graphql.String),
 				},
 			},
-			Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+			Resolve: roleRestrictedResolver(auth.ANALYST, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 				return v.db.GetMeetingsForBeneficiary(p.Args["beneficiary"].(string), u)
 			}),
 		},
@@ -266,7 +426,7 @@ func (v *v1) getMeetingMutations(meetTypes meetingTypes) graphql.Fields {
 					Description: "The time and date when the meeting was conducted. Should be ISO standard timestamp",
 				},
 			},
-			Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+			Resolve: roleRestrictedResolver(auth.PRACTITIONER, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 				beneficiaryID := p.Args["beneficiaryID"].(string)
 				outcomeSetID := p.Args["outcomeSetID"].(string)
 				conducted := p.Args["conducted"].(string)
@@ -294,7 +454,7 @@ func (v *v1) getMeetingMutations(meetTypes meetingTypes) graphql.Fields {
 					Description: "Number of days the beneficiary has to complete the assessment",
 				},
 			},
-			Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+			Resolve: roleRestrictedResolver(auth.PRACTITIONER, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 				beneficiaryID := p.Args["beneficiaryID"].(string)
 				outcomeSetID := p.Args["outcomeSetID"].(string)
 				daysToComplete := p.Args["daysToComplete"].(int)
@@ -302,44 +462,186 @@ func (v *v1) getMeetingMutations(meetTypes meetingTypes) graphql.Fields {
 				if err != nil {
 					return nil, err
 				}
-				jwt, err := v.authGen.GenerateBeneficiaryJWT(beneficiaryID, meeting.ID, (time.Hour*24)*time.Duration(daysToComplete))
+				expiry := (time.Hour * 24) * time.Duration(daysToComplete)
+				// the stored beneficiary may be a pseudonym, which keeps the beneficiary's ID out of their JWT
+				jwt, err := v.authGen.GenerateBeneficiaryJWT(meeting.Beneficiary, meeting.ID, expiry)
+				if err != nil {
+					return nil, err
+				}
+				sc, err := logic.NewShortCode(meeting.ID, meeting.Beneficiary, time.Now().Add(expiry), v.db, u)
 				if err != nil {
 					return nil, err
 				}
 				return map[string]interface{}{
-					"JWT":     jwt,
-					"meeting": meeting,
+					"JWT":       jwt,
+					"meeting":   meeting,
+					"shortCode": sc.Code,
 				}, nil
 			}),
 		},
+		"RevokeRemoteMeeting": &graphql.Field{
+			Type:        graphql.ID,
+			Description: "Prevents the JWTs issued for a remote meeting from being used. Returns the ID of the meeting",
+			Args: graphql.FieldConfigArgument{
+				"meetingID": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.ID),
+					Description: "The ID of the remote meeting",
+				},
+			},
+			Resolve: roleRestrictedResolver(auth.PRACTITIONER, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				id := p.Args["meetingID"].(string)
+				if err := v.db.RevokeAssessment(id, u); err != nil {
+					return nil, err
+				}
+				return id, nil
+			}),
+		},
 		"AddLikertAnswer": &graphql.Field{
 			Type:        meetTypes.meetingType,
-			Description: "Provide an answer for a Likert Scale question",
+			Description: "Provide an answer for a Likert Scale question, replacing any previous answer to the question",
+			Args: answerArgs("value", &graphql.ArgumentConfig{
+				Type:        graphql.NewNonNull(graphql.Int),
+				Description: "The value given for the particular likert scale",
+			}),
+			Resolve: v.addAnswerResolver(impact.INT, argValue),
+		},
+		"AddFreeTextAnswer": &graphql.Field{
+			Type:        meetTypes.meetingType,
+			Description: "Provide an answer for a free text question, replacing any previous answer to the question",
+			Args: answerArgs("value", &graphql.ArgumentConfig{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "The text given",
+			}),
+			Resolve: v.addAnswerResolver(impact.STRING, argValue),
+		},
+		"AddYesNoAnswer": &graphql.Field{
+			Type:        meetTypes.meetingType,
+			Description: "Provide an answer for a yes/no question, replacing any previous answer to the question",
+			Args: answerArgs("value", &graphql.ArgumentConfig{
+				Type:        graphql.NewNonNull(graphql.Boolean),
+				Description: "True if the answer was yes",
+			}),
+			Resolve: v.addAnswerResolver(impact.BOOL, argValue),
+		},
+		"AddSingleChoiceAnswer": &graphql.Field{
+			Type:        meetTypes.meetingType,
+			Description: "Provide an answer for a single choice question, replacing any previous answer to the question",
+			Args: answerArgs("value", &graphql.ArgumentConfig{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "The choice made, must be one of the question's choices",
+			}),
+			Resolve: v.addAnswerResolver(impact.STRING, argValue),
+		},
+		"AddMultiChoiceAnswer": &graphql.Field{
+			Type:        meetTypes.meetingType,
+			Description: "Provide an answer for a multi choice question, replacing any previous answer to the question",
+			Args: answerArgs("values", &graphql.ArgumentConfig{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+				Description: "The choices made, each must be one of the question's choices. May be empty if nothing was chosen",
+			}),
+			Resolve: v.addAnswerResolver(impact.STRING_LIST, func(args map[string]interface{}) interface{} {
+				values := []string{}
+				for _, c := range args["values"].([]interface{}) {
+					values = append(values, c.(string))
+				}
+				return values
+			}),
+		},
+		"AddNumericAnswer": &graphql.Field{
+			Type:        meetTypes.meetingType,
+			Description: "Provide an answer for a numeric question, replacing any previous answer to the question",
+			Args: answerArgs("value", &graphql.ArgumentConfig{
+				Type:        graphql.NewNonNull(graphql.Float),
+				Description: "The number given",
+			}),
+			Resolve: v.addAnswerResolver(impact.FLOAT, argValue),
+		},
+		"EditMeeting": &graphql.Field{
+			Type:        meetTypes.meetingType,
+			Description: "Edit the details of a meeting",
 			Args: graphql.FieldConfigArgument{
 				"meetingID": &graphql.ArgumentConfig{
 					Type:        graphql.NewNonNull(graphql.String),
-					Description: "The ID of the meeting the answer is associated with",
+					Description: "The ID of the meeting",
 				},
-				"questionID": &graphql.ArgumentConfig{
+				"beneficiaryID": &graphql.ArgumentConfig{
 					Type:        graphql.NewNonNull(graphql.String),
-					Description: "The ID of the question being answered",
+					Description: "The ID associated with the beneficiary being interviewed",
 				},
-				"value": &graphql.ArgumentConfig{
-					Type:        graphql.NewNonNull(graphql.Int),
-					Description: "The value given for the particular likert scale",
+				"conducted": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The time and date when the meeting was conducted. Should be ISO standard timestamp",
 				},
 			},
-			Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+			Resolve: roleRestrictedResolver(auth.PRACTITIONER, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 				meetingID := p.Args["meetingID"].(string)
-				questionID := p.Args["questionID"].(string)
-				value := p.Args["value"].(int)
-				return v.db.NewAnswer(meetingID, impact.Answer{
-					QuestionID: questionID,
-					Type:       impact.INT,
-					Answer:     value,
-				}, u)
+				beneficiaryID := p.Args["beneficiaryID"].(string)
+				conducted := p.Args["conducted"].(string)
+				parsedConducted, err := time.Parse(time.RFC3339, conducted)
+				if err != nil {
+					return nil, err
+				}
+				if _, err := v.db.GetMeeting(meetingID, u); err != nil {
+					return nil, err
+				}
+				return v.db.EditMeeting(meetingID, beneficiaryID, parsedConducted, u)
+			}),
+		},
+		"CompleteMeeting": &graphql.Field{
+			Type:        meetTypes.meetingType,
+			Description: "Mark a meeting as completed. All active questions of the outcome set must have been answered",
+			Args: graphql.FieldConfigArgument{
+				"meetingID": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the meeting",
+				},
+			},
+			Resolve: v.assessmentRestrictedResolver(auth.PRACTITIONER, argMeetingID("meetingID"), func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				meetingID := p.Args["meetingID"].(string)
+				meeting, err := logic.CompleteMeeting(meetingID, v.db, u)
+				if err != nil {
+					return nil, err
+				}
+				if v.revokeOnCompletion {
+					if err := v.db.RevokeAssessment(meetingID, u); err != nil {
+						return nil, err
+					}
+				}
+				return meeting, nil
+			}),
+		},
+		"AbandonMeeting": &graphql.Field{
+			Type:        meetTypes.meetingType,
+			Description: "Mark a meeting which will not be completed, excluding it from reports",
+			Args: graphql.FieldConfigArgument{
+				"meetingID": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the meeting",
+				},
+			},
+			Resolve: roleRestrictedResolver(auth.PRACTITIONER, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				return logic.AbandonMeeting(p.Args["meetingID"].(string), v.db, u)
+			}),
+		},
+		"DeleteMeeting": &graphql.Field{
+			Type:        graphql.ID,
+			Description: "Deletes a meeting and returns the ID of the deleted meeting",
+			Args: graphql.FieldConfigArgument{
+				"meetingID": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.ID),
+					Description: "The ID of the meeting",
+				},
+			},
+			Resolve: roleRestrictedResolver(auth.PRACTITIONER, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				id := p.Args["meetingID"].(string)
+				if err := v.db.DeleteMeeting(id, u); err != nil {
+					return nil, err
+				}
+				if err := v.db.RevokeAssessment(id, u); err != nil {
+					return nil, err
+				}
+				return id, nil
 			}),
 		},
-		//"DeleteMeeting",
 	}
 }
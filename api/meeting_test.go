@@ -0,0 +1,87 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/impactasaurus/server/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeletedMeetingsCannotBeAltered(t *testing.T) {
+	h, issue := setupOrgUsers(t)
+	admin := issue(auth.ADMIN)
+	practitioner := issue(auth.PRACTITIONER)
+
+	res := query(t, h, admin, `mutation { AddOutcomeSet(name: "os") { id } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	osID := res.Data["AddOutcomeSet"].(map[string]interface{})["id"].(string)
+	res = query(t, h, admin, `mutation { AddYesNoQuestion(outcomeSetID: "`+osID+`", question: "Employed?") { questions { id } } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	qID := res.Data["AddYesNoQuestion"].(map[string]interface{})["questions"].([]interface{})[0].(map[string]interface{})["id"].(string)
+
+	res = query(t, h, practitioner, `mutation { AddMeeting(beneficiaryID: "ben", outcomeSetID: "`+osID+`", conducted: "2017-10-01T12:00:00Z") { id } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	meetingID := res.Data["AddMeeting"].(map[string]interface{})["id"].(string)
+	res = query(t, h, practitioner, `mutation { AddYesNoAnswer(meetingID: "`+meetingID+`", questionID: "`+qID+`", value: true) { id } }`)
+	assert.Len(t, res.Errors, 0)
+	res = query(t, h, practitioner, `mutation { DeleteMeeting(meetingID: "`+meetingID+`") }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+
+	for _, m := range []string{
+		`AddYesNoAnswer(meetingID: "` + meetingID + `", questionID: "` + qID + `", value: false) { id }`,
+		`EditMeeting(meetingID: "` + meetingID + `", beneficiaryID: "other", conducted: "2017-10-02T12:00:00Z") { id }`,
+		`CompleteMeeting(meetingID: "` + meetingID + `") { id }`,
+		`AbandonMeeting(meetingID: "` + meetingID + `") { id }`,
+	} {
+		res = query(t, h, practitioner, `mutation { `+m+` }`)
+		if assert.Len(t, res.Errors, 1, m) {
+			assert.Contains(t, res.Errors[0].Message, "not found", m)
+		}
+	}
+
+	res = query(t, h, practitioner, `{ meeting(id: "`+meetingID+`") { id } }`)
+	if assert.Len(t, res.Errors, 1) {
+		assert.Contains(t, res.Errors[0].Message, "not found")
+	}
+}
+
+func TestAnswerErrorCodes(t *testing.T) {
+	h, issue := setupOrgUsers(t)
+	admin := issue(auth.ADMIN)
+	practitioner := issue(auth.PRACTITIONER)
+
+	res := query(t, h, admin, `mutation { AddOutcomeSet(name: "os") { id } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	osID := res.Data["AddOutcomeSet"].(map[string]interface{})["id"].(string)
+	res = query(t, h, admin, `mutation { AddLikertQuestion(outcomeSetID: "`+osID+`", question: "Happy?", minValue: 1, maxValue: 5) { questions { id } } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	qID := res.Data["AddLikertQuestion"].(map[string]interface{})["questions"].([]interface{})[0].(map[string]interface{})["id"].(string)
+	res = query(t, h, practitioner, `mutation { AddMeeting(beneficiaryID: "ben", outcomeSetID: "`+osID+`", conducted: "2017-10-01T12:00:00Z") { id } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	meetingID := res.Data["AddMeeting"].(map[string]interface{})["id"].(string)
+
+	for code, m := range map[string]string{
+		"ANSWER_OUT_OF_RANGE": `AddLikertAnswer(meetingID: "` + meetingID + `", questionID: "` + qID + `", value: 6)`,
+		"QUESTION_NOT_FOUND":  `AddLikertAnswer(meetingID: "` + meetingID + `", questionID: "unknown", value: 3)`,
+		"WRONG_ANSWER_TYPE":   `AddYesNoAnswer(meetingID: "` + meetingID + `", questionID: "` + qID + `", value: true)`,
+	} {
+		res = query(t, h, practitioner, `mutation { `+m+` { id } }`)
+		if assert.Len(t, res.Errors, 1, code) {
+			assert.Equal(t, code, res.Errors[0].Extensions["code"])
+		}
+	}
+}
@@ -2,26 +2,77 @@ package api
 
 import (
 	"github.com/graphql-go/graphql"
+	impact "github.com/impactasaurus/server"
 	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	"github.com/impactasaurus/server/logic"
 )
 
 func (v *v1) initOrgTypes() organisationTypes {
-	return organisationTypes{
-		organisationType: graphql.NewObject(graphql.ObjectConfig{
-			Name:        "Organisation",
-			Description: "An organisation",
-			Fields: graphql.Fields{
-				"id": &graphql.Field{
-					Type:        graphql.NewNonNull(graphql.ID),
-					Description: "Unique identifier for the organisation",
-				},
-				"name": &graphql.Field{
-					Type:        graphql.NewNonNull(graphql.String),
-					Description: "Organisation's name",
-				},
+	ret := organisationTypes{}
+
+	ret.settingsType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "OrganisationSettings",
+		Description: "Organisation wide settings, unset settings are empty strings",
+		Fields: graphql.Fields{
+			"timezone": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "The organisation's IANA time zone, e.g. Europe/London",
 			},
-		}),
-	}
+			"defaultOutcomeSetID": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "The ID of the outcome set selected by default when starting a meeting",
+			},
+			"locale": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "The organisation's language tag, e.g. en-GB",
+			},
+		},
+	})
+
+	ret.organisationType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Organisation",
+		Description: "An organisation",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.ID),
+				Description: "Unique identifier for the organisation",
+			},
+			"name": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "Organisation's name",
+			},
+			"settings": &graphql.Field{
+				Type:        graphql.NewNonNull(ret.settingsType),
+				Description: "Organisation's settings",
+			},
+		},
+	})
+
+	ret.userType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "User",
+		Description: "The user making the request",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.ID),
+				Description: "The user's unique identifier",
+			},
+			"role": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "The user's role within the organisation, either admin, practitioner or analyst",
+			},
+			"organisation": &graphql.Field{
+				Type:        ret.organisationType,
+				Description: "The organisation the user is acting within, null if the organisation has not been created yet",
+			},
+			"organisations": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.String)),
+				Description: "The IDs of all the organisations the user belongs to",
+			},
+		},
+	})
+
+	return ret
 }
 
 func (v *v1) getOrgQueries(orgTypes organisationTypes) graphql.Fields {
@@ -35,9 +86,89 @@ func (v *v1) getOrgQueries(orgTypes organisationTypes) graphql.Fields {
 					Type:        graphql.NewNonNull(graphql.String),
 				},
 			},
-			Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+			Resolve: roleRestrictedResolver(auth.ANALYST, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 				return v.db.GetOrganisation(p.Args["id"].(string), u)
 			}),
 		},
+		"me": &graphql.Field{
+			Type:        orgTypes.userType,
+			Description: "Get the current user, along with the organisation they are acting within",
+			Resolve: roleRestrictedResolver(auth.ANALYST, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				userOrg, err := u.Organisation()
+				if err != nil {
+					return nil, err
+				}
+				var org interface{}
+				found, err := v.db.GetOrganisation(userOrg, u)
+				if err == nil {
+					org = found
+				} else if !data.IsNotFound(err) {
+					return nil, err
+				}
+				return map[string]interface{}{
+					"id":            u.UserID(),
+					"role":          string(u.Role()),
+					"organisation":  org,
+					"organisations": u.Organisations(),
+				}, nil
+			}),
+		},
+	}
+}
+
+func (v *v1) getOrgMutations(orgTypes organisationTypes) graphql.Fields {
+	return graphql.Fields{
+		"CreateOrganisation": &graphql.Field{
+			Type:        orgTypes.organisationType,
+			Description: "Create the organisation the user is acting within. The organisation's ID is taken from the user",
+			Args: graphql.FieldConfigArgument{
+				"name": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The name of the organisation",
+				},
+			},
+			Resolve: roleRestrictedResolver(auth.ADMIN, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				return v.db.CreateOrganisation(p.Args["name"].(string), u)
+			}),
+		},
+		"EditOrganisation": &graphql.Field{
+			Type:        orgTypes.organisationType,
+			Description: "Edit an organisation and its settings",
+			Args: graphql.FieldConfigArgument{
+				"organisationID": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.ID),
+					Description: "The ID of the organisation",
+				},
+				"name": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The new name to apply to the organisation",
+				},
+				"timezone": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "The organisation's IANA time zone, e.g. Europe/London. If left null, any existing timezone will be removed",
+				},
+				"defaultOutcomeSetID": &graphql.ArgumentConfig{
+					Type:        graphql.ID,
+					Description: "The outcome set selected by default when starting a meeting. If left null, any existing default will be removed",
+				},
+				"locale": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "The organisation's language tag, e.g. en-GB. If left null, any existing locale will be removed",
+				},
+			},
+			Resolve: roleRestrictedResolver(auth.ADMIN, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				id := p.Args["organisationID"].(string)
+				name := p.Args["name"].(string)
+				settings := impact.OrganisationSettings{
+					Timezone:            getNullableString(p.Args, "timezone"),
+					DefaultOutcomeSetID: getNullableString(p.Args, "defaultOutcomeSetID"),
+					Locale:              getNullableString(p.Args, "locale"),
+				}
+				if err := logic.ValidateOrganisationSettings(settings, v.db, u); err != nil {
+					return nil, err
+				}
+				return v.db.EditOrganisation(id, name, settings, u)
+			}),
+		},
 	}
 }
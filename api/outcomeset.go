@@ -2,6 +2,8 @@ package api
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/graphql-go/graphql"
 	impact "github.com/impactasaurus/server"
@@ -44,125 +46,137 @@ func (v *v1) initOutcomeSetTypes(orgTypes organisationTypes) outcomeSetTypes {
 			switch obj.Type {
 			case impact.LIKERT:
 				return ret.likertScale
+			case impact.FREE_TEXT:
+				return ret.freeTextQuestion
+			case impact.YES_NO:
+				return ret.yesNoQuestion
+			case impact.SINGLE_CHOICE:
+				return ret.singleChoiceQuestion
+			case impact.MULTI_CHOICE:
+				return ret.multiChoiceQuestion
+			case impact.NUMERIC:
+				return ret.numericQuestion
 			default:
 				return ret.likertScale
 			}
 		},
 	})
 
-	ret.likertScale = graphql.NewObject(graphql.ObjectConfig{
-		Name:        "LikertScale",
-		Description: "Question gathering information using Likert Scales",
-		Interfaces: []*graphql.Interface{
-			ret.questionInterface,
-		},
-		Fields: graphql.Fields{
-			"id": &graphql.Field{
-				Type:        graphql.NewNonNull(graphql.ID),
-				Description: "Unique ID for the question",
-			},
-			"question": &graphql.Field{
-				Type:        graphql.NewNonNull(graphql.String),
-				Description: "The question",
-			},
-			"description": &graphql.Field{
-				Type:        graphql.String,
-				Description: "Optional description of the question",
-			},
-			"archived": &graphql.Field{
-				Type:        graphql.Boolean,
-				Description: "Whether the question has been archived",
-				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-					obj, ok := p.Source.(impact.Question)
-					if !ok {
-						return nil, errors.New("Expecting an impact.Question")
-					}
-					return obj.Deleted, nil
-				},
-			},
-			"categoryID": &graphql.Field{
-				Type:        graphql.String,
-				Description: "The category the question belongs to",
+	ret.likertScale = newQuestionObject("LikertScale", "Question gathering information using Likert Scales", ret.questionInterface, graphql.Fields{
+		"reverseScored": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether a low value is good, the question is flipped within the scale when aggregated",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				obj, ok := p.Source.(impact.Question)
+				if !ok {
+					return nil, errors.New("Expecting an impact.Question")
+				}
+				return obj.IsReverseScored(), nil
 			},
-			"minValue": &graphql.Field{
-				Type:        graphql.Int,
-				Description: "The minimum value in the scale",
-				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-					obj, ok := p.Source.(impact.Question)
-					if !ok {
-						return nil, errors.New("Expecting an impact.Question")
-					}
-					minValue, ok := obj.Options["minValue"]
-					if !ok {
-						return nil, nil
-					}
-					minValueInt, ok := minValue.(int)
-					if !ok {
-						return nil, errors.New("Min likert value should be an int")
-					}
-					return minValueInt, nil
-				},
+		},
+		"minValue": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The minimum value in the scale",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				obj, ok := p.Source.(impact.Question)
+				if !ok {
+					return nil, errors.New("Expecting an impact.Question")
+				}
+				minValue, ok := obj.Options["minValue"]
+				if !ok {
+					return nil, nil
+				}
+				minValueInt, ok := minValue.(int)
+				if !ok {
+					return nil, errors.New("Min likert value should be an int")
+				}
+				return minValueInt, nil
 			},
-			"maxValue": &graphql.Field{
-				Type:        graphql.NewNonNull(graphql.Int),
-				Description: "The maximum value in the scale",
-				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-					obj, ok := p.Source.(impact.Question)
-					if !ok {
-						return nil, errors.New("Expecting an impact.Question")
-					}
-					maxValue, ok := obj.Options["maxValue"]
-					if !ok {
-						return nil, nil
-					}
-					maxValueInt, ok := maxValue.(int)
-					if !ok {
-						return nil, errors.New("Max likert value should be an int")
-					}
-					return maxValueInt, nil
-				},
+		},
+		"maxValue": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The maximum value in the scale",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				obj, ok := p.Source.(impact.Question)
+				if !ok {
+					return nil, errors.New("Expecting an impact.Question")
+				}
+				maxValue, ok := obj.Options["maxValue"]
+				if !ok {
+					return nil, nil
+				}
+				maxValueInt, ok := maxValue.(int)
+				if !ok {
+					return nil, errors.New("Max likert value should be an int")
+				}
+				return maxValueInt, nil
 			},
-			"minLabel": &graphql.Field{
-				Type:        graphql.String,
-				Description: "The string labelling the minimum value in the scale",
-				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-					obj, ok := p.Source.(impact.Question)
-					if !ok {
-						return nil, errors.New("Expecting an impact.Question")
-					}
-					label, ok := obj.Options["minLabel"]
-					if !ok {
-						return nil, nil
-					}
-					labelStr, ok := label.(string)
-					if !ok {
-						return nil, errors.New("Min likert label should be an string")
-					}
-					return labelStr, nil
-				},
+		},
+		"minLabel": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The string labelling the minimum value in the scale",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				obj, ok := p.Source.(impact.Question)
+				if !ok {
+					return nil, errors.New("Expecting an impact.Question")
+				}
+				label, ok := obj.Options["minLabel"]
+				if !ok {
+					return nil, nil
+				}
+				labelStr, ok := label.(string)
+				if !ok {
+					return nil, errors.New("Min likert label should be an string")
+				}
+				return labelStr, nil
 			},
-			"maxLabel": &graphql.Field{
-				Type:        graphql.String,
-				Description: "The string labelling the maximum value in the scale",
-				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-					obj, ok := p.Source.(impact.Question)
-					if !ok {
-						return nil, errors.New("Expecting an impact.Question")
-					}
-					label, ok := obj.Options["maxLabel"]
-					if !ok {
-						return nil, nil
-					}
-					labelStr, ok := label.(string)
-					if !ok {
-						return nil, errors.New("Max likert label should be an string")
-					}
-					return labelStr, nil
-				},
+		},
+		"maxLabel": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The string labelling the maximum value in the scale",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				obj, ok := p.Source.(impact.Question)
+				if !ok {
+					return nil, errors.New("Expecting an impact.Question")
+				}
+				label, ok := obj.Options["maxLabel"]
+				if !ok {
+					return nil, nil
+				}
+				labelStr, ok := label.(string)
+				if !ok {
+					return nil, errors.New("Max likert label should be an string")
+				}
+				return labelStr, nil
 			},
 		},
 	})
 
+	ret.freeTextQuestion = newQuestionObject("FreeTextQuestion", "Question answered with free text", ret.questionInterface, nil)
+
+	ret.yesNoQuestion = newQuestionObject("YesNoQuestion", "Question answered with yes or no", ret.questionInterface, nil)
+
+	ret.singleChoiceQuestion = newQuestionObject("SingleChoiceQuestion", "Question answered by picking one of a set of choices", ret.questionInterface, graphql.Fields{
+		"choices": choicesField(),
+	})
+
+	ret.multiChoiceQuestion = newQuestionObject("MultiChoiceQuestion", "Question answered by picking any number of a set of choices", ret.questionInterface, graphql.Fields{
+		"choices": choicesField(),
+	})
+
+	ret.numericQuestion = newQuestionObject("NumericQuestion", "Question answered with a number", ret.questionInterface, graphql.Fields{
+		"minValue": &graphql.Field{
+			Type:        graphql.Float,
+			Description: "The minimum value which can be given, if any",
+			Resolve:     numericOptionResolver("minValue"),
+		},
+		"maxValue": &graphql.Field{
+			Type:        graphql.Float,
+			Description: "The maximum value which can be given, if any",
+			Resolve:     numericOptionResolver("maxValue"),
+		},
+	})
+
 	ret.aggregationEnum = graphql.NewEnum(graphql.EnumConfig{
 		Name:        "Aggregation",
 		Description: "Aggregation functions available",
@@ -216,7 +230,7 @@ func (v *v1) initOutcomeSetTypes(orgTypes organisationTypes) outcomeSetTypes {
 			"organisation": &graphql.Field{
 				Type:        graphql.NewNonNull(orgTypes.organisationType),
 				Description: "The owning organisation of the outcome set",
-				Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				Resolve: roleRestrictedResolver(auth.ANALYST, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 					obj, ok := p.Source.(impact.OutcomeSet)
 					if !ok {
 						return nil, errors.New("Expecting an impact.Meeting")
@@ -240,9 +254,40 @@ func (v *v1) initOutcomeSetTypes(orgTypes organisationTypes) outcomeSetTypes {
 				Type:        graphql.NewList(ret.categoryType),
 				Description: "Questions associated with the outcome set",
 			},
+			"version": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.Int),
+				Description: "The latest published version of the outcome set, 0 if it has never been published. For a published version, the version itself",
+			},
+			"published": &graphql.Field{
+				Type:        graphql.String,
+				Description: "When the version was published, null if the outcome set has never been published",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					obj, ok := p.Source.(impact.OutcomeSet)
+					if !ok {
+						return nil, errors.New("Expecting an impact.OutcomeSet")
+					}
+					if obj.Published.IsZero() {
+						return nil, nil
+					}
+					return obj.Published.Format(time.RFC3339), nil
+				},
+			},
 		},
 	})
 
+	// versions is added once the type exists, as it refers to the type itself
+	ret.outcomeSetType.AddFieldConfig("versions", &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(ret.outcomeSetType)),
+		Description: "The published versions of the outcome set, oldest first. Published versions are not altered by later edits",
+		Resolve: roleRestrictedResolver(auth.ANALYST, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+			obj, ok := p.Source.(impact.OutcomeSet)
+			if !ok {
+				return nil, errors.New("Expecting an impact.OutcomeSet")
+			}
+			return v.db.GetOutcomeSetVersions(obj.ID, u)
+		}),
+	})
+
 	return ret
 }
 
@@ -251,7 +296,7 @@ func (v *v1) getOSQueries(osTypes outcomeSetTypes) graphql.Fields {
 		"outcomesets": &graphql.Field{
 			Type:        graphql.NewList(osTypes.outcomeSetType),
 			Description: "Gather all outcome sets",
-			Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+			Resolve: roleRestrictedResolver(auth.ANALYST, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 				return v.db.GetOutcomeSets(u)
 			}),
 		},
@@ -264,15 +309,45 @@ func (v *v1) getOSQueries(osTypes outcomeSetTypes) graphql.Fields {
 					Type:        graphql.NewNonNull(graphql.String),
 				},
 			},
-			Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+			Resolve: roleRestrictedResolver(auth.ANALYST, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 				return v.db.GetOutcomeSet(p.Args["id"].(string), u)
 			}),
 		},
+		"outcomesetVersion": &graphql.Field{
+			Type:        osTypes.outcomeSetType,
+			Description: "Gather a published version of an outcome set",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Description: "The ID of the outcomeset",
+					Type:        graphql.NewNonNull(graphql.String),
+				},
+				"version": &graphql.ArgumentConfig{
+					Description: "The published version",
+					Type:        graphql.NewNonNull(graphql.Int),
+				},
+			},
+			Resolve: roleRestrictedResolver(auth.ANALYST, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				return v.db.GetOutcomeSetVersion(p.Args["id"].(string), p.Args["version"].(int), u)
+			}),
+		},
 	}
 }
 
 func (v *v1) getOSMutations(osTypes outcomeSetTypes) graphql.Fields {
 	return graphql.Fields{
+		"PublishOutcomeSet": &graphql.Field{
+			Type:        osTypes.outcomeSetType,
+			Description: "Publish the outcome set's current questions and categories as a new version. Meetings created afterwards are conducted against this version, unaffected by later edits until they are published",
+			Args: graphql.FieldConfigArgument{
+				"outcomeSetID": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.ID),
+					Description: "The ID of the outcomeset",
+				},
+			},
+			Resolve: roleRestrictedResolver(auth.ADMIN, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+				return v.db.PublishOutcomeSet(p.Args["outcomeSetID"].(string), u)
+			}),
+		},
 		"AddOutcomeSet": &graphql.Field{
 			Type:        osTypes.outcomeSetType,
 			Description: "Create a new outcomeset",
@@ -286,7 +361,7 @@ func (v *v1) getOSMutations(osTypes outcomeSetTypes) graphql.Fields {
 					Description: "An optional description",
 				},
 			},
-			Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+			Resolve: roleRestrictedResolver(auth.ADMIN, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 				name := p.Args["name"].(string)
 				description := getNullableString(p.Args, "description")
 				return v.db.NewOutcomeSet(name, description, u)
@@ -309,7 +384,7 @@ func (v *v1) getOSMutations(osTypes outcomeSetTypes) graphql.Fields {
 					Description: "The new description to apply to the outcomeset, if left null, any existing description will be removed",
 				},
 			},
-			Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+			Resolve: roleRestrictedResolver(auth.ADMIN, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 				id := p.Args["outcomeSetID"].(string)
 				name := p.Args["name"].(string)
 				description := getNullableString(p.Args, "description")
@@ -325,7 +400,7 @@ func (v *v1) getOSMutations(osTypes outcomeSetTypes) graphql.Fields {
 					Description: "The ID of the outcomeset",
 				},
 			},
-			Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+			Resolve: roleRestrictedResolver(auth.ADMIN, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 				id := p.Args["outcomeSetID"].(string)
 				if err := v.db.DeleteOutcomeSet(id, u); err != nil {
 					return nil, err
@@ -350,7 +425,7 @@ func (v *v1) getOSMutations(osTypes outcomeSetTypes) graphql.Fields {
 					Description: "The new zero indexed position of the question witin the question set. Must be greater or equal to 0. The new index should be specified assuming that the question has been removed before being reinserted at the new index.",
 				},
 			},
-			Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+			Resolve: roleRestrictedResolver(auth.ADMIN, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 				outcomeSetID := p.Args["outcomeSetID"].(string)
 				questionID := p.Args["questionID"].(string)
 				newIndex := p.Args["newIndex"].(int)
@@ -384,7 +459,7 @@ func (v *v1) getOSMutations(osTypes outcomeSetTypes) graphql.Fields {
 					Description: "The aggregation applied to the category",
 				},
 			},
-			Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+			Resolve: roleRestrictedResolver(auth.ADMIN, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 				id := p.Args["outcomeSetID"].(string)
 				name := p.Args["name"].(string)
 				description := getNullableString(p.Args, "description")
@@ -408,7 +483,7 @@ func (v *v1) getOSMutations(osTypes outcomeSetTypes) graphql.Fields {
 					Description: "The ID of the category",
 				},
 			},
-			Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+			Resolve: roleRestrictedResolver(auth.ADMIN, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 				outcomeSetID := p.Args["outcomeSetID"].(string)
 				categoryID := p.Args["categoryID"].(string)
 				if err := v.db.DeleteCategory(outcomeSetID, categoryID, u); err != nil {
@@ -442,7 +517,7 @@ func (v *v1) getOSMutations(osTypes outcomeSetTypes) graphql.Fields {
 					Description: "The aggregation applied to the category",
 				},
 			},
-			Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+			Resolve: roleRestrictedResolver(auth.ADMIN, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 				osID := p.Args["outcomeSetID"].(string)
 				cID := p.Args["categoryID"].(string)
 				originalCat, err := v.db.GetCategory(osID, cID, u)
@@ -485,7 +560,7 @@ func (v *v1) getOSMutations(osTypes outcomeSetTypes) graphql.Fields {
 					Description: "The ID of the category. If NULL, the category associated with the question is removed",
 				},
 			},
-			Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+			Resolve: roleRestrictedResolver(auth.ADMIN, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 				outcomeSetID := p.Args["outcomeSetID"].(string)
 				questionID := p.Args["questionID"].(string)
 				categoryID := getNullableString(p.Args, "categoryID")
@@ -533,20 +608,26 @@ func (v *v1) getOSMutations(osTypes outcomeSetTypes) graphql.Fields {
 					Type:        graphql.String,
 					Description: "Label associated with the maximum value of the likert scale",
 				},
+				"reverseScored": &graphql.ArgumentConfig{
+					Type:        graphql.Boolean,
+					Description: "Whether a low value is good, such questions are flipped within the scale when aggregated. Defaults to false",
+				},
 			},
-			Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+			Resolve: roleRestrictedResolver(auth.ADMIN, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 				id := p.Args["outcomeSetID"].(string)
 				question := p.Args["question"].(string)
 				minValue := getNullableInt(p.Args, "minValue")
 				maxValue := p.Args["maxValue"].(int)
 				minLabel := getNullableString(p.Args, "minLabel")
 				maxLabel := getNullableString(p.Args, "maxLabel")
+				reverseScored := getNullableBool(p.Args, "reverseScored")
 				description := getNullableString(p.Args, "description")
 				if _, err := v.db.NewQuestion(id, question, description, impact.LIKERT, map[string]interface{}{
-					"minValue": minValue,
-					"maxValue": maxValue,
-					"minLabel": minLabel,
-					"maxLabel": maxLabel,
+					"minValue":      minValue,
+					"maxValue":      maxValue,
+					"minLabel":      minLabel,
+					"maxLabel":      maxLabel,
+					"reverseScored": reverseScored,
 				}, u); err != nil {
 					return nil, err
 				}
@@ -581,14 +662,21 @@ func (v *v1) getOSMutations(osTypes outcomeSetTypes) graphql.Fields {
 					Type:        graphql.String,
 					Description: "New label associated with the maximum value of the likert scale",
 				},
+				"reverseScored": &graphql.ArgumentConfig{
+					Type:        graphql.Boolean,
+					Description: "Whether a low value is good. Changing this alters the aggregation of existing answers",
+				},
 			},
-			Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+			Resolve: roleRestrictedResolver(auth.ADMIN, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 				osID := p.Args["outcomeSetID"].(string)
 				qID := p.Args["questionID"].(string)
 				originalQ, err := v.db.GetQuestion(osID, qID, u)
 				if err != nil {
 					return nil, err
 				}
+				if originalQ.Type != impact.LIKERT {
+					return nil, fmt.Errorf("Question %s is a %s question, not %s", qID, originalQ.Type, impact.LIKERT)
+				}
 				newQ := originalQ
 
 				if newQuestion, ok := getNullOrString(p.Args, "question"); ok {
@@ -603,6 +691,9 @@ func (v *v1) getOSMutations(osTypes outcomeSetTypes) graphql.Fields {
 				if newMaxLabel, ok := getNullOrString(p.Args, "maxLabel"); ok {
 					newQ.Options["maxLabel"] = newMaxLabel
 				}
+				if reverseScored, ok := p.Args["reverseScored"].(bool); ok {
+					newQ.Options["reverseScored"] = reverseScored
+				}
 				if _, err := v.db.EditQuestion(osID, qID, newQ.Question, newQ.Description, impact.LIKERT, newQ.Options, u); err != nil {
 					return nil, err
 				}
@@ -622,7 +713,7 @@ func (v *v1) getOSMutations(osTypes outcomeSetTypes) graphql.Fields {
 					Description: "The ID of the question",
 				},
 			},
-			Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+			Resolve: roleRestrictedResolver(auth.ADMIN, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 				outcomeSetID := p.Args["outcomeSetID"].(string)
 				questionID := p.Args["questionID"].(string)
 				if err := v.db.DeleteQuestion(outcomeSetID, questionID, u); err != nil {
@@ -0,0 +1,71 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/impactasaurus/server/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishOutcomeSet(t *testing.T) {
+	h, issue := setupOrgUsers(t)
+	admin := issue(auth.ADMIN)
+	practitioner := issue(auth.PRACTITIONER)
+
+	res := query(t, h, admin, `mutation { AddOutcomeSet(name: "os") { id } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	osID := res.Data["AddOutcomeSet"].(map[string]interface{})["id"].(string)
+	res = query(t, h, admin, `mutation { AddSingleChoiceQuestion(outcomeSetID: "`+osID+`", question: "Housing?", choices: ["Rented", "Owned"]) { questions { id } } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	qID := res.Data["AddSingleChoiceQuestion"].(map[string]interface{})["questions"].([]interface{})[0].(map[string]interface{})["id"].(string)
+
+	res = query(t, h, practitioner, `mutation { PublishOutcomeSet(outcomeSetID: "`+osID+`") { version } }`)
+	assert.NotEmpty(t, res.Errors, "only admins can publish")
+	res = query(t, h, admin, `mutation { PublishOutcomeSet(outcomeSetID: "`+osID+`") { version published } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	published := res.Data["PublishOutcomeSet"].(map[string]interface{})
+	assert.Equal(t, float64(1), published["version"])
+	assert.NotNil(t, published["published"])
+
+	res = query(t, h, admin, `mutation { EditSingleChoiceQuestion(outcomeSetID: "`+osID+`", questionID: "`+qID+`", choices: ["Rented", "Other"]) { version } }`)
+	assert.Len(t, res.Errors, 0)
+
+	res = query(t, h, practitioner, `mutation { AddMeeting(beneficiaryID: "ben", outcomeSetID: "`+osID+`", conducted: "2017-10-01T12:00:00Z") { id outcomeSetVersion } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	meeting := res.Data["AddMeeting"].(map[string]interface{})
+	assert.Equal(t, float64(1), meeting["outcomeSetVersion"])
+	meetingID := meeting["id"].(string)
+
+	answer := func(value string) gqlResponse {
+		return query(t, h, practitioner, `mutation { AddSingleChoiceAnswer(meetingID: "`+meetingID+`", questionID: "`+qID+`", value: "`+value+`") { id } }`)
+	}
+	assert.NotEmpty(t, answer("Other").Errors, "answers should be validated against the published version")
+	assert.Len(t, answer("Owned").Errors, 0)
+
+	res = query(t, h, practitioner, `{ meeting(id: "`+meetingID+`") { outcomeSet { version questions { ... on SingleChoiceQuestion { choices } } } } }`)
+	if assert.Len(t, res.Errors, 0) {
+		os := res.Data["meeting"].(map[string]interface{})["outcomeSet"].(map[string]interface{})
+		assert.Equal(t, float64(1), os["version"])
+		assert.Equal(t, []interface{}{"Rented", "Owned"}, os["questions"].([]interface{})[0].(map[string]interface{})["choices"])
+	}
+
+	res = query(t, h, admin, `mutation { PublishOutcomeSet(outcomeSetID: "`+osID+`") { version versions { version } } }`)
+	if assert.Len(t, res.Errors, 0) {
+		published = res.Data["PublishOutcomeSet"].(map[string]interface{})
+		assert.Equal(t, float64(2), published["version"])
+		assert.Len(t, published["versions"], 2)
+	}
+	res = query(t, h, issue(auth.ANALYST), `{ outcomesetVersion(id: "`+osID+`", version: 2) { questions { ... on SingleChoiceQuestion { choices } } } }`)
+	if assert.Len(t, res.Errors, 0) {
+		os := res.Data["outcomesetVersion"].(map[string]interface{})
+		assert.Equal(t, []interface{}{"Rented", "Other"}, os["questions"].([]interface{})[0].(map[string]interface{})["choices"])
+	}
+}
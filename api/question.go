@@ -0,0 +1,363 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/logic"
+)
+
+// questionFields returns the fields shared by all question types, combined with the type's own fields
+func questionFields(extra graphql.Fields) graphql.Fields {
+	fields := graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.ID),
+			Description: "Unique ID for the question",
+		},
+		"question": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The question",
+		},
+		"description": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Optional description of the question",
+		},
+		"archived": &graphql.Field{
+			Type:        graphql.Boolean,
+			Description: "Whether the question has been archived",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				obj, ok := p.Source.(impact.Question)
+				if !ok {
+					return nil, errors.New("Expecting an impact.Question")
+				}
+				return obj.Deleted, nil
+			},
+		},
+		"categoryID": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The category the question belongs to",
+		},
+	}
+	for k, f := range extra {
+		fields[k] = f
+	}
+	return fields
+}
+
+func newQuestionObject(name, description string, questionInterface *graphql.Interface, extra graphql.Fields) *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name:        name,
+		Description: description,
+		Interfaces: []*graphql.Interface{
+			questionInterface,
+		},
+		Fields: questionFields(extra),
+	})
+}
+
+func numericOptionResolver(key string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		obj, ok := p.Source.(impact.Question)
+		if !ok {
+			return nil, errors.New("Expecting an impact.Question")
+		}
+		v, ok := obj.NumericOption(key)
+		if !ok {
+			return nil, nil
+		}
+		return v, nil
+	}
+}
+
+func choicesField() *graphql.Field {
+	return &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+		Description: "The choices offered",
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			obj, ok := p.Source.(impact.Question)
+			if !ok {
+				return nil, errors.New("Expecting an impact.Question")
+			}
+			return obj.Choices(), nil
+		},
+	}
+}
+
+// questionArgs returns the arguments shared by the question mutations, combined with the question type's own arguments
+func questionArgs(edit bool, extra graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+	args := graphql.FieldConfigArgument{
+		"outcomeSetID": &graphql.ArgumentConfig{
+			Type:        graphql.NewNonNull(graphql.ID),
+			Description: "The ID of the outcomeset",
+		},
+		"question": &graphql.ArgumentConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "Question to be asked",
+		},
+		"description": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "Optional description of the question",
+		},
+	}
+	if edit {
+		args["questionID"] = &graphql.ArgumentConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the question",
+		}
+		args["question"] = &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "The new question to be asked",
+		}
+		args["description"] = &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "New description of the question",
+		}
+	}
+	for k, a := range extra {
+		args[k] = a
+	}
+	return args
+}
+
+func getChoices(input map[string]interface{}, key string) ([]string, error) {
+	choices := []string{}
+	for _, c := range input[key].([]interface{}) {
+		choices = append(choices, c.(string))
+	}
+	return logic.NormaliseChoices(choices)
+}
+
+func getNullableFloat(input map[string]interface{}, key string) *float64 {
+	r, ok := input[key].(float64)
+	if !ok {
+		return nil
+	}
+	return &r
+}
+
+// addQuestionResolver adds a question of the provided type, options returns the type's options from the mutation's arguments
+func (v *v1) addQuestionResolver(qType impact.QuestionType, options func(args map[string]interface{}) (map[string]interface{}, error)) graphql.FieldResolveFn {
+	return roleRestrictedResolver(auth.ADMIN, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+		id := p.Args["outcomeSetID"].(string)
+		question := p.Args["question"].(string)
+		description := getNullableString(p.Args, "description")
+		opts, err := options(p.Args)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := v.db.NewQuestion(id, question, description, qType, opts, u); err != nil {
+			return nil, err
+		}
+		return v.db.GetOutcomeSet(id, u)
+	})
+}
+
+// editQuestionResolver edits a question of the provided type, arguments which are not specified are not altered.
+// edit applies the type's own arguments to the question's options.
+func (v *v1) editQuestionResolver(qType impact.QuestionType, edit func(args map[string]interface{}, options map[string]interface{}) error) graphql.FieldResolveFn {
+	return roleRestrictedResolver(auth.ADMIN, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+		osID := p.Args["outcomeSetID"].(string)
+		qID := p.Args["questionID"].(string)
+		originalQ, err := v.db.GetQuestion(osID, qID, u)
+		if err != nil {
+			return nil, err
+		}
+		if originalQ.Type != qType {
+			return nil, fmt.Errorf("Question %s is a %s question, not %s", qID, originalQ.Type, qType)
+		}
+		newQ := originalQ
+
+		if newQuestion, ok := getNullOrString(p.Args, "question"); ok {
+			newQ.Question = newQuestion
+		}
+		if newDescription, ok := getNullOrString(p.Args, "description"); ok {
+			newQ.Description = newDescription
+		}
+		options := map[string]interface{}{}
+		for k, o := range originalQ.Options {
+			options[k] = o
+		}
+		if err := edit(p.Args, options); err != nil {
+			return nil, err
+		}
+		if _, err := v.db.EditQuestion(osID, qID, newQ.Question, newQ.Description, qType, options, u); err != nil {
+			return nil, err
+		}
+		return v.db.GetOutcomeSet(osID, u)
+	})
+}
+
+func noOptions(args map[string]interface{}) (map[string]interface{}, error) {
+	return map[string]interface{}{}, nil
+}
+
+func noOptionEdits(args map[string]interface{}, options map[string]interface{}) error {
+	return nil
+}
+
+func choiceOptions(args map[string]interface{}) (map[string]interface{}, error) {
+	choices, err := getChoices(args, "choices")
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"choices": choices,
+	}, nil
+}
+
+func editChoiceOptions(args map[string]interface{}, options map[string]interface{}) error {
+	if args["choices"] == nil {
+		return nil
+	}
+	choices, err := getChoices(args, "choices")
+	if err != nil {
+		return err
+	}
+	options["choices"] = choices
+	return nil
+}
+
+func numericOptions(args map[string]interface{}) (map[string]interface{}, error) {
+	min := getNullableFloat(args, "minValue")
+	max := getNullableFloat(args, "maxValue")
+	if err := logic.ValidateNumericRange(min, max); err != nil {
+		return nil, err
+	}
+	options := map[string]interface{}{}
+	if min != nil {
+		options["minValue"] = *min
+	}
+	if max != nil {
+		options["maxValue"] = *max
+	}
+	return options, nil
+}
+
+func editNumericOptions(args map[string]interface{}, options map[string]interface{}) error {
+	if min := getNullableFloat(args, "minValue"); min != nil {
+		options["minValue"] = *min
+	}
+	if max := getNullableFloat(args, "maxValue"); max != nil {
+		options["maxValue"] = *max
+	}
+	// the merged range is validated, as only one of the bounds may have been edited
+	q := impact.Question{Options: options}
+	var min, max *float64
+	if v, ok := q.NumericOption("minValue"); ok {
+		min = &v
+	}
+	if v, ok := q.NumericOption("maxValue"); ok {
+		max = &v
+	}
+	return logic.ValidateNumericRange(min, max)
+}
+
+func (v *v1) getQuestionMutations(osTypes outcomeSetTypes) graphql.Fields {
+	choicesArg := func(edit bool) graphql.FieldConfigArgument {
+		if edit {
+			return graphql.FieldConfigArgument{
+				"choices": &graphql.ArgumentConfig{
+					Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+					Description: "The choices offered, replacing the existing choices. Answers already given are not altered",
+				},
+			}
+		}
+		return graphql.FieldConfigArgument{
+			"choices": &graphql.ArgumentConfig{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+				Description: "The choices offered, at least two must be provided",
+			},
+		}
+	}
+
+	numericArgs := func(edit bool) graphql.FieldConfigArgument {
+		if edit {
+			return graphql.FieldConfigArgument{
+				"minValue": &graphql.ArgumentConfig{
+					Type:        graphql.Float,
+					Description: "New minimum value of the answer. Answers already given are not altered",
+				},
+				"maxValue": &graphql.ArgumentConfig{
+					Type:        graphql.Float,
+					Description: "New maximum value of the answer. Answers already given are not altered",
+				},
+			}
+		}
+		return graphql.FieldConfigArgument{
+			"minValue": &graphql.ArgumentConfig{
+				Type:        graphql.Float,
+				Description: "Optional minimum value of the answer",
+			},
+			"maxValue": &graphql.ArgumentConfig{
+				Type:        graphql.Float,
+				Description: "Optional maximum value of the answer",
+			},
+		}
+	}
+
+	return graphql.Fields{
+		"AddFreeTextQuestion": &graphql.Field{
+			Type:        osTypes.outcomeSetType,
+			Description: "Add a question answered with free text to an outcome set",
+			Args:        questionArgs(false, nil),
+			Resolve:     v.addQuestionResolver(impact.FREE_TEXT, noOptions),
+		},
+		"EditFreeTextQuestion": &graphql.Field{
+			Type:        osTypes.outcomeSetType,
+			Description: "Edit a free text question. If arguments are not specified, their values are not altered.",
+			Args:        questionArgs(true, nil),
+			Resolve:     v.editQuestionResolver(impact.FREE_TEXT, noOptionEdits),
+		},
+		"AddYesNoQuestion": &graphql.Field{
+			Type:        osTypes.outcomeSetType,
+			Description: "Add a question answered with yes or no to an outcome set",
+			Args:        questionArgs(false, nil),
+			Resolve:     v.addQuestionResolver(impact.YES_NO, noOptions),
+		},
+		"EditYesNoQuestion": &graphql.Field{
+			Type:        osTypes.outcomeSetType,
+			Description: "Edit a yes/no question. If arguments are not specified, their values are not altered.",
+			Args:        questionArgs(true, nil),
+			Resolve:     v.editQuestionResolver(impact.YES_NO, noOptionEdits),
+		},
+		"AddSingleChoiceQuestion": &graphql.Field{
+			Type:        osTypes.outcomeSetType,
+			Description: "Add a question answered by picking one of a set of choices to an outcome set",
+			Args:        questionArgs(false, choicesArg(false)),
+			Resolve:     v.addQuestionResolver(impact.SINGLE_CHOICE, choiceOptions),
+		},
+		"EditSingleChoiceQuestion": &graphql.Field{
+			Type:        osTypes.outcomeSetType,
+			Description: "Edit a single choice question. If arguments are not specified, their values are not altered.",
+			Args:        questionArgs(true, choicesArg(true)),
+			Resolve:     v.editQuestionResolver(impact.SINGLE_CHOICE, editChoiceOptions),
+		},
+		"AddMultiChoiceQuestion": &graphql.Field{
+			Type:        osTypes.outcomeSetType,
+			Description: "Add a question answered by picking any number of a set of choices to an outcome set",
+			Args:        questionArgs(false, choicesArg(false)),
+			Resolve:     v.addQuestionResolver(impact.MULTI_CHOICE, choiceOptions),
+		},
+		"EditMultiChoiceQuestion": &graphql.Field{
+			Type:        osTypes.outcomeSetType,
+			Description: "Edit a multi choice question. If arguments are not specified, their values are not altered.",
+			Args:        questionArgs(true, choicesArg(true)),
+			Resolve:     v.editQuestionResolver(impact.MULTI_CHOICE, editChoiceOptions),
+		},
+		"AddNumericQuestion": &graphql.Field{
+			Type:        osTypes.outcomeSetType,
+			Description: "Add a question answered with a number to an outcome set",
+			Args:        questionArgs(false, numericArgs(false)),
+			Resolve:     v.addQuestionResolver(impact.NUMERIC, numericOptions),
+		},
+		"EditNumericQuestion": &graphql.Field{
+			Type:        osTypes.outcomeSetType,
+			Description: "Edit a numeric question. If arguments are not specified, their values are not altered.",
+			Args:        questionArgs(true, numericArgs(true)),
+			Resolve:     v.editQuestionResolver(impact.NUMERIC, editNumericOptions),
+		},
+	}
+}
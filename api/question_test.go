@@ -0,0 +1,145 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/impactasaurus/server/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuestionTypes(t *testing.T) {
+	h, issue := setupOrgUsers(t)
+	admin := issue(auth.ADMIN)
+
+	res := query(t, h, admin, `mutation { AddOutcomeSet(name: "os") { id } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	osID := res.Data["AddOutcomeSet"].(map[string]interface{})["id"].(string)
+
+	for _, q := range []string{
+		`AddFreeTextQuestion(outcomeSetID: "` + osID + `", question: "Anything else?")`,
+		`AddYesNoQuestion(outcomeSetID: "` + osID + `", question: "Employed?")`,
+		`AddSingleChoiceQuestion(outcomeSetID: "` + osID + `", question: "Housing?", choices: ["Rented", "Owned"])`,
+		`AddMultiChoiceQuestion(outcomeSetID: "` + osID + `", question: "Support?", choices: ["Food", "Debt", "Housing"])`,
+		`AddNumericQuestion(outcomeSetID: "` + osID + `", question: "Hours worked?", minValue: 0, maxValue: 80)`,
+	} {
+		res = query(t, h, admin, `mutation { `+q+` { id } }`)
+		assert.Len(t, res.Errors, 0, q)
+	}
+	res = query(t, h, admin, `mutation { AddSingleChoiceQuestion(outcomeSetID: "`+osID+`", question: "Housing?", choices: ["Rented"]) { id } }`)
+	assert.NotEmpty(t, res.Errors, "choice questions need at least two choices")
+	res = query(t, h, admin, `mutation { AddNumericQuestion(outcomeSetID: "`+osID+`", question: "Age?", minValue: 10, maxValue: 1) { id } }`)
+	assert.NotEmpty(t, res.Errors, "the minimum must not be greater than the maximum")
+
+	res = query(t, h, admin, `{ outcomeset(id: "`+osID+`") { questions {
+		id __typename
+		... on SingleChoiceQuestion { choices }
+		... on MultiChoiceQuestion { choices }
+		... on NumericQuestion { minValue maxValue }
+	} } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	ids := map[string]string{}
+	for _, q := range res.Data["outcomeset"].(map[string]interface{})["questions"].([]interface{}) {
+		question := q.(map[string]interface{})
+		typename := question["__typename"].(string)
+		ids[typename] = question["id"].(string)
+		switch typename {
+		case "SingleChoiceQuestion":
+			assert.Equal(t, []interface{}{"Rented", "Owned"}, question["choices"])
+		case "NumericQuestion":
+			assert.Equal(t, float64(0), question["minValue"])
+			assert.Equal(t, float64(80), question["maxValue"])
+		}
+	}
+	assert.Len(t, ids, 5)
+
+	res = query(t, h, admin, `mutation { EditSingleChoiceQuestion(outcomeSetID: "`+osID+`", questionID: "`+ids["SingleChoiceQuestion"]+`", choices: ["Rented", "Owned", "Other"]) { id } }`)
+	assert.Len(t, res.Errors, 0)
+	res = query(t, h, admin, `mutation { EditYesNoQuestion(outcomeSetID: "`+osID+`", questionID: "`+ids["FreeTextQuestion"]+`", question: "Employed?") { id } }`)
+	assert.NotEmpty(t, res.Errors, "a question can only be edited as its own type")
+	res = query(t, h, admin, `mutation { EditNumericQuestion(outcomeSetID: "`+osID+`", questionID: "`+ids["NumericQuestion"]+`", maxValue: 90) {
+		questions { ... on NumericQuestion { minValue maxValue } }
+	} }`)
+	if assert.Len(t, res.Errors, 0) {
+		for _, q := range res.Data["EditNumericQuestion"].(map[string]interface{})["questions"].([]interface{}) {
+			if question := q.(map[string]interface{}); question["maxValue"] != nil {
+				assert.Equal(t, float64(0), question["minValue"])
+				assert.Equal(t, float64(90), question["maxValue"])
+			}
+		}
+	}
+	res = query(t, h, admin, `mutation { EditNumericQuestion(outcomeSetID: "`+osID+`", questionID: "`+ids["NumericQuestion"]+`", minValue: 100) { id } }`)
+	assert.NotEmpty(t, res.Errors, "the edited minimum must not be greater than the existing maximum")
+	res = query(t, h, admin, `mutation { EditLikertQuestion(outcomeSetID: "`+osID+`", questionID: "`+ids["FreeTextQuestion"]+`", question: "Happy?") { id } }`)
+	assert.NotEmpty(t, res.Errors, "a question can only be edited as its own type")
+
+	practitioner := issue(auth.PRACTITIONER)
+	res = query(t, h, practitioner, `mutation { AddMeeting(beneficiaryID: "ben", outcomeSetID: "`+osID+`", conducted: "2017-10-01T12:00:00Z") { id } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	meetingID := res.Data["AddMeeting"].(map[string]interface{})["id"].(string)
+	answer := func(mutation, questionType, value string) gqlResponse {
+		return query(t, h, practitioner, `mutation { `+mutation+`(meetingID: "`+meetingID+`", questionID: "`+ids[questionType]+`", `+value+`) { id } }`)
+	}
+	assert.Len(t, answer("AddFreeTextAnswer", "FreeTextQuestion", `value: "Nothing"`).Errors, 0)
+	assert.Len(t, answer("AddYesNoAnswer", "YesNoQuestion", `value: true`).Errors, 0)
+	assert.Len(t, answer("AddSingleChoiceAnswer", "SingleChoiceQuestion", `value: "Other"`).Errors, 0)
+	assert.Len(t, answer("AddMultiChoiceAnswer", "MultiChoiceQuestion", `values: ["Food", "Debt"]`).Errors, 0)
+	assert.Len(t, answer("AddNumericAnswer", "NumericQuestion", `value: 37.5`).Errors, 0)
+	assert.NotEmpty(t, answer("AddSingleChoiceAnswer", "SingleChoiceQuestion", `value: "Tent"`).Errors)
+	assert.NotEmpty(t, answer("AddNumericAnswer", "NumericQuestion", `value: 100`).Errors)
+	assert.NotEmpty(t, answer("AddYesNoAnswer", "FreeTextQuestion", `value: true`).Errors)
+
+	res = query(t, h, practitioner, `mutation { CompleteMeeting(meetingID: "`+meetingID+`") { answers {
+		questionID __typename
+		... on StringAnswer { text: answer }
+		... on BoolAnswer { yes: answer }
+		... on StringListAnswer { choices: answer }
+		... on FloatAnswer { number: answer }
+	} aggregates { category { value } } } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	answers := map[string]map[string]interface{}{}
+	for _, a := range res.Data["CompleteMeeting"].(map[string]interface{})["answers"].([]interface{}) {
+		answer := a.(map[string]interface{})
+		answers[answer["questionID"].(string)] = answer
+	}
+	assert.Equal(t, "Nothing", answers[ids["FreeTextQuestion"]]["text"])
+	assert.Equal(t, true, answers[ids["YesNoQuestion"]]["yes"])
+	assert.Equal(t, "Other", answers[ids["SingleChoiceQuestion"]]["text"])
+	assert.Equal(t, []interface{}{"Food", "Debt"}, answers[ids["MultiChoiceQuestion"]]["choices"])
+	assert.Equal(t, 37.5, answers[ids["NumericQuestion"]]["number"])
+}
+
+func TestReverseScoredLikert(t *testing.T) {
+	h, issue := setupOrgUsers(t)
+	admin := issue(auth.ADMIN)
+
+	res := query(t, h, admin, `mutation { AddOutcomeSet(name: "os") { id } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	osID := res.Data["AddOutcomeSet"].(map[string]interface{})["id"].(string)
+
+	res = query(t, h, admin, `mutation { AddLikertQuestion(outcomeSetID: "`+osID+`", question: "Anxious?", minValue: 1, maxValue: 5, reverseScored: true) {
+		questions { id ... on LikertScale { reverseScored } }
+	} }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	q := res.Data["AddLikertQuestion"].(map[string]interface{})["questions"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, true, q["reverseScored"])
+
+	res = query(t, h, admin, `mutation { EditLikertQuestion(outcomeSetID: "`+osID+`", questionID: "`+q["id"].(string)+`", reverseScored: false) {
+		questions { ... on LikertScale { reverseScored } }
+	} }`)
+	if assert.Len(t, res.Errors, 0) {
+		q = res.Data["EditLikertQuestion"].(map[string]interface{})["questions"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, false, q["reverseScored"])
+	}
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// failureLimiter blocks clients which have failed too many times within a sliding window
+type failureLimiter struct {
+	mutex    sync.Mutex
+	max      int
+	window   time.Duration
+	failures map[string][]time.Time
+	now      func() time.Time
+	// lastSweep is when clients which have not returned were last pruned
+	lastSweep time.Time
+}
+
+func newFailureLimiter(max int, window time.Duration) *failureLimiter {
+	return &failureLimiter{
+		max:       max,
+		window:    window,
+		failures:  map[string][]time.Time{},
+		now:       time.Now,
+		lastSweep: time.Now(),
+	}
+}
+
+// recent drops failures which have left the window, the caller must hold the mutex
+func (l *failureLimiter) recent(client string) []time.Time {
+	cutoff := l.now().Add(-l.window)
+	kept := l.failures[client][:0]
+	for _, f := range l.failures[client] {
+		if f.After(cutoff) {
+			kept = append(kept, f)
+		}
+	}
+	if len(kept) == 0 {
+		delete(l.failures, client)
+		return nil
+	}
+	l.failures[client] = kept
+	return kept
+}
+
+// sweep prunes every client once per window, so clients which fail once and never return do not accumulate.
+// The caller must hold the mutex.
+func (l *failureLimiter) sweep() {
+	if l.now().Sub(l.lastSweep) < l.window {
+		return
+	}
+	for client := range l.failures {
+		l.recent(client)
+	}
+	l.lastSweep = l.now()
+}
+
+// Blocked returns true if the client has reached the failure limit
+func (l *failureLimiter) Blocked(client string) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return len(l.recent(client)) >= l.max
+}
+
+// Fail records a failure against the client
+func (l *failureLimiter) Fail(client string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.sweep()
+	l.failures[client] = append(l.recent(client), l.now())
+}
+
+// clientAddress identifies the client making the request.
+// X-Forwarded-For can be set by the client, so is only used when the server sits behind a trusted proxy.
+// The last entry is used as it was added by the proxy.
+func clientAddress(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			hops := strings.Split(fwd, ",")
+			return strings.TrimSpace(hops[len(hops)-1])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
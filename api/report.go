@@ -43,7 +43,7 @@ func (v *v1) initRepTypes() reportTypes {
 				},
 				"value": &graphql.Field{
 					Type:        graphql.Float,
-					Description: "The aggregated value",
+					Description: "The aggregated value. Reverse scored questions are flipped within their scale, so a higher value is always better",
 				},
 				"beneficiaryIDs": &graphql.Field{
 					Type:        graphql.NewNonNull(graphql.NewList(graphql.String)),
@@ -132,7 +132,7 @@ Aggregates are calculated over all beneficiaries for the first and last meetings
 					Description: "The question set to produce the report for",
 				},
 			},
-			Resolve: userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+			Resolve: roleRestrictedResolver(auth.ANALYST, func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
 				start := p.Args["start"].(string)
 				startParsed, err := time.Parse(time.RFC3339, start)
 				if err != nil {
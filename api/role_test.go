@@ -0,0 +1,108 @@
+package api_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"testing"
+	"time"
+
+	jwtLib "github.com/dgrijalva/jwt-go"
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/api"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupOrgUsers returns a handler along with a function which issues org1 JWTs for the role
+func setupOrgUsers(t *testing.T) (http.Handler, func(role auth.Role) string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := memory.New(impact.Organisation{ID: "org1", Name: "Org"})
+	h, err := api.NewV1(db, auth.NewBeneficiaryJWTGenerator(aud, iss, auth.NewKeyring(key)), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issue := func(role auth.Role) string {
+		token, err := jwtLib.NewWithClaims(jwtLib.SigningMethodRS256, jwtLib.MapClaims{
+			"aud": aud,
+			"iss": iss,
+			"sub": string(role) + "-user",
+			"exp": time.Now().Add(time.Minute).Unix(),
+			"app_metadata": map[string]interface{}{
+				"organisation": "org1",
+				"role":         string(role),
+			},
+		}).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	return auth.Middleware(h, auth.NewJWTAuthenticator(aud, iss, &key.PublicKey), auth.NewAPIKeyAuthenticator(db)), issue
+}
+
+func TestRolesEnforced(t *testing.T) {
+	h, issue := setupOrgUsers(t)
+
+	res := query(t, h, issue(auth.ANALYST), `mutation { AddOutcomeSet(name: "os") { id } }`)
+	assert.NotEmpty(t, res.Errors, "analysts can not edit outcome sets")
+	res = query(t, h, issue(auth.PRACTITIONER), `mutation { AddOutcomeSet(name: "os") { id } }`)
+	assert.NotEmpty(t, res.Errors, "practitioners can not edit outcome sets")
+
+	res = query(t, h, issue(auth.ADMIN), `mutation { AddOutcomeSet(name: "os") { id } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	osID := res.Data["AddOutcomeSet"].(map[string]interface{})["id"].(string)
+
+	meeting := `mutation { AddMeeting(beneficiaryID: "ben1", outcomeSetID: "` + osID + `", conducted: "2017-10-01T12:00:00Z") { id } }`
+	res = query(t, h, issue(auth.ANALYST), meeting)
+	assert.NotEmpty(t, res.Errors, "analysts can not conduct meetings")
+	res = query(t, h, issue(auth.PRACTITIONER), meeting)
+	assert.Len(t, res.Errors, 0)
+
+	res = query(t, h, issue(auth.ANALYST), `{ outcomesets { id } meetings(beneficiary: "ben1") { id } }`)
+	assert.Len(t, res.Errors, 0, "analysts can read")
+	res = query(t, h, issue(auth.Role("unknown")), `{ outcomesets { id } }`)
+	assert.NotEmpty(t, res.Errors)
+}
+
+func TestMe(t *testing.T) {
+	h, issue := setupOrgUsers(t)
+	admin := issue(auth.ADMIN)
+
+	res := query(t, h, admin, `{ me { id role organisation { id } organisations } }`)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	me := res.Data["me"].(map[string]interface{})
+	assert.Equal(t, "admin-user", me["id"])
+	assert.Equal(t, "admin", me["role"])
+	assert.Equal(t, "org1", me["organisation"].(map[string]interface{})["id"])
+	assert.Equal(t, []interface{}{"org1"}, me["organisations"])
+}
+
+func TestEditOrganisation(t *testing.T) {
+	h, issue := setupOrgUsers(t)
+
+	edit := `mutation { EditOrganisation(organisationID: "org1", name: "Renamed", timezone: "Europe/London", locale: "en-GB") { name settings { timezone locale } } }`
+	res := query(t, h, issue(auth.PRACTITIONER), edit)
+	assert.NotEmpty(t, res.Errors, "only admins can edit the organisation")
+
+	res = query(t, h, issue(auth.ADMIN), edit)
+	if !assert.Len(t, res.Errors, 0) {
+		return
+	}
+	org := res.Data["EditOrganisation"].(map[string]interface{})
+	assert.Equal(t, "Renamed", org["name"])
+	assert.Equal(t, "Europe/London", org["settings"].(map[string]interface{})["timezone"])
+
+	res = query(t, h, issue(auth.ADMIN), `mutation { EditOrganisation(organisationID: "org1", name: "Org", timezone: "Mars/Olympus") { name } }`)
+	assert.NotEmpty(t, res.Errors)
+	res = query(t, h, issue(auth.ADMIN), `mutation { EditOrganisation(organisationID: "org1", name: "Org", defaultOutcomeSetID: "unknown") { name } }`)
+	assert.NotEmpty(t, res.Errors)
+}
@@ -18,12 +18,15 @@ func combineFields(toCombine ...graphql.Fields) (graphql.Fields, error) {
 	return final, nil
 }
 
-func (v *v1) getSchema(orgTypes organisationTypes, osTypes outcomeSetTypes, meetTypes meetingTypes, repTypes reportTypes) (*graphql.Schema, error) {
+func (v *v1) getSchema(orgTypes organisationTypes, osTypes outcomeSetTypes, meetTypes meetingTypes, benTypes beneficiaryTypes, repTypes reportTypes, keyTypes apiKeyTypes, audTypes auditTypes) (*graphql.Schema, error) {
 	queries, err := combineFields(
 		v.getMeetingQueries(meetTypes),
 		v.getOrgQueries(orgTypes),
 		v.getOSQueries(osTypes),
 		v.getRepQueries(repTypes),
+		v.getBeneficiaryQueries(meetTypes, benTypes),
+		v.getAPIKeyQueries(keyTypes),
+		v.getAuditQueries(audTypes),
 	)
 	if err != nil {
 		return nil, err
@@ -34,8 +37,12 @@ func (v *v1) getSchema(orgTypes organisationTypes, osTypes outcomeSetTypes, meet
 	})
 
 	mutations, err := combineFields(
-		v.getOSMutations(osTypes),
-		v.getMeetingMutations(meetTypes),
+		v.audited(v.getOSMutations(osTypes), v.outcomeSetSnapshot),
+		v.audited(v.getQuestionMutations(osTypes), v.outcomeSetSnapshot),
+		v.audited(v.getMeetingMutations(meetTypes), v.meetingSnapshot),
+		v.audited(v.getBeneficiaryMutations(benTypes), v.beneficiarySnapshot),
+		v.getOrgMutations(orgTypes),
+		v.getAPIKeyMutations(keyTypes),
 	)
 
 	mutationType := graphql.NewObject(graphql.ObjectConfig{
@@ -48,7 +55,16 @@ func (v *v1) getSchema(orgTypes organisationTypes, osTypes outcomeSetTypes, meet
 		Mutation: mutationType,
 		Types: []graphql.Type{
 			osTypes.likertScale,
+			osTypes.freeTextQuestion,
+			osTypes.yesNoQuestion,
+			osTypes.singleChoiceQuestion,
+			osTypes.multiChoiceQuestion,
+			osTypes.numericQuestion,
 			meetTypes.intAnswer,
+			meetTypes.floatAnswer,
+			meetTypes.stringAnswer,
+			meetTypes.boolAnswer,
+			meetTypes.stringListAnswer,
 		},
 	})
 	if err != nil {
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	"github.com/impactasaurus/server/log"
+	"github.com/impactasaurus/server/logic"
+)
+
+// clients are blocked from exchanging short codes after this many failed attempts within the window
+const shortCodeMaxFailures = 10
+const shortCodeFailureWindow = 10 * time.Minute
+
+type shortCodeHandler struct {
+	db         data.Base
+	authGen    auth.Generator
+	limiter    *failureLimiter
+	trustProxy bool
+}
+
+// NewShortCodeHandler returns a http.Handler which exchanges a short code for a beneficiary JWT.
+// The code is taken from the last segment of the request path, e.g. /v1/code/{code}.
+// If trustProxy is true, the client is identified using the X-Forwarded-For header when rate limiting failed attempts.
+func NewShortCodeHandler(db data.Base, authGen auth.Generator, trustProxy bool) http.Handler {
+	return &shortCodeHandler{
+		db:         db,
+		authGen:    authGen,
+		limiter:    newFailureLimiter(shortCodeMaxFailures, shortCodeFailureWindow),
+		trustProxy: trustProxy,
+	}
+}
+
+func (h *shortCodeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	client := clientAddress(r, h.trustProxy)
+	if h.limiter.Blocked(client) {
+		http.Error(w, "Too many failed attempts, try again later", http.StatusTooManyRequests)
+		return
+	}
+
+	code := logic.NormaliseShortCode(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+	sc, err := h.db.GetShortCode(code)
+	if err != nil && !data.IsNotFound(err) {
+		log.Error(err, nil)
+		http.Error(w, "Failed to lookup short code", http.StatusInternalServerError)
+		return
+	}
+	if err == nil && !sc.IsExpired() {
+		revoked, err := h.db.IsAssessmentRevoked(sc.MeetingID)
+		if err != nil {
+			log.Error(err, nil)
+			http.Error(w, "Failed to lookup short code", http.StatusInternalServerError)
+			return
+		}
+		if !revoked {
+			h.respondWithJWT(w, sc.Beneficiary, sc.MeetingID, time.Until(sc.Expiry))
+			return
+		}
+	}
+
+	// unknown, expired and revoked codes are indistinguishable to prevent the code space from being probed
+	h.limiter.Fail(client)
+	http.Error(w, "Short code not found", http.StatusNotFound)
+}
+
+func (h *shortCodeHandler) respondWithJWT(w http.ResponseWriter, beneficiaryID, meetingID string, expiry time.Duration) {
+	jwt, err := h.authGen.GenerateBeneficiaryJWT(beneficiaryID, meetingID, expiry)
+	if err != nil {
+		log.Error(err, nil)
+		http.Error(w, "Failed to generate JWT", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"JWT": jwt,
+	})
+}
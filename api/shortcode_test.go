@@ -0,0 +1,85 @@
+package api_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/api"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data/memory"
+	"github.com/impactasaurus/server/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupShortCode(t *testing.T, expiry time.Time) (http.Handler, auth.Authenticator, impact.Meeting) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := memory.New(impact.Organisation{ID: "org1", Name: "Org"})
+
+	ctrl := gomock.NewController(t)
+	u := mock.NewMockUser(ctrl)
+	u.EXPECT().Organisation().Return("org1", nil).AnyTimes()
+	u.EXPECT().UserID().Return("user1").AnyTimes()
+
+	meeting, err := db.NewMeeting("ben1", "os", time.Now(), u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.NewShortCode("ABCD2345", meeting.ID, "ben1", expiry, u); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := auth.NewKeyring(key)
+	h := api.NewShortCodeHandler(db, auth.NewBeneficiaryJWTGenerator(aud, iss, keys), false)
+	return h, auth.NewBeneficiaryAuthenticator(aud, iss, keys, db), meeting
+}
+
+func exchange(h http.Handler, code string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/v1/code/"+code, nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestShortCodeExchange(t *testing.T) {
+	h, authenticator, meeting := setupShortCode(t, time.Now().Add(time.Hour))
+
+	rec := exchange(h, "abcd-2345")
+	if !assert.Equal(t, http.StatusOK, rec.Code) {
+		return
+	}
+	res := map[string]string{}
+	assert.Nil(t, json.NewDecoder(rec.Body).Decode(&res))
+
+	u, err := authenticator.AuthUser(res["JWT"])
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "ben1", u.UserID())
+	scope, ok := u.GetAssessmentScope()
+	assert.True(t, ok)
+	assert.Equal(t, meeting.ID, scope)
+}
+
+func TestShortCodeExpired(t *testing.T) {
+	h, _, _ := setupShortCode(t, time.Now().Add(-time.Minute))
+	assert.Equal(t, http.StatusNotFound, exchange(h, "ABCD2345").Code)
+}
+
+func TestShortCodeGuessingLimited(t *testing.T) {
+	h, _, _ := setupShortCode(t, time.Now().Add(time.Hour))
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, http.StatusNotFound, exchange(h, "WRONG234").Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, exchange(h, "ABCD2345").Code, "valid codes should be refused once blocked")
+}
@@ -1,18 +1,73 @@
 package api
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/graphql-go/graphql"
 	"github.com/impactasaurus/server/auth"
 )
 
 type userAuthenticatedResolver func(graphql.ResolveParams, auth.User) (interface{}, error)
 
+var errBeneficiaryDenied = errors.New("Beneficiary users can only access their assessment")
+
+// userRestrictedResolver only allows organisation users to access the resolver
 func userRestrictedResolver(fn userAuthenticatedResolver) graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (interface{}, error) {
 		u, e := auth.GetUser(p.Context)
 		if e != nil {
 			return nil, e
 		}
+		if u.IsBeneficiary() {
+			return nil, errBeneficiaryDenied
+		}
+		return fn(p, u)
+	}
+}
+
+// roleRestrictedResolver only allows organisation users whose role permits the required role to access the resolver
+func roleRestrictedResolver(required auth.Role, fn userAuthenticatedResolver) graphql.FieldResolveFn {
+	return userRestrictedResolver(func(p graphql.ResolveParams, u auth.User) (interface{}, error) {
+		if !u.Role().Permits(required) {
+			return nil, newRoleDeniedError(required)
+		}
 		return fn(p, u)
+	})
+}
+
+func newRoleDeniedError(required auth.Role) error {
+	return fmt.Errorf("User does not have permission, %s role required", required)
+}
+
+type meetingIDGetter func(graphql.ResolveParams) (string, error)
+
+// assessmentRestrictedResolver allows organisation users whose role permits the required role, as well as beneficiary users
+// whose assessment scope matches the meeting ID returned by getMeetingID, to access the resolver.
+// Beneficiary users are provided to the resolver as a member of the meeting's organisation.
+func (v *v1) assessmentRestrictedResolver(required auth.Role, getMeetingID meetingIDGetter, fn userAuthenticatedResolver) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		u, e := auth.GetUser(p.Context)
+		if e != nil {
+			return nil, e
+		}
+		if !u.IsBeneficiary() {
+			if !u.Role().Permits(required) {
+				return nil, newRoleDeniedError(required)
+			}
+			return fn(p, u)
+		}
+		meetingID, err := getMeetingID(p)
+		if err != nil {
+			return nil, err
+		}
+		if scope, ok := u.GetAssessmentScope(); !ok || scope != meetingID {
+			return nil, errBeneficiaryDenied
+		}
+		scopedUser, err := v.getAssessmentUser(u)
+		if err != nil {
+			return nil, err
+		}
+		return fn(p, scopedUser)
 	}
-}
\ No newline at end of file
+}
@@ -12,22 +12,50 @@ import (
 type meetingTypes struct {
 	answerInterface   *graphql.Interface
 	intAnswer         *graphql.Object
+	floatAnswer       *graphql.Object
+	stringAnswer      *graphql.Object
+	boolAnswer        *graphql.Object
+	stringListAnswer  *graphql.Object
 	categoryAggregate *graphql.Object
 	aggregates        *graphql.Object
 	meetingType       *graphql.Object
 	remoteMeetingType *graphql.Object
+	meetingStatusEnum *graphql.Enum
+}
+
+type beneficiaryTypes struct {
+	beneficiaryType       *graphql.Object
+	beneficiaryStatusEnum *graphql.Enum
+	exportFormatEnum      *graphql.Enum
+	beneficiaryExportType *graphql.Object
 }
 
 type organisationTypes struct {
 	organisationType *graphql.Object
+	settingsType     *graphql.Object
+	userType         *graphql.Object
+}
+
+type apiKeyTypes struct {
+	apiKeyType    *graphql.Object
+	newAPIKeyType *graphql.Object
+}
+
+type auditTypes struct {
+	auditEntryType *graphql.Object
 }
 
 type outcomeSetTypes struct {
-	questionInterface *graphql.Interface
-	likertScale       *graphql.Object
-	outcomeSetType    *graphql.Object
-	aggregationEnum   *graphql.Enum
-	categoryType      *graphql.Object
+	questionInterface    *graphql.Interface
+	likertScale          *graphql.Object
+	freeTextQuestion     *graphql.Object
+	yesNoQuestion        *graphql.Object
+	singleChoiceQuestion *graphql.Object
+	multiChoiceQuestion  *graphql.Object
+	numericQuestion      *graphql.Object
+	outcomeSetType       *graphql.Object
+	aggregationEnum      *graphql.Enum
+	categoryType         *graphql.Object
 }
 
 type reportTypes struct {
@@ -35,21 +63,27 @@ type reportTypes struct {
 }
 
 type v1 struct {
-	db      data.Base
-	authGen auth.Generator
+	db                 data.Base
+	authGen            auth.Generator
+	revokeOnCompletion bool
 }
 
 // NewV1 returns a http.Handler which serves the V1 graphql
-func NewV1(db data.Base, authGen auth.Generator) (http.Handler, error) {
+// If revokeOnCompletion is true, beneficiary JWTs are revoked once their meeting is completed
+func NewV1(db data.Base, authGen auth.Generator, revokeOnCompletion bool) (http.Handler, error) {
 	v := &v1{
-		db:      db,
-		authGen: authGen,
+		db:                 db,
+		authGen:            authGen,
+		revokeOnCompletion: revokeOnCompletion,
 	}
 	orgTypes := v.initOrgTypes()
 	osTypes := v.initOutcomeSetTypes(orgTypes)
 	meetTypes := v.initMeetingTypes(orgTypes, osTypes)
+	benTypes := v.initBeneficiaryTypes(meetTypes)
 	repTypes := v.initRepTypes()
-	schema, err := v.getSchema(orgTypes, osTypes, meetTypes, repTypes)
+	keyTypes := v.initAPIKeyTypes()
+	audTypes := v.initAuditTypes()
+	schema, err := v.getSchema(orgTypes, osTypes, meetTypes, benTypes, repTypes, keyTypes, audTypes)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,18 @@
+package server
+
+import "time"
+
+// APIKey allows machine to machine integrations to access an organisation without a user JWT
+type APIKey struct {
+	ID             string `json:"id" bson:"_id"`
+	OrganisationID string `json:"organisationID" bson:"organisationID"`
+	Name           string `json:"name"`
+	// Hash is the SHA-256 hash of the key, the key itself is only available when it is created
+	Hash string `json:"-"`
+	// Scopes are the roles granted to the key
+	Scopes  []string  `json:"scopes"`
+	Created time.Time `json:"created"`
+	// User is the ID of the user who created the key
+	User    string `json:"user"`
+	Revoked bool   `json:"revoked"`
+}
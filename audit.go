@@ -0,0 +1,19 @@
+package server
+
+import "time"
+
+// AuditEntry records a change made to an organisation's data
+type AuditEntry struct {
+	ID             string `json:"id" bson:"_id"`
+	OrganisationID string `json:"organisationID" bson:"organisationID"`
+	// User is the ID of the user who made the change
+	User string `json:"user"`
+	// Mutation is the name of the GraphQL mutation which made the change
+	Mutation string `json:"mutation"`
+	// Arguments, Before and After are JSON encoded.
+	// Before and After are snapshots of the changed outcome set or meeting, null if it did not exist.
+	Arguments string    `json:"arguments"`
+	Before    string    `json:"before"`
+	After     string    `json:"after"`
+	Timestamp time.Time `json:"timestamp"`
+}
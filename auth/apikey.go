@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	impact "github.com/impactasaurus/server"
+)
+
+// APIKeyPrefix starts every API key, allowing API keys to be told apart from JWTs
+const APIKeyPrefix = "ik_"
+
+// APIKeyStore looks up API keys by the hash of the key
+type APIKeyStore interface {
+	GetAPIKeyByHash(hash string) (impact.APIKey, error)
+}
+
+// GenerateAPIKey returns a new random API key along with its hash, only the hash should be stored
+func GenerateAPIKey() (key string, hash string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	key = APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return key, HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the SHA-256 hash of the key.
+// Keys are generated with 256 bits of entropy, so an unsalted fast hash is sufficient.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseScopes checks each scope is a known role, API keys are granted the most permissive role in their scopes
+func ParseScopes(scopes []string) ([]Role, error) {
+	if len(scopes) == 0 {
+		return nil, errors.New("At least one scope is required")
+	}
+	roles := make([]Role, 0, len(scopes))
+	for _, s := range scopes {
+		r := Role(s)
+		if _, ok := roleLevels[r]; !ok {
+			return nil, fmt.Errorf("Unknown scope %s, expecting one of %s, %s or %s", s, ADMIN, PRACTITIONER, ANALYST)
+		}
+		roles = append(roles, r)
+	}
+	return roles, nil
+}
+
+type apiKeyAuth struct {
+	store APIKeyStore
+}
+
+// NewAPIKeyAuthenticator returns an Authenticator which authenticates API keys against the store
+func NewAPIKeyAuthenticator(store APIKeyStore) Authenticator {
+	return &apiKeyAuth{
+		store: store,
+	}
+}
+
+func (a *apiKeyAuth) AuthUser(token string) (User, error) {
+	if !strings.HasPrefix(token, APIKeyPrefix) {
+		return nil, errors.New("Not an API key")
+	}
+	key, err := a.store.GetAPIKeyByHash(HashAPIKey(token))
+	if err != nil {
+		return nil, err
+	}
+	if key.Revoked {
+		return nil, errors.New("API key has been revoked")
+	}
+	return &apiKeyUser{
+		key: key,
+	}, nil
+}
+
+type apiKeyUser struct {
+	key impact.APIKey
+}
+
+// IsAPIKey returns true if the user was authenticated with an API key
+func IsAPIKey(u User) bool {
+	_, ok := u.(*apiKeyUser)
+	if !ok {
+		if selected, isSelected := u.(*orgSelectedUser); isSelected {
+			return IsAPIKey(selected.User)
+		}
+	}
+	return ok
+}
+
+func (a *apiKeyUser) Organisation() (string, error) {
+	return a.key.OrganisationID, nil
+}
+
+func (a *apiKeyUser) Organisations() []string {
+	return []string{a.key.OrganisationID}
+}
+
+func (a *apiKeyUser) UserID() string {
+	return "apikey|" + a.key.ID
+}
+
+func (a *apiKeyUser) IsBeneficiary() bool {
+	return false
+}
+
+func (a *apiKeyUser) GetAssessmentScope() (string, bool) {
+	return "", false
+}
+
+func (a *apiKeyUser) Role() Role {
+	var granted Role
+	for _, s := range a.key.Scopes {
+		r := Role(s)
+		if r.Permits(granted) {
+			granted = r
+		}
+	}
+	return granted
+}
@@ -4,9 +4,11 @@ import "time"
 
 // User is an object which provides details about the user making the request to the API
 type User interface {
-	// Organisation gets the organisation the user belongs to
-	// errors are expected if the user is a beneficiary
+	// Organisation gets the organisation the user is acting within
+	// errors are expected if the user is a beneficiary, or belongs to multiple organisations and has not selected one
 	Organisation() (string, error)
+	// Organisations gets all the organisations the user belongs to
+	Organisations() []string
 	// UserID gets the user's ID within the system
 	// for users this will be their auth0 IDs
 	// for beneficiaries this will be their beneficiary ID
@@ -17,6 +19,9 @@ type User interface {
 	// GetAssessmentScope returns true and the assessment ID if the user is restricted in scope to a single assessment
 	// this is common for beneficiary users
 	GetAssessmentScope() (string, bool)
+	// Role gets the user's role within their organisation
+	// beneficiary users do not have a role
+	Role() Role
 }
 
 // Authenticator takes a JWT, validates the JWT and generates a User object
@@ -28,3 +33,8 @@ type Authenticator interface {
 type Generator interface {
 	GenerateBeneficiaryJWT(benID, meetingID string, expiry time.Duration) (string, error)
 }
+
+// RevocationStore reports whether beneficiary access to an assessment has been revoked
+type RevocationStore interface {
+	IsAssessmentRevoked(meetingID string) (bool, error)
+}
@@ -1,7 +1,6 @@
 package auth
 
 import (
-	"crypto/rsa"
 	"time"
 
 	"errors"
@@ -12,18 +11,18 @@ const beneficiaryKey = "beneficiary"
 const assessmentScopeKey = "scope"
 
 type benGen struct {
-	private *rsa.PrivateKey
-	aud     string
-	iss     string
+	keys *Keyring
+	aud  string
+	iss  string
 }
 
 // NewBeneficiaryJWTGenerator returns a beneficiary JWT generator using the provided
-// audience, issuer and private RSA key
-func NewBeneficiaryJWTGenerator(aud, iss string, private *rsa.PrivateKey) Generator {
+// audience, issuer and the keyring's signing key
+func NewBeneficiaryJWTGenerator(aud, iss string, keys *Keyring) Generator {
 	return &benGen{
-		private: private,
-		aud:     aud,
-		iss:     iss,
+		keys: keys,
+		aud:  aud,
+		iss:  iss,
 	}
 }
 
@@ -41,17 +40,22 @@ func (b *benGen) GenerateBeneficiaryJWT(benID, meetingID string, expiry time.Dur
 			Subject:   benID,
 		},
 	})
-	return token.SignedString(b.private)
+	token.Header["kid"] = b.keys.signingKid
+	return token.SignedString(b.keys.signing)
 }
 
 type benAuth struct {
-	inner Authenticator
+	inner   Authenticator
+	revoked RevocationStore
 }
 
 // NewBeneficiaryAuthenticator returns an Authenticator which authenticates only beneficiary JWTs
-func NewBeneficiaryAuthenticator(aud, iss string, key *rsa.PublicKey) Authenticator {
+// JWTs must be signed by a key in the keyring
+// JWTs scoped to an assessment which has been revoked in the RevocationStore are rejected
+func NewBeneficiaryAuthenticator(aud, iss string, keys *Keyring, revoked RevocationStore) Authenticator {
 	return &benAuth{
-		inner: NewJWTAuthenticator(aud, iss, key),
+		inner:   keys.authenticator(aud, iss),
+		revoked: revoked,
 	}
 }
 
@@ -63,5 +67,14 @@ func (b *benAuth) AuthUser(jwt string) (User, error) {
 	if ben := u.IsBeneficiary(); ben == false {
 		return nil, errors.New("JWT was not a beneficiary JWT")
 	}
+	if meetingID, ok := u.GetAssessmentScope(); ok {
+		revoked, err := b.revoked.IsAssessmentRevoked(meetingID)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			return nil, errors.New("JWT has been revoked")
+		}
+	}
 	return u, err
 }
@@ -3,9 +3,11 @@ package auth_test
 import (
 	"crypto/rand"
 	"crypto/rsa"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	jwtLib "github.com/dgrijalva/jwt-go"
 	"github.com/impactasaurus/server/auth"
 	"github.com/stretchr/testify/assert"
 )
@@ -18,7 +20,7 @@ func getTarget(t *testing.T) (auth.Generator, auth.Authenticator) {
 	bitSize := 2048
 	key, err := rsa.GenerateKey(reader, bitSize)
 	assert.Nil(t, err)
-	return auth.NewBeneficiaryJWTGenerator(aud, iss, key), auth.NewJWTAuthenticator(aud, iss, &key.PublicKey)
+	return auth.NewBeneficiaryJWTGenerator(aud, iss, auth.NewKeyring(key)), auth.NewJWTAuthenticator(aud, iss, &key.PublicKey)
 }
 
 func TestBeneficiaryJWTContent(t *testing.T) {
@@ -48,3 +50,90 @@ func TestExpiry(t *testing.T) {
 	_, err = authenticator.AuthUser(token)
 	assert.NotNil(t, err)
 }
+
+type revocations map[string]bool
+
+func (r revocations) IsAssessmentRevoked(meetingID string) (bool, error) {
+	return r[meetingID], nil
+}
+
+func TestRevokedAssessment(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	target := auth.NewBeneficiaryJWTGenerator(aud, iss, auth.NewKeyring(key))
+	authenticator := auth.NewBeneficiaryAuthenticator(aud, iss, auth.NewKeyring(key), revocations{"revoked": true})
+
+	token, err := target.GenerateBeneficiaryJWT("ben1", "m1", time.Minute)
+	assert.Nil(t, err)
+	_, err = authenticator.AuthUser(token)
+	assert.Nil(t, err)
+
+	token, err = target.GenerateBeneficiaryJWT("ben1", "revoked", time.Minute)
+	assert.Nil(t, err)
+	_, err = authenticator.AuthUser(token)
+	assert.NotNil(t, err)
+}
+
+func TestKeyRotation(t *testing.T) {
+	old, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	current, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+
+	oldToken, err := auth.NewBeneficiaryJWTGenerator(aud, iss, auth.NewKeyring(old)).GenerateBeneficiaryJWT("ben1", "m1", time.Minute)
+	assert.Nil(t, err)
+
+	rotated := auth.NewKeyring(current, &old.PublicKey)
+	newToken, err := auth.NewBeneficiaryJWTGenerator(aud, iss, rotated).GenerateBeneficiaryJWT("ben1", "m2", time.Minute)
+	assert.Nil(t, err)
+
+	authenticator := auth.NewBeneficiaryAuthenticator(aud, iss, rotated, revocations{})
+	_, err = authenticator.AuthUser(oldToken)
+	assert.Nil(t, err, "JWTs signed by a previous key should be accepted")
+	_, err = authenticator.AuthUser(newToken)
+	assert.Nil(t, err)
+
+	_, err = auth.NewBeneficiaryAuthenticator(aud, iss, auth.NewKeyring(current), revocations{}).AuthUser(oldToken)
+	assert.NotNil(t, err, "JWTs signed by a dropped key should be rejected")
+}
+
+func TestKeyringJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	keys := auth.NewKeyring(key)
+	token, err := auth.NewBeneficiaryJWTGenerator(aud, iss, keys).GenerateBeneficiaryJWT("ben1", "m1", time.Minute)
+	assert.Nil(t, err)
+
+	// the served JWKS can be used by others to verify the keyring's JWTs
+	srv := httptest.NewServer(auth.NewJWKSHandler(keys))
+	defer srv.Close()
+	authenticator, err := auth.NewJWKSAuthenticator(aud, iss, auth.NewURLKeySource(srv.URL), time.Hour)
+	if !assert.Nil(t, err) {
+		return
+	}
+	u, err := authenticator.AuthUser(token)
+	assert.Nil(t, err)
+	if u != nil {
+		assert.True(t, u.IsBeneficiary())
+	}
+}
+
+func TestTokenWithoutKid(t *testing.T) {
+	old, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	current, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+
+	// JWTs issued before kid headers were introduced
+	token, err := jwtLib.NewWithClaims(jwtLib.SigningMethodRS256, jwtLib.MapClaims{
+		"aud":          aud,
+		"iss":          iss,
+		"sub":          "ben1",
+		"exp":          time.Now().Add(time.Minute).Unix(),
+		"app_metadata": map[string]interface{}{"beneficiary": true, "scope": "m1"},
+	}).SignedString(old)
+	assert.Nil(t, err)
+
+	_, err = auth.NewBeneficiaryAuthenticator(aud, iss, auth.NewKeyring(current, &old.PublicKey), revocations{}).AuthUser(token)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,233 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/impactasaurus/server/log"
+)
+
+// JWK is a JSON Web Key, only RSA signing keys are supported
+type JWK struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// JWKS is a JSON Web Key Set, as served by identity providers at /.well-known/jwks.json
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWKS returns a JWKS containing the provided RSA public keys, indexed by kid
+func NewJWKS(keys map[string]*rsa.PublicKey) JWKS {
+	set := JWKS{
+		Keys: make([]JWK, 0, len(keys)),
+	}
+	for kid, key := range keys {
+		set.Keys = append(set.Keys, JWK{
+			Kid: kid,
+			Kty: "RSA",
+			Alg: "RS256",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	return set
+}
+
+// PublicKeys returns the set's RSA signing keys indexed by kid, other keys are ignored
+func (s JWKS) PublicKeys() (map[string]*rsa.PublicKey, error) {
+	keys := map[string]*rsa.PublicKey{}
+	for _, k := range s.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode modulus of key %s: %s", k.Kid, err.Error())
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode exponent of key %s: %s", k.Kid, err.Error())
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+// KeySource fetches the current JWKS
+type KeySource interface {
+	FetchJWKS() (JWKS, error)
+}
+
+type fileKeySource string
+
+// NewFileKeySource returns a KeySource which reads the JWKS from a file, the file is reread on every fetch
+func NewFileKeySource(path string) KeySource {
+	return fileKeySource(path)
+}
+
+func (f fileKeySource) FetchJWKS() (JWKS, error) {
+	set := JWKS{}
+	b, err := ioutil.ReadFile(string(f))
+	if err != nil {
+		return set, err
+	}
+	err = json.Unmarshal(b, &set)
+	return set, err
+}
+
+type urlKeySource struct {
+	url    string
+	client *http.Client
+}
+
+// NewURLKeySource returns a KeySource which downloads the JWKS from the provided URL
+func NewURLKeySource(url string) KeySource {
+	return &urlKeySource{
+		url: url,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (u *urlKeySource) FetchJWKS() (JWKS, error) {
+	set := JWKS{}
+	res, err := u.client.Get(u.url)
+	if err != nil {
+		return set, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return set, fmt.Errorf("Failed to fetch JWKS, received status %d", res.StatusCode)
+	}
+	err = json.NewDecoder(res.Body).Decode(&set)
+	return set, err
+}
+
+// StaticKeySource is a KeySource which always returns the same JWKS, intended for tests and local development
+type StaticKeySource JWKS
+
+// FetchJWKS returns the static JWKS
+func (s StaticKeySource) FetchJWKS() (JWKS, error) {
+	return JWKS(s), nil
+}
+
+// unknown kids and failed fetches trigger a refetch at most this often, so tokens with made up kids can not flood the source
+const minRefetchInterval = time.Minute
+
+type keyCache struct {
+	source  KeySource
+	refresh time.Duration
+
+	mutex          sync.RWMutex
+	keys           map[string]*rsa.PublicKey
+	fetched        time.Time
+	attempted      time.Time
+	failed         bool
+	unknownFetched time.Time
+}
+
+// NewJWKSAuthenticator returns an Authenticator which verifies JWTs using the key matching the JWT's kid header.
+// Keys are fetched from the source on creation, then refetched once they are older than refresh
+// or when a JWT references an unknown kid, so keys rotated by the identity provider are picked up without a restart.
+func NewJWKSAuthenticator(aud, iss string, source KeySource, refresh time.Duration) (Authenticator, error) {
+	c := &keyCache{
+		source:  source,
+		refresh: refresh,
+	}
+	if err := c.fetch(); err != nil {
+		return nil, err
+	}
+	return &jwt{
+		aud: aud,
+		iss: iss,
+		key: c.key,
+	}, nil
+}
+
+func (c *keyCache) fetch() error {
+	c.attempted = time.Now()
+	c.failed = true
+	set, err := c.source.FetchJWKS()
+	if err != nil {
+		return err
+	}
+	keys, err := set.PublicKeys()
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return errors.New("JWKS does not contain any RSA signing keys")
+	}
+	c.keys = keys
+	c.fetched = c.attempted
+	c.failed = false
+	return nil
+}
+
+func (c *keyCache) lookup(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" && len(c.keys) == 1 {
+		for _, k := range c.keys {
+			return k, true
+		}
+	}
+	k, ok := c.keys[kid]
+	return k, ok
+}
+
+func (c *keyCache) shouldFetch(known bool) bool {
+	if c.failed && time.Since(c.attempted) < minRefetchInterval {
+		return false
+	}
+	if time.Since(c.fetched) >= c.refresh {
+		return true
+	}
+	return !known && time.Since(c.unknownFetched) >= minRefetchInterval
+}
+
+func (c *keyCache) key(kid string) (*rsa.PublicKey, error) {
+	c.mutex.RLock()
+	k, ok := c.lookup(kid)
+	fresh := time.Since(c.fetched) < c.refresh
+	c.mutex.RUnlock()
+	if ok && fresh {
+		return k, nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	// another request may have refetched whilst waiting for the lock
+	k, ok = c.lookup(kid)
+	if c.shouldFetch(ok) {
+		if !ok {
+			c.unknownFetched = time.Now()
+		}
+		if err := c.fetch(); err != nil {
+			// the previous keys continue to be used until the source recovers
+			log.Error(err, nil)
+		}
+		k, ok = c.lookup(kid)
+	}
+	if !ok {
+		return nil, fmt.Errorf("Unknown signing key: %s", kid)
+	}
+	return k, nil
+}
@@ -0,0 +1,140 @@
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	jwtLib "github.com/dgrijalva/jwt-go"
+	"github.com/impactasaurus/server/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func newKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func sign(t *testing.T, key *rsa.PrivateKey, kid string, meta map[string]interface{}) string {
+	token := jwtLib.NewWithClaims(jwtLib.SigningMethodRS256, jwtLib.MapClaims{
+		"aud":          aud,
+		"iss":          iss,
+		"sub":          "user1",
+		"exp":          time.Now().Add(time.Minute).Unix(),
+		"app_metadata": meta,
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func signWithKid(t *testing.T, key *rsa.PrivateKey, kid string) string {
+	return sign(t, key, kid, map[string]interface{}{})
+}
+
+func signUser(t *testing.T, key *rsa.PrivateKey, meta map[string]interface{}) string {
+	return sign(t, key, "", meta)
+}
+
+// rotatingKeySource returns whichever keys are currently set, mimicking an identity provider rotating keys
+type rotatingKeySource struct {
+	mutex   sync.Mutex
+	keys    map[string]*rsa.PublicKey
+	fetches int
+}
+
+func (r *rotatingKeySource) set(keys map[string]*rsa.PublicKey) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.keys = keys
+}
+
+func (r *rotatingKeySource) FetchJWKS() (auth.JWKS, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.fetches++
+	return auth.NewJWKS(r.keys), nil
+}
+
+func TestJWKSPublicKeys(t *testing.T) {
+	key := newKey(t)
+	keys, err := auth.NewJWKS(map[string]*rsa.PublicKey{"k1": &key.PublicKey}).PublicKeys()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]*rsa.PublicKey{"k1": &key.PublicKey}, keys)
+}
+
+func TestJWKSAuthenticatorSelectsByKid(t *testing.T) {
+	k1, k2 := newKey(t), newKey(t)
+	source := auth.StaticKeySource(auth.NewJWKS(map[string]*rsa.PublicKey{
+		"k1": &k1.PublicKey,
+		"k2": &k2.PublicKey,
+	}))
+	authenticator, err := auth.NewJWKSAuthenticator(aud, iss, source, time.Hour)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	u, err := authenticator.AuthUser(signWithKid(t, k1, "k1"))
+	assert.Nil(t, err)
+	assert.Equal(t, "user1", u.UserID())
+	_, err = authenticator.AuthUser(signWithKid(t, k2, "k2"))
+	assert.Nil(t, err)
+
+	_, err = authenticator.AuthUser(signWithKid(t, k1, "k2"))
+	assert.NotNil(t, err, "signed with a different key to the kid")
+	_, err = authenticator.AuthUser(signWithKid(t, k1, "unknown"))
+	assert.NotNil(t, err)
+	_, err = authenticator.AuthUser(signWithKid(t, k1, ""))
+	assert.NotNil(t, err, "kid is required when the set has multiple keys")
+}
+
+func TestJWKSAuthenticatorRotation(t *testing.T) {
+	old, rotated := newKey(t), newKey(t)
+	source := &rotatingKeySource{}
+	source.set(map[string]*rsa.PublicKey{"old": &old.PublicKey})
+	authenticator, err := auth.NewJWKSAuthenticator(aud, iss, source, time.Hour)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	_, err = authenticator.AuthUser(signWithKid(t, old, "old"))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, source.fetches, "known kids should be served from the cache")
+
+	source.set(map[string]*rsa.PublicKey{"new": &rotated.PublicKey})
+	_, err = authenticator.AuthUser(signWithKid(t, rotated, "new"))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, source.fetches)
+
+	_, err = authenticator.AuthUser(signWithKid(t, rotated, "unknown"))
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, source.fetches, "unknown kids should not trigger repeated fetches")
+}
+
+func TestURLKeySource(t *testing.T) {
+	key := newKey(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(auth.NewJWKS(map[string]*rsa.PublicKey{"k1": &key.PublicKey}))
+	}))
+	defer srv.Close()
+
+	authenticator, err := auth.NewJWKSAuthenticator(aud, iss, auth.NewURLKeySource(srv.URL), time.Hour)
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = authenticator.AuthUser(signWithKid(t, key, "k1"))
+	assert.Nil(t, err)
+}
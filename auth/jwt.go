@@ -8,10 +8,13 @@ import (
 	jwtLib "github.com/dgrijalva/jwt-go"
 )
 
+// keyFunc returns the public key to verify a JWT with, kid is empty if the JWT does not include a kid header
+type keyFunc func(kid string) (*rsa.PublicKey, error)
+
 type jwt struct {
 	aud string
 	iss string
-	key *rsa.PublicKey
+	key keyFunc
 }
 
 type jwtUser struct {
@@ -25,7 +28,9 @@ func NewJWTAuthenticator(aud, iss string, key *rsa.PublicKey) Authenticator {
 	return &jwt{
 		aud: aud,
 		iss: iss,
-		key: key,
+		key: func(string) (*rsa.PublicKey, error) {
+			return key, nil
+		},
 	}
 }
 
@@ -34,7 +39,8 @@ func (j *jwt) AuthUser(jwt string) (User, error) {
 		if _, ok := token.Method.(*jwtLib.SigningMethodRSA); !ok {
 			return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
 		}
-		return j.key, nil
+		kid, _ := token.Header["kid"].(string)
+		return j.key(kid)
 	})
 	if err != nil {
 		return nil, err
@@ -57,11 +63,29 @@ func (j *jwt) AuthUser(jwt string) (User, error) {
 }
 
 func (j *jwtUser) Organisation() (string, error) {
-	org, ok := j.AppMetadata["organisation"].(string)
-	if !ok {
+	orgs := j.Organisations()
+	switch len(orgs) {
+	case 0:
 		return "", errors.New("Failed to extract organisation")
+	case 1:
+		return orgs[0], nil
+	default:
+		return "", ErrOrganisationNotSelected
+	}
+}
+
+func (j *jwtUser) Organisations() []string {
+	orgs := []string{}
+	if org, ok := j.AppMetadata[organisationKey].(string); ok {
+		orgs = append(orgs, org)
+	}
+	list, _ := j.AppMetadata[organisationsKey].([]interface{})
+	for _, o := range list {
+		if org, ok := o.(string); ok && !contains(orgs, org) {
+			orgs = append(orgs, org)
+		}
 	}
-	return org, nil
+	return orgs
 }
 
 func (j *jwtUser) UserID() string {
@@ -76,3 +100,15 @@ func (j *jwtUser) GetAssessmentScope() (string, bool) {
 	assessmentID, ok := j.AppMetadata[assessmentScopeKey].(string)
 	return assessmentID, ok
 }
+
+func (j *jwtUser) Role() Role {
+	if j.IsBeneficiary() {
+		return ""
+	}
+	role, ok := j.AppMetadata[roleKey].(string)
+	if !ok {
+		// users created before roles were introduced retain full access to their organisation
+		return ADMIN
+	}
+	return Role(role)
+}
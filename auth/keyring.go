@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+)
+
+// Keyring holds the key used to sign local JWTs, along with previous signing keys which are still accepted.
+// Keys are identified by their RFC 7638 thumbprint, which is included as the kid header of signed JWTs.
+// To rotate, configure a new signing key and keep the previous key's public key in the keyring
+// until the JWTs it signed have expired.
+type Keyring struct {
+	signingKid string
+	signing    *rsa.PrivateKey
+	public     map[string]*rsa.PublicKey
+}
+
+// NewKeyring returns a Keyring which signs with the provided private key and verifies against it and any previous keys
+func NewKeyring(signing *rsa.PrivateKey, previous ...*rsa.PublicKey) *Keyring {
+	k := &Keyring{
+		signingKid: Thumbprint(&signing.PublicKey),
+		signing:    signing,
+		public:     map[string]*rsa.PublicKey{},
+	}
+	k.public[k.signingKid] = &signing.PublicKey
+	for _, p := range previous {
+		k.public[Thumbprint(p)] = p
+	}
+	return k
+}
+
+// Thumbprint returns the RFC 7638 JWK thumbprint of the public key
+func Thumbprint(key *rsa.PublicKey) string {
+	// members are in lexicographic order with no whitespace, as required by the RFC
+	canonical := fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`,
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		base64.RawURLEncoding.EncodeToString(key.N.Bytes()))
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// FetchJWKS returns the public keys of the keyring, allowing the keyring to be used as a KeySource
+func (k *Keyring) FetchJWKS() (JWKS, error) {
+	return NewJWKS(k.public), nil
+}
+
+func (k *Keyring) publicKey(kid string) (*rsa.PublicKey, error) {
+	key, ok := k.public[kid]
+	if !ok {
+		return nil, fmt.Errorf("Unknown signing key: %s", kid)
+	}
+	return key, nil
+}
+
+type keyringAuth []Authenticator
+
+func (a keyringAuth) AuthUser(jwt string) (User, error) {
+	var firstErr error
+	for _, inner := range a {
+		u, err := inner.AuthUser(jwt)
+		if err == nil {
+			return u, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, firstErr
+}
+
+// authenticator returns an Authenticator which accepts JWTs signed by any key in the keyring
+func (k *Keyring) authenticator(aud, iss string) Authenticator {
+	auths := keyringAuth{&jwt{
+		aud: aud,
+		iss: iss,
+		key: func(kid string) (*rsa.PublicKey, error) {
+			if kid == "" {
+				return nil, errors.New("JWT is missing a kid header")
+			}
+			return k.publicKey(kid)
+		},
+	}}
+	// JWTs issued before kid headers were introduced may have been signed by any key in the keyring
+	for _, key := range k.public {
+		key := key
+		auths = append(auths, &jwt{
+			aud: aud,
+			iss: iss,
+			key: func(kid string) (*rsa.PublicKey, error) {
+				if kid != "" {
+					return nil, errors.New("JWT has a kid header")
+				}
+				return key, nil
+			},
+		})
+	}
+	return auths
+}
+
+// NewJWKSHandler returns a http.Handler which serves the source's JWKS, allowing others to verify locally issued JWTs
+func NewJWKSHandler(source KeySource) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		set, err := source.FetchJWKS()
+		if err != nil {
+			http.Error(w, "Failed to fetch JWKS", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(set)
+	})
+}
@@ -17,6 +17,7 @@ func getUser(jwt string, auth ...Authenticator) (User, error) {
 
 // Middleware creates a http handler middleware which authenticates responses using the provided Authenticators
 // If authentication suceeds, the request will have a context which includes a User object
+// The user acts within the organisation named by the OrganisationHeader, if present
 // If authentication fails, the context will have an authentication error
 func Middleware(next http.Handler, auth ...Authenticator) http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
@@ -26,6 +27,9 @@ func Middleware(next http.Handler, auth ...Authenticator) http.Handler {
 		if strings.HasPrefix(authString, "Bearer ") {
 			jwt := strings.TrimPrefix(authString, "Bearer ")
 			user, err := getUser(jwt, auth...)
+			if org := req.Header.Get(OrganisationHeader); err == nil && org != "" {
+				user, err = SelectOrganisation(user, org)
+			}
 			if err != nil {
 				ctx = newContextWithAuthError(ctx, err)
 			} else {
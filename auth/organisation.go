@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+)
+
+const organisationKey = "organisation"
+
+// users belonging to several organisations list them under this key, alongside or instead of organisationKey
+const organisationsKey = "organisations"
+
+// OrganisationHeader is the request header used to select which of their organisations a user is acting within
+const OrganisationHeader = "X-Organisation"
+
+// ErrOrganisationNotSelected is returned when a user belonging to multiple organisations has not selected one
+var ErrOrganisationNotSelected = errors.New("User belongs to multiple organisations, select one with the " + OrganisationHeader + " header")
+
+type orgSelectedUser struct {
+	User
+	organisation string
+}
+
+func (o *orgSelectedUser) Organisation() (string, error) {
+	return o.organisation, nil
+}
+
+// SelectOrganisation returns the user acting within the provided organisation, which must be one of the user's organisations
+func SelectOrganisation(u User, org string) (User, error) {
+	if !contains(u.Organisations(), org) {
+		return nil, fmt.Errorf("User does not belong to organisation %s", org)
+	}
+	return &orgSelectedUser{
+		User:         u,
+		organisation: org,
+	}, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/impactasaurus/server/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultipleOrganisations(t *testing.T) {
+	key := newKey(t)
+	authenticator := auth.NewJWTAuthenticator(aud, iss, &key.PublicKey)
+
+	u, err := authenticator.AuthUser(signUser(t, key, map[string]interface{}{
+		"organisations": []string{"org1", "org2"},
+	}))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, []string{"org1", "org2"}, u.Organisations())
+	_, err = u.Organisation()
+	assert.Equal(t, auth.ErrOrganisationNotSelected, err)
+
+	selected, err := auth.SelectOrganisation(u, "org2")
+	assert.Nil(t, err)
+	org, err := selected.Organisation()
+	assert.Nil(t, err)
+	assert.Equal(t, "org2", org)
+
+	_, err = auth.SelectOrganisation(u, "org3")
+	assert.NotNil(t, err)
+}
+
+func TestSingleOrganisation(t *testing.T) {
+	key := newKey(t)
+	u, err := auth.NewJWTAuthenticator(aud, iss, &key.PublicKey).AuthUser(signUser(t, key, map[string]interface{}{
+		"organisation": "org1",
+	}))
+	if !assert.Nil(t, err) {
+		return
+	}
+	org, err := u.Organisation()
+	assert.Nil(t, err)
+	assert.Equal(t, "org1", org)
+	assert.Equal(t, []string{"org1"}, u.Organisations())
+}
+
+func TestMiddlewareSelectsOrganisation(t *testing.T) {
+	key := newKey(t)
+	token := signUser(t, key, map[string]interface{}{
+		"organisations": []string{"org1", "org2"},
+	})
+
+	var selected string
+	var authErr error
+	h := auth.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		selected = ""
+		u, err := auth.GetUser(r.Context())
+		authErr = err
+		if err == nil {
+			selected, _ = u.Organisation()
+		}
+	}), auth.NewJWTAuthenticator(aud, iss, &key.PublicKey))
+
+	serve := func(org string) {
+		req := httptest.NewRequest("POST", "/v1/graphql", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		if org != "" {
+			req.Header.Set(auth.OrganisationHeader, org)
+		}
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	serve("org2")
+	assert.Nil(t, authErr)
+	assert.Equal(t, "org2", selected)
+
+	serve("org3")
+	assert.NotNil(t, authErr, "users can not select organisations they do not belong to")
+}
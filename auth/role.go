@@ -0,0 +1,29 @@
+package auth
+
+// Role determines what an organisation user is permitted to do within their organisation
+type Role string
+
+const (
+	// ADMIN users can do everything, including structural edits to outcome sets
+	ADMIN Role = "admin"
+	// PRACTITIONER users can conduct meetings and view reports
+	PRACTITIONER Role = "practitioner"
+	// ANALYST users have read only access, including reports
+	ANALYST Role = "analyst"
+)
+
+const roleKey = "role"
+
+// roles are ordered, each role is permitted to do everything the roles below it can
+var roleLevels = map[Role]int{
+	ANALYST:      1,
+	PRACTITIONER: 2,
+	ADMIN:        3,
+}
+
+// Permits returns true if the role is permitted to do everything the required role can
+// Unknown roles, including the empty role held by beneficiaries, are not permitted anything
+func (r Role) Permits(required Role) bool {
+	level, ok := roleLevels[r]
+	return ok && level >= roleLevels[required]
+}
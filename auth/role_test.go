@@ -0,0 +1,38 @@
+package auth_test
+
+import (
+	"testing"
+
+	"github.com/impactasaurus/server/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRolePermits(t *testing.T) {
+	assert.True(t, auth.ADMIN.Permits(auth.ADMIN))
+	assert.True(t, auth.ADMIN.Permits(auth.ANALYST))
+	assert.True(t, auth.PRACTITIONER.Permits(auth.ANALYST))
+	assert.False(t, auth.PRACTITIONER.Permits(auth.ADMIN))
+	assert.False(t, auth.ANALYST.Permits(auth.PRACTITIONER))
+	assert.False(t, auth.Role("").Permits(auth.ANALYST))
+	assert.False(t, auth.Role("unknown").Permits(auth.ANALYST))
+}
+
+func TestRoleFromAppMetadata(t *testing.T) {
+	key := newKey(t)
+	authenticator := auth.NewJWTAuthenticator(aud, iss, &key.PublicKey)
+	cases := map[string]struct {
+		meta     map[string]interface{}
+		expected auth.Role
+	}{
+		"analyst":     {map[string]interface{}{"role": "analyst"}, auth.ANALYST},
+		"missing":     {map[string]interface{}{}, auth.ADMIN},
+		"beneficiary": {map[string]interface{}{"beneficiary": true, "role": "admin"}, ""},
+	}
+	for name, c := range cases {
+		u, err := authenticator.AuthUser(signUser(t, key, c.meta))
+		if !assert.Nil(t, err, name) {
+			continue
+		}
+		assert.Equal(t, c.expected, u.Role(), name)
+	}
+}
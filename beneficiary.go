@@ -0,0 +1,31 @@
+package server
+
+import "time"
+
+// BeneficiaryStatus tracks whether a beneficiary is part of the organisation's active caseload
+type BeneficiaryStatus string
+
+const (
+	ACTIVE BeneficiaryStatus = "active"
+	CLOSED BeneficiaryStatus = "closed"
+)
+
+// Beneficiary is a person the organisation supports, who is identified within meetings by their ID
+type Beneficiary struct {
+	// ID is chosen by the organisation and is unique within it
+	ID             string            `json:"id" bson:"id"`
+	OrganisationID string            `json:"organisationID" bson:"organisationID"`
+	Created        time.Time         `json:"created"`
+	Tags           []string          `json:"tags"`
+	Status         BeneficiaryStatus `json:"status"`
+}
+
+// HasTag returns true if the beneficiary has been given the tag
+func (b Beneficiary) HasTag(tag string) bool {
+	for _, t := range b.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -1,44 +1,134 @@
 package main
 
 import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
 	"net/http"
-
+	"os"
 	"strconv"
+	"strings"
 
+	impact "github.com/impactasaurus/server"
 	"github.com/impactasaurus/server/api"
 	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	"github.com/impactasaurus/server/data/bolt"
+	"github.com/impactasaurus/server/data/memory"
 	"github.com/impactasaurus/server/data/mongo"
+	"github.com/impactasaurus/server/data/postgres"
 	"github.com/impactasaurus/server/log"
 	corsLib "github.com/rs/cors"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "pseudonymise" {
+		runPseudonymise()
+		return
+	}
+
 	c := mustGetConfiguration()
 
 	mustConfigureLogger(c)
 
-	db, err := mongo.New(c.Mongo.URL, c.Mongo.Port, c.Mongo.Database, c.Mongo.User, c.Mongo.Password)
-	if err != nil {
-		log.Fatal(err, nil)
-	}
+	db := mustGetDatabase(c)
 
-	beneficiaryAuthGen := auth.NewBeneficiaryJWTGenerator(c.Local.Audience, c.Local.Issuer, auth.MustParseRSAPrivateKeyFromPEM(c.Local.PrivateKey))
-	v1Handler, err := api.NewV1(db, beneficiaryAuthGen)
+	localKeys := mustGetLocalKeyring(c)
+	beneficiaryAuthGen := auth.NewBeneficiaryJWTGenerator(c.Local.Audience, c.Local.Issuer, localKeys)
+	v1Handler, err := api.NewV1(db, beneficiaryAuthGen, c.Local.RevokeOnCompletion)
 	if err != nil {
 		log.Fatal(err, nil)
 	}
 
-	auth0Auth := auth.NewJWTAuthenticator(c.Auth0.Audience, c.Auth0.Issuer, auth.MustParseRSAPublicKeyFromPEM(c.Auth0.PublicKey))
-	localAuth := auth.NewBeneficiaryAuthenticator(c.Local.Audience, c.Local.Issuer, auth.MustParseRSAPublicKeyFromPEM(c.Local.PublicKey))
+	auth0Auth := mustGetAuth0Authenticator(c)
+	localAuth := auth.NewBeneficiaryAuthenticator(c.Local.Audience, c.Local.Issuer, localKeys, db)
 	cors := corsLib.New(corsLib.Options{
 		AllowCredentials: true,
-		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type", auth.OrganisationHeader},
 	})
-	http.Handle("/v1/graphql", cors.Handler(auth.Middleware(v1Handler, auth0Auth, localAuth)))
+	http.Handle("/v1/graphql", cors.Handler(auth.Middleware(v1Handler, auth0Auth, localAuth, auth.NewAPIKeyAuthenticator(db))))
+	http.Handle("/.well-known/jwks.json", cors.Handler(auth.NewJWKSHandler(localKeys)))
+	http.Handle("/v1/code/", cors.Handler(api.NewShortCodeHandler(db, beneficiaryAuthGen, c.Network.TrustProxy)))
 
 	http.ListenAndServe(":"+strconv.Itoa(c.Network.Port), nil)
 }
 
+func mustGetDatabase(c *config) data.Base {
+	switch c.Database.Backend {
+	case "mongo":
+		opts := []mongo.Option{}
+		if c.Mongo.PseudonymKey != "" {
+			opts = append(opts, mongo.WithPseudonyms([]byte(c.Mongo.PseudonymKey), c.Mongo.PseudonymDatabase))
+		}
+		db, err := mongo.New(c.Mongo.URL, c.Mongo.Port, c.Mongo.Database, c.Mongo.User, c.Mongo.Password, opts...)
+		if err != nil {
+			log.Fatal(err, nil)
+		}
+		return db
+	case "postgres":
+		db, err := postgres.New(c.Postgres.URL)
+		if err != nil {
+			log.Fatal(err, nil)
+		}
+		return db
+	case "bolt":
+		db, err := bolt.New(c.Bolt.Path, toOrganisations(c.Bolt.Organisations)...)
+		if err != nil {
+			log.Fatal(err, nil)
+		}
+		return db
+	case "memory":
+		log.Info("Using in memory database, data will not be persisted", nil)
+		return memory.New(toOrganisations(c.Database.Organisations)...)
+	default:
+		log.Fatal(fmt.Errorf("Unknown database backend %s", c.Database.Backend), nil)
+		return nil
+	}
+}
+
+func mustGetLocalKeyring(c *config) *auth.Keyring {
+	previous := make([]*rsa.PublicKey, 0, len(c.Local.PreviousKeys))
+	for _, k := range c.Local.PreviousKeys {
+		previous = append(previous, auth.MustParseRSAPublicKeyFromPEM(k))
+	}
+	return auth.NewKeyring(auth.MustParseRSAPrivateKeyFromPEM(c.Local.PrivateKey), previous...)
+}
+
+func mustGetAuth0Authenticator(c *config) auth.Authenticator {
+	if c.Auth0.JWKS == "" {
+		if c.Auth0.PublicKey == "" {
+			log.Fatal(errors.New("Either AUTH0_JWKS or AUTH0_PUBLICKEY must be set"), nil)
+		}
+		return auth.NewJWTAuthenticator(c.Auth0.Audience, c.Auth0.Issuer, auth.MustParseRSAPublicKeyFromPEM(c.Auth0.PublicKey))
+	}
+	var source auth.KeySource
+	if strings.HasPrefix(c.Auth0.JWKS, "http://") || strings.HasPrefix(c.Auth0.JWKS, "https://") {
+		source = auth.NewURLKeySource(c.Auth0.JWKS)
+	} else {
+		source = auth.NewFileKeySource(c.Auth0.JWKS)
+	}
+	a, err := auth.NewJWKSAuthenticator(c.Auth0.Audience, c.Auth0.Issuer, source, c.Auth0.JWKSRefresh)
+	if err != nil {
+		log.Fatal(err, nil)
+	}
+	return a
+}
+
+func toOrganisations(seed map[string]string) []impact.Organisation {
+	orgs := make([]impact.Organisation, 0, len(seed))
+	for id, name := range seed {
+		orgs = append(orgs, impact.Organisation{
+			ID:   id,
+			Name: name,
+		})
+	}
+	return orgs
+}
+
 func mustConfigureLogger(c *config) {
 	if c.Sentry.DSN != "" {
 		s, err := log.NewSentryErrorTracker(c.Sentry.DSN)
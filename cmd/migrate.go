@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"strconv"
+
+	"github.com/impactasaurus/server/data/mongo"
+	"github.com/impactasaurus/server/log"
+	"github.com/kelseyhightower/envconfig"
+)
+
+// runMigrate applies pending mongo migrations, e.g. `cmd migrate -dry-run`
+// only the mongo settings are required, the postgres backend migrates itself on startup
+func runMigrate(args []string) {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "list the pending migrations without applying them")
+	flags.Parse(args)
+
+	c := configMongo{}
+	envconfig.MustProcess("MONGO", &c)
+
+	migrations, err := mongo.Migrate(c.URL, c.Port, c.Database, c.User, c.Password, *dryRun)
+	if err != nil {
+		log.Fatal(err, nil)
+	}
+	if *dryRun {
+		for _, m := range migrations {
+			log.Info("Pending mongo migration", map[string]string{
+				"version":     strconv.Itoa(m.Version),
+				"description": m.Description,
+			})
+		}
+	}
+	log.Info("Migrations complete", map[string]string{
+		"count":   strconv.Itoa(len(migrations)),
+		"dry-run": strconv.FormatBool(*dryRun),
+	})
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/impactasaurus/server/data/mongo"
+	"github.com/impactasaurus/server/log"
+	"github.com/kelseyhightower/envconfig"
+)
+
+// runPseudonymise replaces the beneficiary IDs stored in mongo with pseudonyms derived from MONGO_PSEUDONYMKEY
+// it should be run after the key is first set, with the same mongo settings as the server
+func runPseudonymise() {
+	c := configMongo{}
+	envconfig.MustProcess("MONGO", &c)
+	if c.PseudonymKey == "" {
+		log.Fatal(errors.New("MONGO_PSEUDONYMKEY must be set"), nil)
+	}
+
+	updated, err := mongo.Pseudonymise(c.URL, c.Port, c.Database, c.User, c.Password, []byte(c.PseudonymKey), c.PseudonymDatabase)
+	if err != nil {
+		log.Fatal(err, nil)
+	}
+	log.Info("Pseudonymisation complete", map[string]string{
+		"meetings": strconv.Itoa(updated),
+	})
+}
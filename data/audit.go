@@ -0,0 +1,67 @@
+package data
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+)
+
+// AuditFilter restricts the audit entries returned, zero values are ignored
+type AuditFilter struct {
+	Mutation string
+	UserID   string
+	// Start and End are inclusive
+	Start time.Time
+	End   time.Time
+	// Limit is the maximum number of entries to return
+	Limit int
+}
+
+// Matches returns true if the entry passes the filter, the limit is not considered
+func (f AuditFilter) Matches(e impact.AuditEntry) bool {
+	if f.Mutation != "" && e.Mutation != f.Mutation {
+		return false
+	}
+	if f.UserID != "" && e.User != f.UserID {
+		return false
+	}
+	if !f.Start.IsZero() && e.Timestamp.Before(f.Start) {
+		return false
+	}
+	if !f.End.IsZero() && e.Timestamp.After(f.End) {
+		return false
+	}
+	return true
+}
+
+// ReferencesAny returns true if the entry was made by, or its arguments or snapshots hold, any of the IDs.
+// Erasing a beneficiary removes the entries which reference the beneficiary or their meetings.
+func ReferencesAny(e impact.AuditEntry, ids []string) bool {
+	for _, id := range ids {
+		if e.User == id {
+			return true
+		}
+		// arguments and snapshots are JSON encoded, so the quoted ID only matches whole values
+		quoted, err := json.Marshal(id)
+		if err != nil {
+			continue
+		}
+		for _, field := range []string{e.Arguments, e.Before, e.After} {
+			if strings.Contains(field, string(quoted)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AuditLog stores a record of changes made to organisations' data
+type AuditLog interface {
+	// NewAuditEntry stores the entry, the entry's ID and timestamp are set by the store
+	NewAuditEntry(e impact.AuditEntry) (impact.AuditEntry, error)
+	// GetAuditEntries returns the user's organisation's entries which pass the filter, newest first
+	GetAuditEntries(filter AuditFilter, u auth.User) ([]impact.AuditEntry, error)
+}
@@ -0,0 +1,113 @@
+package bolt
+
+import (
+	"time"
+
+	boltLib "github.com/boltdb/bolt"
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	uuid "github.com/satori/go.uuid"
+)
+
+func decodeAPIKey(b []byte) (impact.APIKey, error) {
+	key := impact.APIKey{}
+	if err := decode(b, &key); err != nil {
+		return key, err
+	}
+	if key.Scopes == nil {
+		key.Scopes = []string{}
+	}
+	return key, nil
+}
+
+func (b *bolt) NewAPIKey(name, hash string, scopes []string, u auth.User) (impact.APIKey, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.APIKey{}, err
+	}
+
+	key := impact.APIKey{
+		ID:             uuid.NewV4().String(),
+		OrganisationID: userOrg,
+		Name:           name,
+		Hash:           hash,
+		Scopes:         scopes,
+		Created:        time.Now(),
+		User:           u.UserID(),
+	}
+	if err := b.db.Update(func(tx *boltLib.Tx) error {
+		v, err := encode(key)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(apiKeyBucket).Put([]byte(hash), v)
+	}); err != nil {
+		return impact.APIKey{}, err
+	}
+	return key, nil
+}
+
+func (b *bolt) GetAPIKeys(u auth.User) ([]impact.APIKey, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []impact.APIKey{}
+	err = b.db.View(func(tx *boltLib.Tx) error {
+		return tx.Bucket(apiKeyBucket).ForEach(func(k, v []byte) error {
+			key, err := decodeAPIKey(v)
+			if err != nil {
+				return err
+			}
+			if key.OrganisationID == userOrg {
+				keys = append(keys, key)
+			}
+			return nil
+		})
+	})
+	return keys, err
+}
+
+func (b *bolt) RevokeAPIKey(id string, u auth.User) error {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *boltLib.Tx) error {
+		bucket := tx.Bucket(apiKeyBucket)
+		c := bucket.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			key, err := decodeAPIKey(v)
+			if err != nil {
+				return err
+			}
+			if key.ID != id || key.OrganisationID != userOrg {
+				continue
+			}
+			key.Revoked = true
+			updated, err := encode(key)
+			if err != nil {
+				return err
+			}
+			return bucket.Put(k, updated)
+		}
+		return data.NewNotFoundError("API key")
+	})
+}
+
+func (b *bolt) GetAPIKeyByHash(hash string) (impact.APIKey, error) {
+	var key impact.APIKey
+	err := b.db.View(func(tx *boltLib.Tx) error {
+		v := tx.Bucket(apiKeyBucket).Get([]byte(hash))
+		if v == nil {
+			return data.NewNotFoundError("API key")
+		}
+		var err error
+		key, err = decodeAPIKey(v)
+		return err
+	})
+	return key, err
+}
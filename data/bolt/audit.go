@@ -0,0 +1,93 @@
+package bolt
+
+import (
+	"fmt"
+	"time"
+
+	boltLib "github.com/boltdb/bolt"
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	uuid "github.com/satori/go.uuid"
+)
+
+func (b *bolt) NewAuditEntry(e impact.AuditEntry) (impact.AuditEntry, error) {
+	e.ID = uuid.NewV4().String()
+	e.Timestamp = time.Now()
+
+	if err := b.db.Update(func(tx *boltLib.Tx) error {
+		bucket, err := orgBucket(tx, auditBucket, e.OrganisationID, true)
+		if err != nil {
+			return err
+		}
+		v, err := encode(e)
+		if err != nil {
+			return err
+		}
+		// zero padded so keys sort by time
+		key := fmt.Sprintf("%020d-%s", e.Timestamp.UnixNano(), e.ID)
+		return bucket.Put([]byte(key), v)
+	}); err != nil {
+		return impact.AuditEntry{}, err
+	}
+	return e, nil
+}
+
+func (b *bolt) GetAuditEntries(filter data.AuditFilter, u auth.User) ([]impact.AuditEntry, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []impact.AuditEntry{}
+	err = b.db.View(func(tx *boltLib.Tx) error {
+		bucket, err := orgBucket(tx, auditBucket, userOrg, false)
+		if err != nil || bucket == nil {
+			return err
+		}
+		c := bucket.Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			e := impact.AuditEntry{}
+			if err := decode(v, &e); err != nil {
+				return err
+			}
+			if !filter.Matches(e) {
+				continue
+			}
+			entries = append(entries, e)
+			if filter.Limit > 0 && len(entries) == filter.Limit {
+				return nil
+			}
+		}
+		return nil
+	})
+	return entries, err
+}
+
+// eraseAuditEntries removes the organisation's entries which reference any of the IDs
+func eraseAuditEntries(tx *boltLib.Tx, userOrg string, references []string) error {
+	bucket, err := orgBucket(tx, auditBucket, userOrg, false)
+	if err != nil || bucket == nil {
+		return err
+	}
+	// keys are collected first, as bolt does not allow a bucket to be modified while it is being iterated
+	keys := [][]byte{}
+	if err := bucket.ForEach(func(k, v []byte) error {
+		e := impact.AuditEntry{}
+		if err := decode(v, &e); err != nil {
+			return err
+		}
+		if data.ReferencesAny(e, references) {
+			keys = append(keys, k)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := bucket.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
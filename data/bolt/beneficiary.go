@@ -0,0 +1,304 @@
+package bolt
+
+import (
+	"time"
+
+	boltLib "github.com/boltdb/bolt"
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+)
+
+func decodeBeneficiary(v []byte) (impact.Beneficiary, error) {
+	b := impact.Beneficiary{}
+	if err := decode(v, &b); err != nil {
+		return b, err
+	}
+	if b.Tags == nil {
+		b.Tags = []string{}
+	}
+	return b, nil
+}
+
+func getBeneficiary(tx *boltLib.Tx, id, userOrg string) (impact.Beneficiary, error) {
+	b, err := orgBucket(tx, beneficiaryBucket, userOrg, false)
+	if err != nil {
+		return impact.Beneficiary{}, err
+	}
+	if b == nil {
+		return impact.Beneficiary{}, data.NewNotFoundError("Beneficiary")
+	}
+	v := b.Get([]byte(id))
+	if v == nil {
+		return impact.Beneficiary{}, data.NewNotFoundError("Beneficiary")
+	}
+	return decodeBeneficiary(v)
+}
+
+func putBeneficiary(tx *boltLib.Tx, ben impact.Beneficiary) error {
+	b, err := orgBucket(tx, beneficiaryBucket, ben.OrganisationID, true)
+	if err != nil {
+		return err
+	}
+	v, err := encode(ben)
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(ben.ID), v)
+}
+
+// ensureBeneficiary creates the beneficiary's record if it does not exist
+func ensureBeneficiary(tx *boltLib.Tx, id, userOrg string, created time.Time) error {
+	_, err := getBeneficiary(tx, id, userOrg)
+	if !data.IsNotFound(err) {
+		return err
+	}
+	return putBeneficiary(tx, impact.Beneficiary{
+		ID:             id,
+		OrganisationID: userOrg,
+		Created:        created,
+		Tags:           []string{},
+		Status:         impact.ACTIVE,
+	})
+}
+
+// backfillBeneficiaries creates records for the beneficiaries of meetings stored before beneficiary records were introduced
+func backfillBeneficiaries(tx *boltLib.Tx) error {
+	c := tx.Bucket(meetingBucket).Cursor()
+	for org, v := c.First(); org != nil; org, v = c.Next() {
+		// nested buckets have nil values
+		if v != nil {
+			continue
+		}
+		if err := tx.Bucket(meetingBucket).Bucket(org).ForEach(func(k, v []byte) error {
+			m, err := decodeMeeting(v)
+			if err != nil {
+				return err
+			}
+			return ensureBeneficiary(tx, m.Beneficiary, m.OrganisationID, m.Created)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *bolt) GetBeneficiary(id string, u auth.User) (impact.Beneficiary, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Beneficiary{}, err
+	}
+
+	var ben impact.Beneficiary
+	err = b.db.View(func(tx *boltLib.Tx) error {
+		ben, err = getBeneficiary(tx, id, userOrg)
+		return err
+	})
+	return ben, err
+}
+
+func (b *bolt) GetBeneficiaries(u auth.User) ([]impact.Beneficiary, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	// bolt iterates keys in byte order, so the results are ordered by ID
+	results := []impact.Beneficiary{}
+	err = b.db.View(func(tx *boltLib.Tx) error {
+		bucket, err := orgBucket(tx, beneficiaryBucket, userOrg, false)
+		if err != nil || bucket == nil {
+			return err
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			ben, err := decodeBeneficiary(v)
+			if err != nil {
+				return err
+			}
+			results = append(results, ben)
+			return nil
+		})
+	})
+	return results, err
+}
+
+func (b *bolt) EditBeneficiary(id string, tags []string, status impact.BeneficiaryStatus, u auth.User) (impact.Beneficiary, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Beneficiary{}, err
+	}
+
+	var ben impact.Beneficiary
+	err = b.db.Update(func(tx *boltLib.Tx) error {
+		ben, err = getBeneficiary(tx, id, userOrg)
+		if err != nil {
+			return err
+		}
+		ben.Tags = tags
+		ben.Status = status
+		return putBeneficiary(tx, ben)
+	})
+	return ben, err
+}
+
+func (b *bolt) RenameBeneficiary(id, newID string, u auth.User) (impact.Beneficiary, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Beneficiary{}, err
+	}
+
+	var ben impact.Beneficiary
+	err = b.db.Update(func(tx *boltLib.Tx) error {
+		ben, err = getBeneficiary(tx, id, userOrg)
+		if err != nil {
+			return err
+		}
+		if _, err := getBeneficiary(tx, newID, userOrg); !data.IsNotFound(err) {
+			if err == nil {
+				return data.ErrBeneficiaryExists
+			}
+			return err
+		}
+		bucket, err := orgBucket(tx, beneficiaryBucket, userOrg, false)
+		if err != nil {
+			return err
+		}
+		if err := bucket.Delete([]byte(id)); err != nil {
+			return err
+		}
+		ben.ID = newID
+		if err := putBeneficiary(tx, ben); err != nil {
+			return err
+		}
+
+		meetings, err := orgBucket(tx, meetingBucket, userOrg, false)
+		if err != nil || meetings == nil {
+			return err
+		}
+		// collected first, as bolt does not allow a bucket to be modified while it is being iterated
+		renamed := []impact.Meeting{}
+		if err := meetings.ForEach(func(k, v []byte) error {
+			m, err := decodeMeeting(v)
+			if err != nil {
+				return err
+			}
+			if m.Beneficiary == id {
+				m.Beneficiary = newID
+				m.Modified = time.Now()
+				renamed = append(renamed, m)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		for _, m := range renamed {
+			if err := putMeeting(tx, m); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return ben, err
+}
+
+func (b *bolt) GetAllMeetingsForBeneficiary(beneficiary string, u auth.User) ([]impact.Meeting, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	results := []impact.Meeting{}
+	err = b.db.View(func(tx *boltLib.Tx) error {
+		bucket, err := orgBucket(tx, meetingBucket, userOrg, false)
+		if err != nil || bucket == nil {
+			return err
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			m, err := decodeMeeting(v)
+			if err != nil {
+				return err
+			}
+			if m.Beneficiary == beneficiary {
+				results = append(results, m)
+			}
+			return nil
+		})
+	})
+	return results, err
+}
+
+func (b *bolt) EraseBeneficiary(id string, u auth.User) error {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *boltLib.Tx) error {
+		found := false
+		if bucket, err := orgBucket(tx, beneficiaryBucket, userOrg, false); err != nil {
+			return err
+		} else if bucket != nil && bucket.Get([]byte(id)) != nil {
+			found = true
+			if err := bucket.Delete([]byte(id)); err != nil {
+				return err
+			}
+		}
+
+		// keys are collected first, as bolt does not allow a bucket to be modified while it is being iterated
+		erased := map[string]bool{}
+		meetings, err := orgBucket(tx, meetingBucket, userOrg, false)
+		if err != nil {
+			return err
+		}
+		if meetings != nil {
+			if err := meetings.ForEach(func(k, v []byte) error {
+				m, err := decodeMeeting(v)
+				if err != nil {
+					return err
+				}
+				if m.Beneficiary == id {
+					erased[m.ID] = true
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		if !found && len(erased) == 0 {
+			return data.NewNotFoundError("Beneficiary")
+		}
+
+		codes := [][]byte{}
+		if err := tx.Bucket(shortCodeBucket).ForEach(func(k, v []byte) error {
+			sc := impact.ShortCode{}
+			if err := decode(v, &sc); err != nil {
+				return err
+			}
+			if erased[sc.MeetingID] {
+				codes = append(codes, k)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		for _, code := range codes {
+			if err := tx.Bucket(shortCodeBucket).Delete(code); err != nil {
+				return err
+			}
+		}
+		for meetingID := range erased {
+			if err := meetings.Delete([]byte(meetingID)); err != nil {
+				return err
+			}
+			if err := tx.Bucket(revokedAssessmentBucket).Delete([]byte(meetingID)); err != nil {
+				return err
+			}
+		}
+
+		references := []string{id}
+		for meetingID := range erased {
+			references = append(references, meetingID)
+		}
+		return eraseAuditEntries(tx, userOrg, references)
+	})
+}
@@ -0,0 +1,134 @@
+package bolt
+
+import (
+	"bytes"
+	"encoding/gob"
+	"time"
+
+	boltLib "github.com/boltdb/bolt"
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/data"
+)
+
+// Outcome sets and meetings are stored in a nested bucket per organisation, e.g. outcomesets -> {orgID} -> {id}.
+// This keeps organisation scoping structural, as a user can only ever reach their own organisation's bucket.
+var (
+	outcomeSetBucket = []byte("outcomesets")
+	// published outcome set versions are keyed by outcome set ID then zero padded version, so cursors iterate in version order
+	outcomeSetVersionBucket = []byte("outcomesetversions")
+	meetingBucket           = []byte("meetings")
+	beneficiaryBucket       = []byte("beneficiaries")
+	organisationBucket      = []byte("organisations")
+	// audit entries are also nested per organisation, keyed by timestamp then ID so cursors iterate in time order
+	auditBucket = []byte("auditlog")
+	// revoked assessments are keyed by meeting ID, meeting IDs are unique so are not nested per organisation
+	revokedAssessmentBucket = []byte("revokedassessments")
+	shortCodeBucket         = []byte("shortcodes")
+	// api keys are keyed by hash, as that is how they are looked up when authenticating
+	apiKeyBucket = []byte("apikeys")
+)
+
+type bolt struct {
+	db *boltLib.DB
+}
+
+// New returns a data.Base backed by a single bolt database file at the provided path.
+// The file is created if it does not exist. Any provided organisations are stored, replacing existing entries with the same ID.
+func New(path string, orgs ...impact.Organisation) (data.Base, error) {
+	db, err := boltLib.Open(path, 0600, &boltLib.Options{
+		Timeout: time.Duration(10) * time.Second,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Update(func(tx *boltLib.Tx) error {
+		backfill := tx.Bucket(beneficiaryBucket) == nil
+		versioned := tx.Bucket(outcomeSetVersionBucket) != nil
+		for _, name := range [][]byte{outcomeSetBucket, outcomeSetVersionBucket, meetingBucket, beneficiaryBucket, organisationBucket, revokedAssessmentBucket, shortCodeBucket, apiKeyBucket, auditBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		if backfill {
+			if err := backfillBeneficiaries(tx); err != nil {
+				return err
+			}
+		}
+		if !versioned {
+			if err := backfillOutcomeSetVersions(tx); err != nil {
+				return err
+			}
+		}
+		for _, org := range orgs {
+			v, err := encode(org)
+			if err != nil {
+				return err
+			}
+			if err := tx.Bucket(organisationBucket).Put([]byte(org.ID), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &bolt{
+		db: db,
+	}, nil
+}
+
+// orgBucket returns the organisation's bucket within the collection.
+// nil is returned if the bucket does not exist and create is false.
+func orgBucket(tx *boltLib.Tx, collection []byte, userOrg string, create bool) (*boltLib.Bucket, error) {
+	col := tx.Bucket(collection)
+	if !create {
+		return col.Bucket([]byte(userOrg)), nil
+	}
+	return col.CreateBucketIfNotExists([]byte(userOrg))
+}
+
+// documents are gob encoded, unlike JSON this preserves the int values held in options and answers
+func encode(v interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := gob.NewEncoder(buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decode(b []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(b)).Decode(v)
+}
+
+// gob omits empty values, so slices are restored to match the other backends
+func decodeOutcomeSet(b []byte) (impact.OutcomeSet, error) {
+	os := impact.OutcomeSet{}
+	if err := decode(b, &os); err != nil {
+		return os, err
+	}
+	if os.Questions == nil {
+		os.Questions = []impact.Question{}
+	}
+	if os.Categories == nil {
+		os.Categories = []impact.Category{}
+	}
+	return os, nil
+}
+
+func decodeMeeting(b []byte) (impact.Meeting, error) {
+	m := impact.Meeting{}
+	if err := decode(b, &m); err != nil {
+		return m, err
+	}
+	if m.Answers == nil {
+		m.Answers = []impact.Answer{}
+	}
+	// meetings stored before the completion lifecycle was introduced were all reported on
+	if m.Status == "" {
+		m.Status = impact.COMPLETED
+	}
+	return m, nil
+}
@@ -0,0 +1,32 @@
+package bolt_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/impactasaurus/server/data"
+	"github.com/impactasaurus/server/data/bolt"
+	"github.com/impactasaurus/server/data/conformance"
+)
+
+func TestConformance(t *testing.T) {
+	dir, err := ioutil.TempDir("", "impactasaurus-bolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	conformance.Run(t, func(t *testing.T) data.Base {
+		f, err := ioutil.TempFile(dir, "conformance")
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+		db, err := bolt.New(f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return db
+	})
+}
@@ -0,0 +1,236 @@
+package bolt
+
+import (
+	"time"
+
+	boltLib "github.com/boltdb/bolt"
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	uuid "github.com/satori/go.uuid"
+)
+
+func getMeeting(tx *boltLib.Tx, id, userOrg string) (impact.Meeting, error) {
+	b, err := orgBucket(tx, meetingBucket, userOrg, false)
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+	if b == nil {
+		return impact.Meeting{}, data.NewNotFoundError("Meeting")
+	}
+	v := b.Get([]byte(id))
+	if v == nil {
+		return impact.Meeting{}, data.NewNotFoundError("Meeting")
+	}
+	return decodeMeeting(v)
+}
+
+func putMeeting(tx *boltLib.Tx, m impact.Meeting) error {
+	b, err := orgBucket(tx, meetingBucket, m.OrganisationID, true)
+	if err != nil {
+		return err
+	}
+	v, err := encode(m)
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(m.ID), v)
+}
+
+func (b *bolt) GetMeeting(id string, u auth.User) (impact.Meeting, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+
+	var m impact.Meeting
+	err = b.db.View(func(tx *boltLib.Tx) error {
+		m, err = getMeeting(tx, id, userOrg)
+		if err == nil && m.Deleted {
+			return data.NewNotFoundError("Meeting")
+		}
+		return err
+	})
+	return m, err
+}
+
+func (b *bolt) GetAssessmentMeeting(u auth.User) (impact.Meeting, error) {
+	id, ok := u.GetAssessmentScope()
+	if !ok {
+		return impact.Meeting{}, data.ErrNotScoped
+	}
+
+	var m impact.Meeting
+	err := b.db.View(func(tx *boltLib.Tx) error {
+		// the meeting's organisation is unknown, so each organisation's bucket is checked
+		c := tx.Bucket(meetingBucket).Cursor()
+		for org, v := c.First(); org != nil; org, v = c.Next() {
+			// nested buckets have nil values
+			if v != nil {
+				continue
+			}
+			found, err := getMeeting(tx, id, string(org))
+			if data.IsNotFound(err) {
+				continue
+			}
+			if err == nil && found.Deleted {
+				return data.NewNotFoundError("Meeting")
+			}
+			m = found
+			return err
+		}
+		return data.NewNotFoundError("Meeting")
+	})
+	return m, err
+}
+
+type meetingFilter func(m impact.Meeting) bool
+
+func (b *bolt) getMeetings(include meetingFilter, u auth.User) ([]impact.Meeting, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	results := []impact.Meeting{}
+	err = b.db.View(func(tx *boltLib.Tx) error {
+		bucket, err := orgBucket(tx, meetingBucket, userOrg, false)
+		if err != nil || bucket == nil {
+			return err
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			m, err := decodeMeeting(v)
+			if err != nil {
+				return err
+			}
+			if !m.Deleted && include(m) {
+				results = append(results, m)
+			}
+			return nil
+		})
+	})
+	return results, err
+}
+
+func (b *bolt) GetMeetingsForBeneficiary(beneficiary string, u auth.User) ([]impact.Meeting, error) {
+	return b.getMeetings(func(m impact.Meeting) bool {
+		return m.Beneficiary == beneficiary
+	}, u)
+}
+
+func (b *bolt) GetOSMeetingsForBeneficiary(beneficiary string, outcomeSetID string, u auth.User) ([]impact.Meeting, error) {
+	return b.getMeetings(func(m impact.Meeting) bool {
+		return m.Beneficiary == beneficiary && m.OutcomeSetID == outcomeSetID
+	}, u)
+}
+
+func (b *bolt) GetOSMeetingsInTimeRange(start, end time.Time, outcomeSetID string, u auth.User) ([]impact.Meeting, error) {
+	return b.getMeetings(func(m impact.Meeting) bool {
+		return m.OutcomeSetID == outcomeSetID &&
+			!m.Conducted.Before(start) &&
+			!m.Conducted.After(end)
+	}, u)
+}
+
+func (b *bolt) NewMeeting(beneficiaryID, outcomeSetID string, conducted time.Time, u auth.User) (impact.Meeting, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+
+	meeting := impact.Meeting{
+		ID:             uuid.NewV4().String(),
+		OrganisationID: userOrg,
+		OutcomeSetID:   outcomeSetID,
+		Beneficiary:    beneficiaryID,
+		Answers:        []impact.Answer{},
+		Conducted:      conducted,
+		Created:        time.Now(),
+		Modified:       time.Now(),
+		User:           u.UserID(),
+		Status:         impact.IN_PROGRESS,
+	}
+
+	if err := b.db.Update(func(tx *boltLib.Tx) error {
+		os, err := getOutcomeSet(tx, outcomeSetID, userOrg)
+		if err != nil && !data.IsNotFound(err) {
+			return err
+		}
+		// outcome sets are published by their first meeting, so every meeting is pinned to a version
+		if err == nil && os.Version == 0 {
+			if os, err = publishOutcomeSet(tx, os); err != nil {
+				return err
+			}
+		}
+		meeting.OutcomeSetVersion = os.Version
+		if err := putMeeting(tx, meeting); err != nil {
+			return err
+		}
+		return ensureBeneficiary(tx, beneficiaryID, userOrg, meeting.Created)
+	}); err != nil {
+		return impact.Meeting{}, err
+	}
+	return meeting, nil
+}
+
+// updateMeeting applies fn to the user's meeting and stores the result within a single transaction
+func (b *bolt) updateMeeting(id string, u auth.User, fn func(m *impact.Meeting)) (impact.Meeting, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+
+	var meeting impact.Meeting
+	err = b.db.Update(func(tx *boltLib.Tx) error {
+		meeting, err = getMeeting(tx, id, userOrg)
+		if err != nil {
+			return err
+		}
+		fn(&meeting)
+		meeting.Modified = time.Now()
+		return putMeeting(tx, meeting)
+	})
+	return meeting, err
+}
+
+func (b *bolt) NewAnswer(meetingID string, answer impact.Answer, u auth.User) (impact.Meeting, error) {
+	return b.updateMeeting(meetingID, u, func(m *impact.Meeting) {
+		m.SetAnswer(answer)
+	})
+}
+
+func (b *bolt) EditMeeting(id, beneficiaryID string, conducted time.Time, u auth.User) (impact.Meeting, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+
+	var meeting impact.Meeting
+	err = b.db.Update(func(tx *boltLib.Tx) error {
+		meeting, err = getMeeting(tx, id, userOrg)
+		if err != nil {
+			return err
+		}
+		meeting.Beneficiary = beneficiaryID
+		meeting.Conducted = conducted
+		meeting.Modified = time.Now()
+		if err := putMeeting(tx, meeting); err != nil {
+			return err
+		}
+		return ensureBeneficiary(tx, beneficiaryID, userOrg, meeting.Modified)
+	})
+	return meeting, err
+}
+
+func (b *bolt) DeleteMeeting(id string, u auth.User) error {
+	_, err := b.updateMeeting(id, u, func(m *impact.Meeting) {
+		m.Deleted = true
+	})
+	return err
+}
+
+func (b *bolt) SetMeetingStatus(id string, status impact.MeetingStatus, u auth.User) (impact.Meeting, error) {
+	return b.updateMeeting(id, u, func(m *impact.Meeting) {
+		m.Status = status
+	})
+}
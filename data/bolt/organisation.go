@@ -0,0 +1,90 @@
+package bolt
+
+import (
+	"errors"
+
+	boltLib "github.com/boltdb/bolt"
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+)
+
+func (b *bolt) GetOrganisation(id string, u auth.User) (impact.Organisation, error) {
+	org := impact.Organisation{}
+
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return org, err
+	}
+
+	if id != userOrg {
+		return org, errors.New("User does not have permission to view this organisation")
+	}
+
+	err = b.db.View(func(tx *boltLib.Tx) error {
+		v := tx.Bucket(organisationBucket).Get([]byte(id))
+		if v == nil {
+			return data.NewNotFoundError("Organisation")
+		}
+		return decode(v, &org)
+	})
+	return org, err
+}
+
+func (b *bolt) CreateOrganisation(name string, u auth.User) (impact.Organisation, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Organisation{}, err
+	}
+
+	org := impact.Organisation{
+		ID:   userOrg,
+		Name: name,
+	}
+	if err := b.db.Update(func(tx *boltLib.Tx) error {
+		bucket := tx.Bucket(organisationBucket)
+		if bucket.Get([]byte(org.ID)) != nil {
+			return data.ErrOrganisationExists
+		}
+		v, err := encode(org)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(org.ID), v)
+	}); err != nil {
+		return impact.Organisation{}, err
+	}
+	return org, nil
+}
+
+func (b *bolt) EditOrganisation(id, name string, settings impact.OrganisationSettings, u auth.User) (impact.Organisation, error) {
+	org := impact.Organisation{}
+
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return org, err
+	}
+
+	if id != userOrg {
+		return org, errors.New("User does not have permission to edit this organisation")
+	}
+
+	err = b.db.Update(func(tx *boltLib.Tx) error {
+		bucket := tx.Bucket(organisationBucket)
+		v := bucket.Get([]byte(id))
+		if v == nil {
+			return data.NewNotFoundError("Organisation")
+		}
+		if err := decode(v, &org); err != nil {
+			return err
+		}
+		org.Name = name
+		org.Settings = settings
+		v, err := encode(org)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(id), v)
+	})
+	return org, err
+}
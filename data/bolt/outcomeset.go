@@ -0,0 +1,320 @@
+package bolt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	boltLib "github.com/boltdb/bolt"
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	uuid "github.com/satori/go.uuid"
+)
+
+func getOutcomeSet(tx *boltLib.Tx, id, userOrg string) (impact.OutcomeSet, error) {
+	b, err := orgBucket(tx, outcomeSetBucket, userOrg, false)
+	if err != nil {
+		return impact.OutcomeSet{}, err
+	}
+	if b == nil {
+		return impact.OutcomeSet{}, data.NewNotFoundError("Outcome Set")
+	}
+	v := b.Get([]byte(id))
+	if v == nil {
+		return impact.OutcomeSet{}, data.NewNotFoundError("Outcome Set")
+	}
+	return decodeOutcomeSet(v)
+}
+
+func putOutcomeSet(tx *boltLib.Tx, os impact.OutcomeSet) error {
+	b, err := orgBucket(tx, outcomeSetBucket, os.OrganisationID, true)
+	if err != nil {
+		return err
+	}
+	v, err := encode(os)
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(os.ID), v)
+}
+
+// updateOutcomeSet applies fn to the user's outcome set and stores the result within a single transaction
+func (b *bolt) updateOutcomeSet(id string, u auth.User, fn func(os *impact.OutcomeSet) error) error {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *boltLib.Tx) error {
+		os, err := getOutcomeSet(tx, id, userOrg)
+		if err != nil {
+			return err
+		}
+		if err := fn(&os); err != nil {
+			return err
+		}
+		return putOutcomeSet(tx, os)
+	})
+}
+
+func getOutcomeSets(tx *boltLib.Tx, userOrg string) ([]impact.OutcomeSet, error) {
+	results := []impact.OutcomeSet{}
+	bucket, err := orgBucket(tx, outcomeSetBucket, userOrg, false)
+	if err != nil || bucket == nil {
+		return results, err
+	}
+	err = bucket.ForEach(func(k, v []byte) error {
+		os, err := decodeOutcomeSet(v)
+		if err != nil {
+			return err
+		}
+		results = append(results, os)
+		return nil
+	})
+	return results, err
+}
+
+func (b *bolt) GetOutcomeSet(id string, u auth.User) (impact.OutcomeSet, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.OutcomeSet{}, err
+	}
+
+	var os impact.OutcomeSet
+	err = b.db.View(func(tx *boltLib.Tx) error {
+		os, err = getOutcomeSet(tx, id, userOrg)
+		return err
+	})
+	return os, err
+}
+
+func (b *bolt) GetOutcomeSets(u auth.User) ([]impact.OutcomeSet, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	results := []impact.OutcomeSet{}
+	err = b.db.View(func(tx *boltLib.Tx) error {
+		all, err := getOutcomeSets(tx, userOrg)
+		if err != nil {
+			return err
+		}
+		for _, os := range all {
+			if !os.Deleted {
+				results = append(results, os)
+			}
+		}
+		return nil
+	})
+	return results, err
+}
+
+func (b *bolt) NewOutcomeSet(name, description string, u auth.User) (impact.OutcomeSet, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.OutcomeSet{}, err
+	}
+
+	newOS := impact.OutcomeSet{
+		ID:             uuid.NewV4().String(),
+		Deleted:        false,
+		Description:    description,
+		Name:           name,
+		OrganisationID: userOrg,
+		Questions:      []impact.Question{},
+		Categories:     []impact.Category{},
+	}
+
+	if err := b.db.Update(func(tx *boltLib.Tx) error {
+		existing, err := getOutcomeSets(tx, userOrg)
+		if err != nil {
+			return err
+		}
+		for _, os := range existing {
+			if os.Name == name && !os.Deleted {
+				return errors.New("Name already in use")
+			}
+		}
+		return putOutcomeSet(tx, newOS)
+	}); err != nil {
+		return impact.OutcomeSet{}, err
+	}
+	return newOS, nil
+}
+
+func (b *bolt) EditOutcomeSet(id, name, description string, u auth.User) (impact.OutcomeSet, error) {
+	if err := b.updateOutcomeSet(id, u, func(os *impact.OutcomeSet) error {
+		os.Name = name
+		os.Description = description
+		return nil
+	}); err != nil {
+		return impact.OutcomeSet{}, err
+	}
+	return b.GetOutcomeSet(id, u)
+}
+
+func (b *bolt) DeleteOutcomeSet(id string, u auth.User) error {
+	return b.updateOutcomeSet(id, u, func(os *impact.OutcomeSet) error {
+		os.Deleted = true
+		return nil
+	})
+}
+
+func outcomeSetVersionKey(id string, version int) []byte {
+	return []byte(fmt.Sprintf("%s/%010d", id, version))
+}
+
+func (b *bolt) PublishOutcomeSet(id string, u auth.User) (impact.OutcomeSet, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.OutcomeSet{}, err
+	}
+
+	var os impact.OutcomeSet
+	err = b.db.Update(func(tx *boltLib.Tx) error {
+		os, err = getOutcomeSet(tx, id, userOrg)
+		if err != nil {
+			return err
+		}
+		os, err = publishOutcomeSet(tx, os)
+		return err
+	})
+	return os, err
+}
+
+// publishOutcomeSet stores a copy of the outcome set as its next version, returning the published outcome set
+func publishOutcomeSet(tx *boltLib.Tx, os impact.OutcomeSet) (impact.OutcomeSet, error) {
+	os.Version++
+	os.Published = time.Now()
+	if err := putOutcomeSet(tx, os); err != nil {
+		return os, err
+	}
+	bucket, err := orgBucket(tx, outcomeSetVersionBucket, os.OrganisationID, true)
+	if err != nil {
+		return os, err
+	}
+	v, err := encode(os)
+	if err != nil {
+		return os, err
+	}
+	return os, bucket.Put(outcomeSetVersionKey(os.ID, os.Version), v)
+}
+
+// backfillOutcomeSetVersions publishes the outcome sets which predate versioning and pins their meetings to that version,
+// so later edits do not change the meaning of the meetings' answers
+func backfillOutcomeSetVersions(tx *boltLib.Tx) error {
+	// outcome sets are collected first, as bolt does not allow a bucket to be modified while it is being iterated
+	unpublished := []impact.OutcomeSet{}
+	c := tx.Bucket(outcomeSetBucket).Cursor()
+	for org, v := c.First(); org != nil; org, v = c.Next() {
+		// nested buckets have nil values
+		if v != nil {
+			continue
+		}
+		if err := tx.Bucket(outcomeSetBucket).Bucket(org).ForEach(func(k, v []byte) error {
+			os, err := decodeOutcomeSet(v)
+			if err != nil {
+				return err
+			}
+			if os.Version == 0 {
+				unpublished = append(unpublished, os)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	for _, os := range unpublished {
+		if _, err := publishOutcomeSet(tx, os); err != nil {
+			return err
+		}
+	}
+
+	pinned := []impact.Meeting{}
+	c = tx.Bucket(meetingBucket).Cursor()
+	for org, v := c.First(); org != nil; org, v = c.Next() {
+		if v != nil {
+			continue
+		}
+		if err := tx.Bucket(meetingBucket).Bucket(org).ForEach(func(k, v []byte) error {
+			m, err := decodeMeeting(v)
+			if err != nil {
+				return err
+			}
+			if m.OutcomeSetVersion != 0 {
+				return nil
+			}
+			if _, err := getOutcomeSet(tx, m.OutcomeSetID, m.OrganisationID); err != nil {
+				if data.IsNotFound(err) {
+					return nil
+				}
+				return err
+			}
+			m.OutcomeSetVersion = 1
+			pinned = append(pinned, m)
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	for _, m := range pinned {
+		if err := putMeeting(tx, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *bolt) GetOutcomeSetVersion(id string, version int, u auth.User) (impact.OutcomeSet, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.OutcomeSet{}, err
+	}
+
+	var os impact.OutcomeSet
+	err = b.db.View(func(tx *boltLib.Tx) error {
+		bucket, err := orgBucket(tx, outcomeSetVersionBucket, userOrg, false)
+		if err != nil {
+			return err
+		}
+		var v []byte
+		if bucket != nil {
+			v = bucket.Get(outcomeSetVersionKey(id, version))
+		}
+		if v == nil {
+			return data.NewNotFoundError("Outcome Set Version")
+		}
+		os, err = decodeOutcomeSet(v)
+		return err
+	})
+	return os, err
+}
+
+func (b *bolt) GetOutcomeSetVersions(id string, u auth.User) ([]impact.OutcomeSet, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	results := []impact.OutcomeSet{}
+	err = b.db.View(func(tx *boltLib.Tx) error {
+		bucket, err := orgBucket(tx, outcomeSetVersionBucket, userOrg, false)
+		if err != nil || bucket == nil {
+			return err
+		}
+		prefix := []byte(id + "/")
+		c := bucket.Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			os, err := decodeOutcomeSet(v)
+			if err != nil {
+				return err
+			}
+			results = append(results, os)
+		}
+		return nil
+	})
+	return results, err
+}
@@ -0,0 +1,137 @@
+package bolt
+
+import (
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	uuid "github.com/satori/go.uuid"
+)
+
+func questionIndex(os *impact.OutcomeSet, questionID string) (int, error) {
+	for i, q := range os.Questions {
+		if q.ID == questionID {
+			return i, nil
+		}
+	}
+	return -1, data.NewNotFoundError("Question")
+}
+
+func (b *bolt) GetQuestion(outcomeSetID string, questionID string, u auth.User) (impact.Question, error) {
+	os, err := b.GetOutcomeSet(outcomeSetID, u)
+	if err != nil {
+		return impact.Question{}, err
+	}
+
+	for _, q := range os.Questions {
+		if q.ID == questionID {
+			return q, nil
+		}
+	}
+	return impact.Question{}, data.NewNotFoundError("Question")
+}
+
+func (b *bolt) NewQuestion(outcomeSetID, question, description string, questionType impact.QuestionType, options map[string]interface{}, u auth.User) (impact.Question, error) {
+	newQuestion := impact.Question{
+		ID:          uuid.NewV4().String(),
+		Question:    question,
+		Description: description,
+		Type:        questionType,
+		Options:     options,
+		Deleted:     false,
+	}
+
+	if err := b.updateOutcomeSet(outcomeSetID, u, func(os *impact.OutcomeSet) error {
+		os.Questions = append(os.Questions, newQuestion)
+		return nil
+	}); err != nil {
+		return impact.Question{}, err
+	}
+
+	return b.GetQuestion(outcomeSetID, newQuestion.ID, u)
+}
+
+func (b *bolt) DeleteQuestion(outcomeSetID, questionID string, u auth.User) error {
+	return b.updateOutcomeSet(outcomeSetID, u, func(os *impact.OutcomeSet) error {
+		idx, err := questionIndex(os, questionID)
+		if err != nil {
+			return err
+		}
+		os.Questions[idx].Deleted = true
+		return nil
+	})
+}
+
+func (b *bolt) EditQuestion(outcomeSetID, questionID, question, description string, questionType impact.QuestionType, options map[string]interface{}, u auth.User) (impact.Question, error) {
+	newQ := impact.Question{
+		ID:          questionID,
+		Question:    question,
+		Description: description,
+		Type:        questionType,
+		Options:     options,
+		Deleted:     false,
+	}
+
+	if err := b.updateOutcomeSet(outcomeSetID, u, func(os *impact.OutcomeSet) error {
+		idx, err := questionIndex(os, questionID)
+		if err != nil {
+			return err
+		}
+		os.Questions[idx] = newQ
+		return nil
+	}); err != nil {
+		return impact.Question{}, err
+	}
+	return newQ, nil
+}
+
+func (b *bolt) MoveQuestion(outcomeSetID, questionID string, newIndex uint, u auth.User) error {
+	return b.updateOutcomeSet(outcomeSetID, u, func(os *impact.OutcomeSet) error {
+		oldIdx, err := questionIndex(os, questionID)
+		if err != nil {
+			return err
+		}
+
+		maxIndex := uint(len(os.Questions) - 1)
+		if newIndex > maxIndex {
+			newIndex = maxIndex
+		}
+
+		moving := os.Questions[oldIdx]
+		nonMovingQuestions := make([]impact.Question, 0, len(os.Questions)-1)
+		nonMovingQuestions = append(nonMovingQuestions, os.Questions[:oldIdx]...)
+		nonMovingQuestions = append(nonMovingQuestions, os.Questions[oldIdx+1:]...)
+
+		newQuestions := make([]impact.Question, 0, len(os.Questions))
+		newQuestions = append(newQuestions, nonMovingQuestions[:newIndex]...)
+		newQuestions = append(newQuestions, moving)
+		newQuestions = append(newQuestions, nonMovingQuestions[newIndex:]...)
+		os.Questions = newQuestions
+		return nil
+	})
+}
+
+func (b *bolt) setQuestionCategory(outcomeSetID, questionID, categoryID string, u auth.User) (impact.Question, error) {
+	if err := b.updateOutcomeSet(outcomeSetID, u, func(os *impact.OutcomeSet) error {
+		idx, err := questionIndex(os, questionID)
+		if err != nil {
+			return err
+		}
+		os.Questions[idx].CategoryID = categoryID
+		return nil
+	}); err != nil {
+		return impact.Question{}, err
+	}
+	return b.GetQuestion(outcomeSetID, questionID, u)
+}
+
+func (b *bolt) SetCategory(outcomeSetID, questionID, categoryID string, u auth.User) (impact.Question, error) {
+	_, err := b.GetCategory(outcomeSetID, categoryID, u)
+	if err != nil {
+		return impact.Question{}, data.NewNotFoundError("Category")
+	}
+	return b.setQuestionCategory(outcomeSetID, questionID, categoryID, u)
+}
+
+func (b *bolt) RemoveCategory(outcomeSetID, questionID string, u auth.User) (impact.Question, error) {
+	return b.setQuestionCategory(outcomeSetID, questionID, "", u)
+}
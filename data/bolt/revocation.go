@@ -0,0 +1,35 @@
+package bolt
+
+import (
+	"time"
+
+	boltLib "github.com/boltdb/bolt"
+	"github.com/impactasaurus/server/auth"
+)
+
+func (b *bolt) RevokeAssessment(meetingID string, u auth.User) error {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *boltLib.Tx) error {
+		if _, err := getMeeting(tx, meetingID, userOrg); err != nil {
+			return err
+		}
+		v, err := encode(time.Now())
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(revokedAssessmentBucket).Put([]byte(meetingID), v)
+	})
+}
+
+func (b *bolt) IsAssessmentRevoked(meetingID string) (bool, error) {
+	revoked := false
+	err := b.db.View(func(tx *boltLib.Tx) error {
+		revoked = tx.Bucket(revokedAssessmentBucket).Get([]byte(meetingID)) != nil
+		return nil
+	})
+	return revoked, err
+}
@@ -0,0 +1,51 @@
+package bolt
+
+import (
+	"time"
+
+	boltLib "github.com/boltdb/bolt"
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+)
+
+func (b *bolt) NewShortCode(code, meetingID, beneficiaryID string, expiry time.Time, u auth.User) (impact.ShortCode, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.ShortCode{}, err
+	}
+
+	sc := impact.ShortCode{
+		Code:           code,
+		MeetingID:      meetingID,
+		Beneficiary:    beneficiaryID,
+		OrganisationID: userOrg,
+		Expiry:         expiry,
+	}
+	if err := b.db.Update(func(tx *boltLib.Tx) error {
+		bucket := tx.Bucket(shortCodeBucket)
+		if bucket.Get([]byte(code)) != nil {
+			return data.ErrShortCodeInUse
+		}
+		v, err := encode(sc)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(code), v)
+	}); err != nil {
+		return impact.ShortCode{}, err
+	}
+	return sc, nil
+}
+
+func (b *bolt) GetShortCode(code string) (impact.ShortCode, error) {
+	sc := impact.ShortCode{}
+	err := b.db.View(func(tx *boltLib.Tx) error {
+		v := tx.Bucket(shortCodeBucket).Get([]byte(code))
+		if v == nil {
+			return data.NewNotFoundError("Short code")
+		}
+		return decode(v, &sc)
+	})
+	return sc, err
+}
@@ -0,0 +1,95 @@
+// Package conformance provides a test suite which checks that a data.Base implementation honours the contract
+// expected by the rest of the server. Backends should run the suite from their own tests.
+package conformance
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	"github.com/impactasaurus/server/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// Constructor returns a new, empty data.Base. It is called once per test case.
+type Constructor func(t *testing.T) data.Base
+
+type testCase func(t *testing.T, db data.Base, ctrl *gomock.Controller)
+
+// Run executes the conformance suite against the data.Base returned by the constructor
+func Run(t *testing.T, newBase Constructor) {
+	cases := map[string]testCase{
+		"OutcomeSetOrgIsolation":        testOutcomeSetOrgIsolation,
+		"OutcomeSetSoftDelete":          testOutcomeSetSoftDelete,
+		"OutcomeSetNameInUse":           testOutcomeSetNameInUse,
+		"OutcomeSetEdit":                testOutcomeSetEdit,
+		"PublishOutcomeSet":             testPublishOutcomeSet,
+		"QuestionNotFound":              testQuestionNotFound,
+		"QuestionArchive":               testQuestionArchive,
+		"MoveQuestion":                  testMoveQuestion,
+		"MoveQuestionClampsIndex":       testMoveQuestionClampsIndex,
+		"CategoryNotFound":              testCategoryNotFound,
+		"SetCategory":                   testSetCategory,
+		"DeleteCategoryActivelyUsed":    testDeleteCategoryActivelyUsed,
+		"DeleteCategoryArchivedOnly":    testDeleteCategoryArchivedOnly,
+		"OrganisationPermission":        testOrganisationPermission,
+		"CreateOrganisation":            testCreateOrganisation,
+		"EditOrganisation":              testEditOrganisation,
+		"APIKeys":                       testAPIKeys,
+		"AuditLog":                      testAuditLog,
+		"MeetingOrgIsolation":           testMeetingOrgIsolation,
+		"MeetingsForBeneficiary":        testMeetingsForBeneficiary,
+		"MeetingsInTimeRangeInclusive":  testMeetingsInTimeRangeInclusive,
+		"MeetingsInTimeRangeOutcomeSet": testMeetingsInTimeRangeOutcomeSet,
+		"NewAnswer":                     testNewAnswer,
+		"NewAnswerReplaces":             testNewAnswerReplaces,
+		"QuestionAndAnswerTypes":        testQuestionAndAnswerTypes,
+		"EditMeeting":                   testEditMeeting,
+		"MeetingSoftDelete":             testMeetingSoftDelete,
+		"MeetingStatus":                 testMeetingStatus,
+		"AssessmentMeeting":             testAssessmentMeeting,
+		"BeneficiaryCreatedByMeetings":  testBeneficiaryCreatedByMeetings,
+		"EditBeneficiary":               testEditBeneficiary,
+		"RenameBeneficiary":             testRenameBeneficiary,
+		"AllMeetingsForBeneficiary":     testAllMeetingsForBeneficiary,
+		"EraseBeneficiary":              testEraseBeneficiary,
+		"EraseBeneficiaryAuditLog":      testEraseBeneficiaryAuditLog,
+		"RevokeAssessment":              testRevokeAssessment,
+		"ShortCode":                     testShortCode,
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			c(t, newBase(t), ctrl)
+		})
+	}
+}
+
+func newUser(ctrl *gomock.Controller, org, id string) auth.User {
+	u := mock.NewMockUser(ctrl)
+	u.EXPECT().Organisation().Return(org, nil).AnyTimes()
+	u.EXPECT().UserID().Return(id).AnyTimes()
+	u.EXPECT().IsBeneficiary().Return(false).AnyTimes()
+	u.EXPECT().GetAssessmentScope().Return("", false).AnyTimes()
+	return u
+}
+
+func assertNotFound(t *testing.T, err error) {
+	if assert.NotNil(t, err) {
+		assert.True(t, data.IsNotFound(err), "expected a not found error, got: %s", err.Error())
+	}
+}
+
+// newBeneficiary returns a beneficiary user, who does not belong to an organisation, scoped to the meeting
+func newBeneficiary(ctrl *gomock.Controller, id, meetingID string) auth.User {
+	u := mock.NewMockUser(ctrl)
+	u.EXPECT().Organisation().Return("", errors.New("Failed to extract organisation")).AnyTimes()
+	u.EXPECT().UserID().Return(id).AnyTimes()
+	u.EXPECT().IsBeneficiary().Return(true).AnyTimes()
+	u.EXPECT().GetAssessmentScope().Return(meetingID, true).AnyTimes()
+	return u
+}
@@ -0,0 +1,581 @@
+package conformance
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/data"
+	"github.com/stretchr/testify/assert"
+)
+
+// stored times may lose sub millisecond precision, so whole seconds are used throughout
+var conducted = time.Date(2017, time.October, 1, 12, 0, 0, 0, time.UTC)
+
+func meetingIDs(meetings []impact.Meeting) []string {
+	ids := make([]string, 0, len(meetings))
+	for _, m := range meetings {
+		ids = append(ids, m.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func sortedIDs(ids ...string) []string {
+	sort.Strings(ids)
+	return ids
+}
+
+func testMeetingOrgIsolation(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u1 := newUser(ctrl, "org1", "user1")
+	u2 := newUser(ctrl, "org2", "user2")
+
+	m, err := db.NewMeeting("ben", "os", conducted, u1)
+	assert.Nil(t, err)
+	assert.Equal(t, "org1", m.OrganisationID)
+	assert.Equal(t, "user1", m.User)
+	assert.Equal(t, "ben", m.Beneficiary)
+	assert.Equal(t, "os", m.OutcomeSetID)
+
+	_, err = db.GetMeeting(m.ID, u2)
+	assertNotFound(t, err)
+	_, err = db.GetMeeting("unknown", u1)
+	assertNotFound(t, err)
+
+	_, err = db.NewAnswer(m.ID, impact.Answer{
+		QuestionID: "q",
+		Type:       impact.INT,
+		Answer:     1,
+	}, u2)
+	assert.NotNil(t, err)
+
+	forBen, err := db.GetMeetingsForBeneficiary("ben", u2)
+	assert.Nil(t, err)
+	assert.Len(t, forBen, 0)
+	inRange, err := db.GetOSMeetingsInTimeRange(conducted, conducted, "os", u2)
+	assert.Nil(t, err)
+	assert.Len(t, inRange, 0)
+
+	fetched, err := db.GetMeeting(m.ID, u1)
+	assert.Nil(t, err)
+	assert.Len(t, fetched.Answers, 0)
+}
+
+func testMeetingsForBeneficiary(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u := newUser(ctrl, "org1", "user1")
+
+	m1, err := db.NewMeeting("ben", "os1", conducted, u)
+	assert.Nil(t, err)
+	m2, err := db.NewMeeting("ben", "os2", conducted, u)
+	assert.Nil(t, err)
+	_, err = db.NewMeeting("other", "os1", conducted, u)
+	assert.Nil(t, err)
+
+	all, err := db.GetMeetingsForBeneficiary("ben", u)
+	assert.Nil(t, err)
+	assert.Equal(t, sortedIDs(m1.ID, m2.ID), meetingIDs(all))
+
+	forOS, err := db.GetOSMeetingsForBeneficiary("ben", "os1", u)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{m1.ID}, meetingIDs(forOS))
+}
+
+func testMeetingsInTimeRangeInclusive(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u := newUser(ctrl, "org1", "user1")
+	start := conducted
+	end := conducted.Add(time.Hour * 24)
+
+	newMeeting := func(c time.Time) impact.Meeting {
+		m, err := db.NewMeeting("ben", "os", c, u)
+		assert.Nil(t, err)
+		return m
+	}
+	newMeeting(start.Add(-time.Second))
+	atStart := newMeeting(start)
+	middle := newMeeting(start.Add(time.Hour))
+	atEnd := newMeeting(end)
+	newMeeting(end.Add(time.Second))
+
+	inRange, err := db.GetOSMeetingsInTimeRange(start, end, "os", u)
+	assert.Nil(t, err)
+	assert.Equal(t, sortedIDs(atStart.ID, middle.ID, atEnd.ID), meetingIDs(inRange))
+}
+
+func testMeetingsInTimeRangeOutcomeSet(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u := newUser(ctrl, "org1", "user1")
+
+	m, err := db.NewMeeting("ben", "os1", conducted, u)
+	assert.Nil(t, err)
+	_, err = db.NewMeeting("ben", "os2", conducted, u)
+	assert.Nil(t, err)
+
+	inRange, err := db.GetOSMeetingsInTimeRange(conducted, conducted, "os1", u)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{m.ID}, meetingIDs(inRange))
+}
+
+func testNewAnswer(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u := newUser(ctrl, "org1", "user1")
+
+	m, err := db.NewMeeting("ben", "os", conducted, u)
+	assert.Nil(t, err)
+	_, err = db.NewAnswer("unknown", impact.Answer{
+		QuestionID: "q",
+		Type:       impact.INT,
+		Answer:     1,
+	}, u)
+	assert.NotNil(t, err)
+
+	updated, err := db.NewAnswer(m.ID, impact.Answer{
+		QuestionID: "q",
+		Type:       impact.INT,
+		Answer:     3,
+	}, u)
+	assert.Nil(t, err)
+	a := updated.GetAnswer("q")
+	if assert.NotNil(t, a) {
+		assert.Equal(t, impact.INT, a.Type)
+		v, err := a.ToFloat()
+		assert.Nil(t, err)
+		assert.Equal(t, float32(3), v)
+	}
+
+	fetched, err := db.GetMeeting(m.ID, u)
+	assert.Nil(t, err)
+	assert.Len(t, fetched.Answers, 1)
+}
+
+// testQuestionAndAnswerTypes checks the options and answers of each question type survive being stored
+func testQuestionAndAnswerTypes(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u := newUser(ctrl, "org1", "user1")
+
+	os, err := db.NewOutcomeSet("os", "", u)
+	assert.Nil(t, err)
+	choice, err := db.NewQuestion(os.ID, "Colour?", "", impact.MULTI_CHOICE, map[string]interface{}{
+		"choices": []string{"red", "green"},
+	}, u)
+	assert.Nil(t, err)
+	numeric, err := db.NewQuestion(os.ID, "Hours?", "", impact.NUMERIC, map[string]interface{}{
+		"minValue": 0.5,
+	}, u)
+	assert.Nil(t, err)
+
+	fetchedOS, err := db.GetOutcomeSet(os.ID, u)
+	assert.Nil(t, err)
+	if q := fetchedOS.GetQuestion(choice.ID); assert.NotNil(t, q) {
+		assert.Equal(t, impact.MULTI_CHOICE, q.Type)
+		assert.Equal(t, []string{"red", "green"}, q.Choices())
+	}
+	if q := fetchedOS.GetQuestion(numeric.ID); assert.NotNil(t, q) {
+		min, ok := q.NumericOption("minValue")
+		assert.True(t, ok)
+		assert.Equal(t, 0.5, min)
+		_, ok = q.NumericOption("maxValue")
+		assert.False(t, ok)
+	}
+
+	m, err := db.NewMeeting("ben", os.ID, conducted, u)
+	assert.Nil(t, err)
+	for _, a := range []impact.Answer{
+		{QuestionID: "text", Type: impact.STRING, Answer: "Some notes"},
+		{QuestionID: "yesno", Type: impact.BOOL, Answer: true},
+		{QuestionID: choice.ID, Type: impact.STRING_LIST, Answer: []string{"green"}},
+		{QuestionID: numeric.ID, Type: impact.FLOAT, Answer: 2.0},
+	} {
+		_, err := db.NewAnswer(m.ID, a, u)
+		assert.Nil(t, err)
+	}
+
+	fetched, err := db.GetMeeting(m.ID, u)
+	assert.Nil(t, err)
+	if a := fetched.GetAnswer("text"); assert.NotNil(t, a) {
+		assert.Equal(t, impact.STRING, a.Type)
+		assert.Equal(t, "Some notes", a.Answer)
+	}
+	if a := fetched.GetAnswer("yesno"); assert.NotNil(t, a) {
+		assert.Equal(t, impact.BOOL, a.Type)
+		assert.Equal(t, true, a.Answer)
+	}
+	if a := fetched.GetAnswer(choice.ID); assert.NotNil(t, a) {
+		assert.Equal(t, impact.STRING_LIST, a.Type)
+		assert.Equal(t, []string{"green"}, a.ToStrings())
+	}
+	if a := fetched.GetAnswer(numeric.ID); assert.NotNil(t, a) {
+		assert.True(t, a.IsNumeric())
+		v, err := a.ToFloat()
+		assert.Nil(t, err)
+		assert.Equal(t, float32(2), v)
+	}
+}
+
+func testNewAnswerReplaces(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u := newUser(ctrl, "org1", "user1")
+
+	m, err := db.NewMeeting("ben", "os", conducted, u)
+	assert.Nil(t, err)
+	for _, a := range []impact.Answer{
+		{QuestionID: "q1", Type: impact.INT, Answer: 1},
+		{QuestionID: "q2", Type: impact.INT, Answer: 2},
+		{QuestionID: "q1", Type: impact.INT, Answer: 5},
+	} {
+		_, err = db.NewAnswer(m.ID, a, u)
+		assert.Nil(t, err)
+	}
+
+	fetched, err := db.GetMeeting(m.ID, u)
+	assert.Nil(t, err)
+	if assert.Len(t, fetched.Answers, 2) {
+		assert.Equal(t, "q1", fetched.Answers[0].QuestionID, "a replaced answer should keep its position")
+		v, err := fetched.Answers[0].ToFloat()
+		assert.Nil(t, err)
+		assert.Equal(t, float32(5), v)
+	}
+}
+
+func testEditMeeting(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u1 := newUser(ctrl, "org1", "user1")
+	u2 := newUser(ctrl, "org2", "user2")
+	moved := conducted.Add(time.Hour * 24 * 7)
+
+	m, err := db.NewMeeting("ben", "os", conducted, u1)
+	assert.Nil(t, err)
+
+	_, err = db.EditMeeting(m.ID, "other", moved, u2)
+	assertNotFound(t, err)
+
+	edited, err := db.EditMeeting(m.ID, "other", moved, u1)
+	assert.Nil(t, err)
+	assert.Equal(t, "other", edited.Beneficiary)
+	assert.True(t, moved.Equal(edited.Conducted))
+	assert.Equal(t, "user1", edited.User)
+
+	forBen, err := db.GetMeetingsForBeneficiary("ben", u1)
+	assert.Nil(t, err)
+	assert.Len(t, forBen, 0)
+	inRange, err := db.GetOSMeetingsInTimeRange(moved, moved, "os", u1)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{m.ID}, meetingIDs(inRange))
+}
+
+func testMeetingSoftDelete(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u1 := newUser(ctrl, "org1", "user1")
+	u2 := newUser(ctrl, "org2", "user2")
+
+	m, err := db.NewMeeting("ben", "os", conducted, u1)
+	assert.Nil(t, err)
+
+	assertNotFound(t, db.DeleteMeeting(m.ID, u2))
+	assertNotFound(t, db.DeleteMeeting("unknown", u1))
+	assert.Nil(t, db.DeleteMeeting(m.ID, u1))
+
+	forBen, err := db.GetMeetingsForBeneficiary("ben", u1)
+	assert.Nil(t, err)
+	assert.Len(t, forBen, 0)
+	forOS, err := db.GetOSMeetingsForBeneficiary("ben", "os", u1)
+	assert.Nil(t, err)
+	assert.Len(t, forOS, 0)
+	inRange, err := db.GetOSMeetingsInTimeRange(conducted, conducted, "os", u1)
+	assert.Nil(t, err)
+	assert.Len(t, inRange, 0)
+
+	_, err = db.GetMeeting(m.ID, u1)
+	assertNotFound(t, err)
+	_, err = db.GetAssessmentMeeting(newBeneficiary(ctrl, "ben", m.ID))
+	assertNotFound(t, err)
+}
+
+func testMeetingStatus(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u1 := newUser(ctrl, "org1", "user1")
+	u2 := newUser(ctrl, "org2", "user2")
+
+	m, err := db.NewMeeting("ben", "os", conducted, u1)
+	assert.Nil(t, err)
+	assert.Equal(t, impact.IN_PROGRESS, m.Status)
+
+	_, err = db.SetMeetingStatus(m.ID, impact.COMPLETED, u2)
+	assertNotFound(t, err)
+
+	completed, err := db.SetMeetingStatus(m.ID, impact.COMPLETED, u1)
+	assert.Nil(t, err)
+	assert.Equal(t, impact.COMPLETED, completed.Status)
+
+	fetched, err := db.GetMeeting(m.ID, u1)
+	assert.Nil(t, err)
+	assert.True(t, fetched.IsComplete())
+}
+
+func testAssessmentMeeting(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u := newUser(ctrl, "org1", "user1")
+
+	m, err := db.NewMeeting("ben", "os", conducted, u)
+	assert.Nil(t, err)
+
+	_, err = db.GetAssessmentMeeting(u)
+	assert.Equal(t, data.ErrNotScoped, err)
+	_, err = db.GetAssessmentMeeting(newBeneficiary(ctrl, "ben", "unknown"))
+	assertNotFound(t, err)
+
+	scoped, err := db.GetAssessmentMeeting(newBeneficiary(ctrl, "ben", m.ID))
+	assert.Nil(t, err)
+	assert.Equal(t, m.ID, scoped.ID)
+	assert.Equal(t, "org1", scoped.OrganisationID)
+}
+
+func testRevokeAssessment(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u1 := newUser(ctrl, "org1", "user1")
+	u2 := newUser(ctrl, "org2", "user2")
+
+	m, err := db.NewMeeting("ben", "os", conducted, u1)
+	assert.Nil(t, err)
+	other, err := db.NewMeeting("ben", "os", conducted, u1)
+	assert.Nil(t, err)
+
+	revoked, err := db.IsAssessmentRevoked(m.ID)
+	assert.Nil(t, err)
+	assert.False(t, revoked)
+
+	assertNotFound(t, db.RevokeAssessment(m.ID, u2))
+	assertNotFound(t, db.RevokeAssessment("unknown", u1))
+	assert.Nil(t, db.RevokeAssessment(m.ID, u1))
+	assert.Nil(t, db.RevokeAssessment(m.ID, u1), "revoking twice should succeed")
+
+	revoked, err = db.IsAssessmentRevoked(m.ID)
+	assert.Nil(t, err)
+	assert.True(t, revoked)
+	revoked, err = db.IsAssessmentRevoked(other.ID)
+	assert.Nil(t, err)
+	assert.False(t, revoked)
+}
+
+func testShortCode(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u := newUser(ctrl, "org1", "user1")
+
+	m, err := db.NewMeeting("ben", "os", conducted, u)
+	assert.Nil(t, err)
+
+	_, err = db.GetShortCode("ABCD2345")
+	assertNotFound(t, err)
+
+	expiry := time.Now().Add(time.Hour)
+	_, err = db.NewShortCode("ABCD2345", m.ID, "ben", expiry, u)
+	assert.Nil(t, err)
+	_, err = db.NewShortCode("ABCD2345", m.ID, "ben", expiry, u)
+	assert.Equal(t, data.ErrShortCodeInUse, err)
+
+	sc, err := db.GetShortCode("ABCD2345")
+	assert.Nil(t, err)
+	assert.Equal(t, "ABCD2345", sc.Code)
+	assert.Equal(t, m.ID, sc.MeetingID)
+	assert.Equal(t, "ben", sc.Beneficiary)
+	assert.Equal(t, "org1", sc.OrganisationID)
+	assert.WithinDuration(t, expiry, sc.Expiry, time.Second)
+}
+
+func beneficiaryIDs(beneficiaries []impact.Beneficiary) []string {
+	ids := make([]string, 0, len(beneficiaries))
+	for _, b := range beneficiaries {
+		ids = append(ids, b.ID)
+	}
+	return ids
+}
+
+func testBeneficiaryCreatedByMeetings(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u1 := newUser(ctrl, "org1", "user1")
+	u2 := newUser(ctrl, "org2", "user2")
+
+	_, err := db.GetBeneficiary("ben2", u1)
+	assertNotFound(t, err)
+
+	m, err := db.NewMeeting("ben2", "os", conducted, u1)
+	assert.Nil(t, err)
+	_, err = db.NewMeeting("ben2", "os", conducted, u1)
+	assert.Nil(t, err)
+	_, err = db.EditMeeting(m.ID, "ben1", conducted, u1)
+	assert.Nil(t, err)
+
+	b, err := db.GetBeneficiary("ben2", u1)
+	assert.Nil(t, err)
+	assert.Equal(t, "ben2", b.ID)
+	assert.Equal(t, "org1", b.OrganisationID)
+	assert.Equal(t, impact.ACTIVE, b.Status)
+	assert.Equal(t, []string{}, b.Tags)
+	assert.False(t, b.Created.IsZero())
+
+	all, err := db.GetBeneficiaries(u1)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"ben1", "ben2"}, beneficiaryIDs(all))
+
+	all, err = db.GetBeneficiaries(u2)
+	assert.Nil(t, err)
+	assert.Len(t, all, 0)
+	_, err = db.GetBeneficiary("ben2", u2)
+	assertNotFound(t, err)
+}
+
+func testEditBeneficiary(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u1 := newUser(ctrl, "org1", "user1")
+	u2 := newUser(ctrl, "org2", "user2")
+
+	_, err := db.EditBeneficiary("ben", []string{"a"}, impact.CLOSED, u1)
+	assertNotFound(t, err)
+
+	_, err = db.NewMeeting("ben", "os", conducted, u1)
+	assert.Nil(t, err)
+	_, err = db.EditBeneficiary("ben", []string{"a"}, impact.CLOSED, u2)
+	assertNotFound(t, err)
+
+	b, err := db.EditBeneficiary("ben", []string{"a", "b"}, impact.CLOSED, u1)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, b.Tags)
+	assert.Equal(t, impact.CLOSED, b.Status)
+
+	// meeting the beneficiary again does not reset their record
+	_, err = db.NewMeeting("ben", "os", conducted, u1)
+	assert.Nil(t, err)
+	b, err = db.GetBeneficiary("ben", u1)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, b.Tags)
+	assert.Equal(t, impact.CLOSED, b.Status)
+}
+
+func testRenameBeneficiary(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u1 := newUser(ctrl, "org1", "user1")
+	u2 := newUser(ctrl, "org2", "user2")
+
+	m1, err := db.NewMeeting("ben", "os", conducted, u1)
+	assert.Nil(t, err)
+	m2, err := db.NewMeeting("ben", "os", conducted, u1)
+	assert.Nil(t, err)
+	_, err = db.NewMeeting("other", "os", conducted, u1)
+	assert.Nil(t, err)
+	// another organisation's beneficiary with the same ID is unaffected
+	m3, err := db.NewMeeting("ben", "os", conducted, u2)
+	assert.Nil(t, err)
+	_, err = db.EditBeneficiary("ben", []string{"a"}, impact.ACTIVE, u1)
+	assert.Nil(t, err)
+
+	_, err = db.RenameBeneficiary("ben", "other", u1)
+	assert.Equal(t, data.ErrBeneficiaryExists, err)
+	_, err = db.RenameBeneficiary("ben", "ben", u1)
+	assert.Equal(t, data.ErrBeneficiaryExists, err)
+	_, err = db.RenameBeneficiary("unknown", "new", u1)
+	assertNotFound(t, err)
+
+	b, err := db.RenameBeneficiary("ben", "renamed", u1)
+	assert.Nil(t, err)
+	assert.Equal(t, "renamed", b.ID)
+	assert.Equal(t, []string{"a"}, b.Tags)
+
+	_, err = db.GetBeneficiary("ben", u1)
+	assertNotFound(t, err)
+	meetings, err := db.GetMeetingsForBeneficiary("renamed", u1)
+	assert.Nil(t, err)
+	assert.Equal(t, sortedIDs(m1.ID, m2.ID), meetingIDs(meetings))
+	meetings, err = db.GetMeetingsForBeneficiary("ben", u1)
+	assert.Nil(t, err)
+	assert.Len(t, meetings, 0)
+
+	meetings, err = db.GetMeetingsForBeneficiary("ben", u2)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{m3.ID}, meetingIDs(meetings))
+	_, err = db.GetBeneficiary("ben", u2)
+	assert.Nil(t, err)
+}
+
+func testAllMeetingsForBeneficiary(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u1 := newUser(ctrl, "org1", "user1")
+	u2 := newUser(ctrl, "org2", "user2")
+
+	m1, err := db.NewMeeting("ben", "os", conducted, u1)
+	assert.Nil(t, err)
+	m2, err := db.NewMeeting("ben", "os", conducted, u1)
+	assert.Nil(t, err)
+	_, err = db.NewMeeting("other", "os", conducted, u1)
+	assert.Nil(t, err)
+	assert.Nil(t, db.DeleteMeeting(m2.ID, u1))
+
+	meetings, err := db.GetAllMeetingsForBeneficiary("ben", u1)
+	assert.Nil(t, err)
+	assert.Equal(t, sortedIDs(m1.ID, m2.ID), meetingIDs(meetings))
+
+	meetings, err = db.GetAllMeetingsForBeneficiary("ben", u2)
+	assert.Nil(t, err)
+	assert.Len(t, meetings, 0)
+}
+
+func testEraseBeneficiary(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u1 := newUser(ctrl, "org1", "user1")
+	u2 := newUser(ctrl, "org2", "user2")
+
+	assertNotFound(t, db.EraseBeneficiary("ben", u1))
+
+	m1, err := db.NewMeeting("ben", "os", conducted, u1)
+	assert.Nil(t, err)
+	m2, err := db.NewMeeting("ben", "os", conducted, u1)
+	assert.Nil(t, err)
+	assert.Nil(t, db.DeleteMeeting(m2.ID, u1))
+	_, err = db.NewAnswer(m1.ID, impact.Answer{QuestionID: "q1", Type: impact.INT, Answer: 1}, u1)
+	assert.Nil(t, err)
+	_, err = db.NewShortCode("ABCD2345", m1.ID, "ben", time.Now().Add(time.Hour), u1)
+	assert.Nil(t, err)
+	assert.Nil(t, db.RevokeAssessment(m1.ID, u1))
+	other, err := db.NewMeeting("other", "os", conducted, u1)
+	assert.Nil(t, err)
+	kept, err := db.NewMeeting("ben", "os", conducted, u2)
+	assert.Nil(t, err)
+
+	assert.Nil(t, db.EraseBeneficiary("ben", u1))
+
+	_, err = db.GetBeneficiary("ben", u1)
+	assertNotFound(t, err)
+	meetings, err := db.GetAllMeetingsForBeneficiary("ben", u1)
+	assert.Nil(t, err)
+	assert.Len(t, meetings, 0)
+	_, err = db.GetMeeting(m1.ID, u1)
+	assertNotFound(t, err)
+	_, err = db.GetShortCode("ABCD2345")
+	assertNotFound(t, err)
+	revoked, err := db.IsAssessmentRevoked(m1.ID)
+	assert.Nil(t, err)
+	assert.False(t, revoked)
+	assertNotFound(t, db.EraseBeneficiary("ben", u1))
+
+	_, err = db.GetMeeting(other.ID, u1)
+	assert.Nil(t, err)
+	_, err = db.GetMeeting(kept.ID, u2)
+	assert.Nil(t, err)
+	_, err = db.GetBeneficiary("ben", u2)
+	assert.Nil(t, err)
+}
+
+func testEraseBeneficiaryAuditLog(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u1 := newUser(ctrl, "org1", "user1")
+	u2 := newUser(ctrl, "org2", "user2")
+
+	m, err := db.NewMeeting("ben", "os", conducted, u1)
+	assert.Nil(t, err)
+	for _, e := range []impact.AuditEntry{
+		{OrganisationID: "org1", User: "user1", Mutation: "AddMeeting", Arguments: `{"beneficiaryID":"ben"}`, Before: "null", After: `{"id":"` + m.ID + `"}`},
+		{OrganisationID: "org1", User: "user1", Mutation: "AddLikertAnswer", Arguments: `{"meetingID":"` + m.ID + `"}`, Before: "null", After: "null"},
+		{OrganisationID: "org1", User: "user1", Mutation: "EditMeeting", Arguments: "{}", Before: `{"beneficiary":"ben"}`, After: "null"},
+		{OrganisationID: "org1", User: "ben", Mutation: "CompleteMeeting", Arguments: "{}", Before: "null", After: "null"},
+		{OrganisationID: "org1", User: "user2", Mutation: "AddMeeting", Arguments: `{"beneficiaryID":"benjamin"}`, Before: "null", After: "null"},
+		{OrganisationID: "org2", User: "user2", Mutation: "AddMeeting", Arguments: `{"beneficiaryID":"ben"}`, Before: "null", After: "null"},
+	} {
+		_, err := db.NewAuditEntry(e)
+		assert.Nil(t, err)
+	}
+
+	assert.Nil(t, db.EraseBeneficiary("ben", u1))
+
+	entries, err := db.GetAuditEntries(data.AuditFilter{}, u1)
+	assert.Nil(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "user2", entries[0].User)
+	}
+	entries, err = db.GetAuditEntries(data.AuditFilter{}, u2)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+}
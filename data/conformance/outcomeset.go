@@ -0,0 +1,448 @@
+package conformance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLikertQuestion(t *testing.T, db data.Base, osID, question string, u auth.User) impact.Question {
+	q, err := db.NewQuestion(osID, question, "", impact.LIKERT, map[string]interface{}{
+		"minValue": 1,
+		"maxValue": 5,
+	}, u)
+	assert.Nil(t, err)
+	return q
+}
+
+func questionIDs(os impact.OutcomeSet) []string {
+	ids := make([]string, 0, len(os.Questions))
+	for _, q := range os.Questions {
+		ids = append(ids, q.ID)
+	}
+	return ids
+}
+
+func testOutcomeSetOrgIsolation(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u1 := newUser(ctrl, "org1", "user1")
+	u2 := newUser(ctrl, "org2", "user2")
+
+	os, err := db.NewOutcomeSet("os", "desc", u1)
+	assert.Nil(t, err)
+	assert.Equal(t, "org1", os.OrganisationID)
+
+	_, err = db.GetOutcomeSet(os.ID, u2)
+	assertNotFound(t, err)
+
+	others, err := db.GetOutcomeSets(u2)
+	assert.Nil(t, err)
+	assert.Len(t, others, 0)
+
+	_, err = db.EditOutcomeSet(os.ID, "hijacked", "", u2)
+	assert.NotNil(t, err)
+	assert.NotNil(t, db.DeleteOutcomeSet(os.ID, u2))
+	_, err = db.NewQuestion(os.ID, "q", "", impact.LIKERT, map[string]interface{}{}, u2)
+	assert.NotNil(t, err)
+
+	fetched, err := db.GetOutcomeSet(os.ID, u1)
+	assert.Nil(t, err)
+	assert.Equal(t, "os", fetched.Name)
+	assert.False(t, fetched.Deleted)
+	assert.Len(t, fetched.Questions, 0)
+}
+
+func testOutcomeSetSoftDelete(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u := newUser(ctrl, "org1", "user1")
+
+	os, err := db.NewOutcomeSet("os", "", u)
+	assert.Nil(t, err)
+	assert.Nil(t, db.DeleteOutcomeSet(os.ID, u))
+
+	all, err := db.GetOutcomeSets(u)
+	assert.Nil(t, err)
+	assert.Len(t, all, 0)
+
+	deleted, err := db.GetOutcomeSet(os.ID, u)
+	assert.Nil(t, err)
+	assert.True(t, deleted.Deleted)
+}
+
+func testOutcomeSetNameInUse(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u1 := newUser(ctrl, "org1", "user1")
+	u2 := newUser(ctrl, "org2", "user2")
+
+	os, err := db.NewOutcomeSet("os", "", u1)
+	assert.Nil(t, err)
+	_, err = db.NewOutcomeSet("os", "", u1)
+	assert.NotNil(t, err)
+
+	_, err = db.NewOutcomeSet("os", "", u2)
+	assert.Nil(t, err, "names should only be unique within an organisation")
+
+	assert.Nil(t, db.DeleteOutcomeSet(os.ID, u1))
+	_, err = db.NewOutcomeSet("os", "", u1)
+	assert.Nil(t, err, "names of deleted outcome sets should be reusable")
+}
+
+func testOutcomeSetEdit(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u := newUser(ctrl, "org1", "user1")
+
+	os, err := db.NewOutcomeSet("os", "desc", u)
+	assert.Nil(t, err)
+	edited, err := db.EditOutcomeSet(os.ID, "new", "", u)
+	assert.Nil(t, err)
+	assert.Equal(t, os.ID, edited.ID)
+	assert.Equal(t, "new", edited.Name)
+	assert.Equal(t, "", edited.Description)
+}
+
+func testPublishOutcomeSet(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u1 := newUser(ctrl, "org1", "user1")
+	u2 := newUser(ctrl, "org2", "user2")
+
+	os, err := db.NewOutcomeSet("os", "", u1)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, os.Version)
+	q := newLikertQuestion(t, db, os.ID, "q", u1)
+	m, err := db.NewMeeting("ben", os.ID, time.Now(), u1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, m.OutcomeSetVersion, "the first meeting should publish the outcome set")
+	m, err = db.GetMeeting(m.ID, u1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, m.OutcomeSetVersion)
+	unknown, err := db.NewMeeting("ben", "unknown", time.Now(), u1)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, unknown.OutcomeSetVersion)
+
+	_, err = db.EditQuestion(os.ID, q.ID, "edited", "", impact.LIKERT, map[string]interface{}{
+		"minValue": 1,
+		"maxValue": 10,
+	}, u1)
+	assert.Nil(t, err)
+	draft, err := db.GetOutcomeSet(os.ID, u1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, draft.Version)
+	assert.False(t, draft.Published.IsZero())
+	assert.Equal(t, "edited", draft.GetQuestion(q.ID).Question)
+	m, err = db.NewMeeting("ben", os.ID, time.Now(), u1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, m.OutcomeSetVersion, "edits should not be used until they are published")
+
+	published, err := db.GetOutcomeSetVersion(os.ID, 1, u1)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 1, published.Version)
+		assert.Equal(t, "q", published.GetQuestion(q.ID).Question, "published versions should not be altered by edits")
+		assert.Equal(t, 5, published.GetQuestion(q.ID).Options["maxValue"])
+	}
+
+	v2, err := db.PublishOutcomeSet(os.ID, u1)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, v2.Version)
+	m, err = db.NewMeeting("ben", os.ID, time.Now(), u1)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, m.OutcomeSetVersion)
+	versions, err := db.GetOutcomeSetVersions(os.ID, u1)
+	assert.Nil(t, err)
+	if assert.Len(t, versions, 2) {
+		assert.Equal(t, 1, versions[0].Version)
+		assert.Equal(t, "q", versions[0].GetQuestion(q.ID).Question)
+		assert.Equal(t, 2, versions[1].Version)
+		assert.Equal(t, "edited", versions[1].GetQuestion(q.ID).Question)
+	}
+
+	_, err = db.GetOutcomeSetVersion(os.ID, 3, u1)
+	assertNotFound(t, err)
+	_, err = db.GetOutcomeSetVersion(os.ID, 1, u2)
+	assertNotFound(t, err)
+	_, err = db.PublishOutcomeSet(os.ID, u2)
+	assertNotFound(t, err)
+	_, err = db.PublishOutcomeSet("unknown", u1)
+	assertNotFound(t, err)
+	others, err := db.GetOutcomeSetVersions(os.ID, u2)
+	assert.Nil(t, err)
+	assert.Len(t, others, 0)
+}
+
+func testQuestionNotFound(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u1 := newUser(ctrl, "org1", "user1")
+	u2 := newUser(ctrl, "org2", "user2")
+
+	os, err := db.NewOutcomeSet("os", "", u1)
+	assert.Nil(t, err)
+	q := newLikertQuestion(t, db, os.ID, "q", u1)
+
+	_, err = db.GetQuestion(os.ID, "unknown", u1)
+	assertNotFound(t, err)
+	_, err = db.GetQuestion(os.ID, q.ID, u2)
+	assertNotFound(t, err)
+	assertNotFound(t, db.MoveQuestion(os.ID, "unknown", 0, u1))
+}
+
+func testQuestionArchive(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u := newUser(ctrl, "org1", "user1")
+
+	os, err := db.NewOutcomeSet("os", "", u)
+	assert.Nil(t, err)
+	q1 := newLikertQuestion(t, db, os.ID, "q1", u)
+	q2 := newLikertQuestion(t, db, os.ID, "q2", u)
+	assert.Nil(t, db.DeleteQuestion(os.ID, q1.ID, u))
+
+	os, err = db.GetOutcomeSet(os.ID, u)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{q1.ID, q2.ID}, questionIDs(os), "archived questions should be retained")
+	assert.True(t, os.GetQuestion(q1.ID).Deleted)
+	assert.Len(t, os.ActiveQuestions(), 1)
+}
+
+func testMoveQuestion(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u := newUser(ctrl, "org1", "user1")
+
+	os, err := db.NewOutcomeSet("os", "", u)
+	assert.Nil(t, err)
+	a := newLikertQuestion(t, db, os.ID, "a", u)
+	b := newLikertQuestion(t, db, os.ID, "b", u)
+	c := newLikertQuestion(t, db, os.ID, "c", u)
+
+	assert.Nil(t, db.MoveQuestion(os.ID, c.ID, 0, u))
+	os, err = db.GetOutcomeSet(os.ID, u)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{c.ID, a.ID, b.ID}, questionIDs(os))
+
+	assert.Nil(t, db.MoveQuestion(os.ID, a.ID, 2, u))
+	os, err = db.GetOutcomeSet(os.ID, u)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{c.ID, b.ID, a.ID}, questionIDs(os))
+}
+
+func testMoveQuestionClampsIndex(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u := newUser(ctrl, "org1", "user1")
+
+	os, err := db.NewOutcomeSet("os", "", u)
+	assert.Nil(t, err)
+	a := newLikertQuestion(t, db, os.ID, "a", u)
+	b := newLikertQuestion(t, db, os.ID, "b", u)
+	c := newLikertQuestion(t, db, os.ID, "c", u)
+
+	assert.Nil(t, db.MoveQuestion(os.ID, a.ID, 100, u))
+	os, err = db.GetOutcomeSet(os.ID, u)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{b.ID, c.ID, a.ID}, questionIDs(os))
+}
+
+func testCategoryNotFound(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u := newUser(ctrl, "org1", "user1")
+
+	os, err := db.NewOutcomeSet("os", "", u)
+	assert.Nil(t, err)
+	q := newLikertQuestion(t, db, os.ID, "q", u)
+
+	_, err = db.GetCategory(os.ID, "unknown", u)
+	assertNotFound(t, err)
+	_, err = db.SetCategory(os.ID, q.ID, "unknown", u)
+	assertNotFound(t, err)
+}
+
+func testSetCategory(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u := newUser(ctrl, "org1", "user1")
+
+	os, err := db.NewOutcomeSet("os", "", u)
+	assert.Nil(t, err)
+	q := newLikertQuestion(t, db, os.ID, "q", u)
+	c, err := db.NewCategory(os.ID, "cat", "", impact.MEAN, u)
+	assert.Nil(t, err)
+
+	q, err = db.SetCategory(os.ID, q.ID, c.ID, u)
+	assert.Nil(t, err)
+	assert.Equal(t, c.ID, q.CategoryID)
+
+	q, err = db.RemoveCategory(os.ID, q.ID, u)
+	assert.Nil(t, err)
+	assert.Equal(t, "", q.CategoryID)
+}
+
+func testDeleteCategoryActivelyUsed(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u := newUser(ctrl, "org1", "user1")
+
+	os, err := db.NewOutcomeSet("os", "", u)
+	assert.Nil(t, err)
+	q := newLikertQuestion(t, db, os.ID, "q", u)
+	c, err := db.NewCategory(os.ID, "cat", "", impact.MEAN, u)
+	assert.Nil(t, err)
+	_, err = db.SetCategory(os.ID, q.ID, c.ID, u)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, db.DeleteCategory(os.ID, c.ID, u))
+	_, err = db.GetCategory(os.ID, c.ID, u)
+	assert.Nil(t, err)
+}
+
+func testDeleteCategoryArchivedOnly(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u := newUser(ctrl, "org1", "user1")
+
+	os, err := db.NewOutcomeSet("os", "", u)
+	assert.Nil(t, err)
+	q := newLikertQuestion(t, db, os.ID, "q", u)
+	c, err := db.NewCategory(os.ID, "cat", "", impact.SUM, u)
+	assert.Nil(t, err)
+	_, err = db.SetCategory(os.ID, q.ID, c.ID, u)
+	assert.Nil(t, err)
+	assert.Nil(t, db.DeleteQuestion(os.ID, q.ID, u))
+
+	assert.Nil(t, db.DeleteCategory(os.ID, c.ID, u))
+	_, err = db.GetCategory(os.ID, c.ID, u)
+	assertNotFound(t, err)
+	q, err = db.GetQuestion(os.ID, q.ID, u)
+	assert.Nil(t, err)
+	assert.Equal(t, "", q.CategoryID)
+}
+
+func testOrganisationPermission(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u := newUser(ctrl, "org1", "user1")
+
+	_, err := db.GetOrganisation("org2", u)
+	if assert.NotNil(t, err) {
+		assert.False(t, data.IsNotFound(err), "accessing another organisation should be refused, not reported as missing")
+	}
+	_, err = db.GetOrganisation("org1", u)
+	assertNotFound(t, err)
+}
+
+func testCreateOrganisation(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u := newUser(ctrl, "org1", "user1")
+
+	org, err := db.CreateOrganisation("Org", u)
+	assert.Nil(t, err)
+	assert.Equal(t, "org1", org.ID)
+	_, err = db.CreateOrganisation("Again", u)
+	assert.Equal(t, data.ErrOrganisationExists, err)
+
+	fetched, err := db.GetOrganisation("org1", u)
+	assert.Nil(t, err)
+	assert.Equal(t, "Org", fetched.Name)
+}
+
+func testEditOrganisation(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u1 := newUser(ctrl, "org1", "user1")
+	u2 := newUser(ctrl, "org2", "user2")
+
+	_, err := db.EditOrganisation("org1", "Org", impact.OrganisationSettings{}, u1)
+	assertNotFound(t, err)
+	_, err = db.CreateOrganisation("Org", u1)
+	assert.Nil(t, err)
+
+	settings := impact.OrganisationSettings{
+		Timezone:            "Europe/London",
+		DefaultOutcomeSetID: "os1",
+		Locale:              "en-GB",
+	}
+	_, err = db.EditOrganisation("org1", "Renamed", settings, u2)
+	if assert.NotNil(t, err) {
+		assert.False(t, data.IsNotFound(err), "editing another organisation should be refused, not reported as missing")
+	}
+
+	edited, err := db.EditOrganisation("org1", "Renamed", settings, u1)
+	assert.Nil(t, err)
+	assert.Equal(t, "Renamed", edited.Name)
+	assert.Equal(t, settings, edited.Settings)
+
+	fetched, err := db.GetOrganisation("org1", u1)
+	assert.Nil(t, err)
+	assert.Equal(t, edited, fetched)
+}
+
+func testAPIKeys(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u1 := newUser(ctrl, "org1", "user1")
+	u2 := newUser(ctrl, "org2", "user2")
+
+	_, err := db.GetAPIKeyByHash("hash")
+	assertNotFound(t, err)
+
+	key, err := db.NewAPIKey("crm", "hash", []string{"analyst"}, u1)
+	assert.Nil(t, err)
+	assert.Equal(t, "org1", key.OrganisationID)
+	assert.Equal(t, "user1", key.User)
+
+	fetched, err := db.GetAPIKeyByHash("hash")
+	assert.Nil(t, err)
+	assert.Equal(t, key.ID, fetched.ID)
+	assert.Equal(t, []string{"analyst"}, fetched.Scopes)
+	assert.False(t, fetched.Revoked)
+
+	keys, err := db.GetAPIKeys(u2)
+	assert.Nil(t, err)
+	assert.Len(t, keys, 0)
+	assertNotFound(t, db.RevokeAPIKey(key.ID, u2))
+
+	assert.Nil(t, db.RevokeAPIKey(key.ID, u1))
+	keys, err = db.GetAPIKeys(u1)
+	assert.Nil(t, err)
+	if assert.Len(t, keys, 1) {
+		assert.True(t, keys[0].Revoked)
+	}
+}
+
+func testAuditLog(t *testing.T, db data.Base, ctrl *gomock.Controller) {
+	u1 := newUser(ctrl, "org1", "user1")
+	u2 := newUser(ctrl, "org2", "user2")
+
+	start := time.Now()
+	for _, e := range []impact.AuditEntry{
+		{OrganisationID: "org1", User: "user1", Mutation: "AddOutcomeSet", Arguments: `{"name":"a"}`, Before: "null", After: `{"id":"os1"}`},
+		{OrganisationID: "org1", User: "user2", Mutation: "EditOutcomeSet", Arguments: "{}", Before: `{"id":"os1"}`, After: `{"id":"os1"}`},
+		{OrganisationID: "org2", User: "user2", Mutation: "AddOutcomeSet", Arguments: "{}", Before: "null", After: "null"},
+		{OrganisationID: "org1", User: "user1", Mutation: "DeleteOutcomeSet", Arguments: "{}", Before: `{"id":"os1"}`, After: "null"},
+	} {
+		saved, err := db.NewAuditEntry(e)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, saved.ID)
+		assert.False(t, saved.Timestamp.IsZero())
+		// keeps the entries' timestamps distinct at the precision of every backend
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	entries, err := db.GetAuditEntries(data.AuditFilter{}, u1)
+	assert.Nil(t, err)
+	if assert.Len(t, entries, 3) {
+		assert.Equal(t, "DeleteOutcomeSet", entries[0].Mutation)
+		assert.Equal(t, "EditOutcomeSet", entries[1].Mutation)
+		assert.Equal(t, "AddOutcomeSet", entries[2].Mutation)
+		assert.Equal(t, "org1", entries[2].OrganisationID)
+		assert.Equal(t, "user1", entries[2].User)
+		assert.JSONEq(t, `{"name":"a"}`, entries[2].Arguments)
+		assert.JSONEq(t, "null", entries[2].Before)
+		assert.JSONEq(t, `{"id":"os1"}`, entries[2].After)
+	}
+
+	entries, err = db.GetAuditEntries(data.AuditFilter{}, u2)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+
+	entries, err = db.GetAuditEntries(data.AuditFilter{Mutation: "AddOutcomeSet"}, u1)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+
+	entries, err = db.GetAuditEntries(data.AuditFilter{UserID: "user2"}, u1)
+	assert.Nil(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "EditOutcomeSet", entries[0].Mutation)
+	}
+
+	entries, err = db.GetAuditEntries(data.AuditFilter{Limit: 2}, u1)
+	assert.Nil(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, "DeleteOutcomeSet", entries[0].Mutation)
+	}
+
+	entries, err = db.GetAuditEntries(data.AuditFilter{Start: start.Add(-time.Minute), End: start.Add(time.Minute)}, u1)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 3)
+
+	entries, err = db.GetAuditEntries(data.AuditFilter{Start: start.Add(time.Minute)}, u1)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 0)
+}
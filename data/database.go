@@ -1,6 +1,7 @@
 package data
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -21,12 +22,38 @@ func (nf *notFound) Error() string {
 	return fmt.Sprintf("%s not found", nf.thing)
 }
 
+// ErrNotScoped is returned when an assessment is requested by a user without an assessment scope
+var ErrNotScoped = errors.New("User is not restricted to an assessment")
+
+// ErrOrganisationExists is returned when creating an organisation which has already been created
+var ErrOrganisationExists = errors.New("Organisation already exists")
+
+// ErrShortCodeInUse is returned when a new short code clashes with an existing code
+var ErrShortCodeInUse = errors.New("Short code already in use")
+
+// ErrBeneficiaryExists is returned when renaming a beneficiary to an ID which is already in use
+var ErrBeneficiaryExists = errors.New("Beneficiary ID already in use")
+
+// IsNotFound returns true if the error was created by NewNotFoundError
+func IsNotFound(err error) bool {
+	_, ok := err.(*notFound)
+	return ok
+}
+
 type Base interface {
+	AuditLog
+
 	NewOutcomeSet(name, description string, u auth.User) (impact.OutcomeSet, error)
 	EditOutcomeSet(id, name, description string, u auth.User) (impact.OutcomeSet, error)
 	GetOutcomeSet(id string, u auth.User) (impact.OutcomeSet, error)
 	GetOutcomeSets(u auth.User) ([]impact.OutcomeSet, error)
 	DeleteOutcomeSet(id string, u auth.User) error
+	// PublishOutcomeSet stores an immutable copy of the outcome set's current questions and categories as its next version
+	PublishOutcomeSet(id string, u auth.User) (impact.OutcomeSet, error)
+	// GetOutcomeSetVersion returns a published copy of the outcome set
+	GetOutcomeSetVersion(id string, version int, u auth.User) (impact.OutcomeSet, error)
+	// GetOutcomeSetVersions returns the outcome set's published copies ordered by version
+	GetOutcomeSetVersions(id string, u auth.User) ([]impact.OutcomeSet, error)
 
 	GetQuestion(outcomeSetID string, questionID string, u auth.User) (impact.Question, error)
 	NewQuestion(outcomeSetID, question, description string, questionType impact.QuestionType, options map[string]interface{}, u auth.User) (impact.Question, error)
@@ -42,11 +69,50 @@ type Base interface {
 	RemoveCategory(outcomeSetID, questionID string, u auth.User) (impact.Question, error)
 
 	GetOrganisation(id string, u auth.User) (impact.Organisation, error)
+	// CreateOrganisation stores the organisation the user is acting within, using the user's organisation ID
+	CreateOrganisation(name string, u auth.User) (impact.Organisation, error)
+	EditOrganisation(id, name string, settings impact.OrganisationSettings, u auth.User) (impact.Organisation, error)
 
+	// GetMeeting and GetAssessmentMeeting report deleted meetings as not found
 	GetMeeting(id string, u auth.User) (impact.Meeting, error)
+	// GetAssessmentMeeting returns the meeting the user's assessment scope is restricted to, regardless of organisation.
+	// This allows beneficiary users, who do not belong to an organisation, to access their assessment.
+	GetAssessmentMeeting(u auth.User) (impact.Meeting, error)
 	GetMeetingsForBeneficiary(beneficiary string, u auth.User) ([]impact.Meeting, error)
 	GetOSMeetingsInTimeRange(start, end time.Time, outcomeSetID string, u auth.User) ([]impact.Meeting, error)
 	GetOSMeetingsForBeneficiary(beneficiary string, outcomeSetID string, u auth.User) ([]impact.Meeting, error)
+	// NewMeeting pins the meeting to the outcome set's latest published version, publishing the outcome set if it has never been published
 	NewMeeting(beneficiaryID, outcomeSetID string, conducted time.Time, u auth.User) (impact.Meeting, error)
+	EditMeeting(id, beneficiaryID string, conducted time.Time, u auth.User) (impact.Meeting, error)
+	DeleteMeeting(id string, u auth.User) error
+	SetMeetingStatus(id string, status impact.MeetingStatus, u auth.User) (impact.Meeting, error)
+
+	// NewMeeting and EditMeeting create an active record for the meeting's beneficiary if one does not exist
+	GetBeneficiary(id string, u auth.User) (impact.Beneficiary, error)
+	// GetBeneficiaries returns the organisation's beneficiaries ordered by ID
+	GetBeneficiaries(u auth.User) ([]impact.Beneficiary, error)
+	EditBeneficiary(id string, tags []string, status impact.BeneficiaryStatus, u auth.User) (impact.Beneficiary, error)
+	// RenameBeneficiary changes the beneficiary's ID, updating their meetings to reference the new ID
+	RenameBeneficiary(id, newID string, u auth.User) (impact.Beneficiary, error)
+	// GetAllMeetingsForBeneficiary includes deleted meetings, so everything held about the beneficiary can be exported
+	GetAllMeetingsForBeneficiary(beneficiary string, u auth.User) ([]impact.Meeting, error)
+	// EraseBeneficiary permanently removes the beneficiary's record and meetings, along with the meetings' short codes and revocations
+	EraseBeneficiary(id string, u auth.User) error
+
+	// RevokeAssessment prevents beneficiary JWTs scoped to the meeting from being used
+	RevokeAssessment(meetingID string, u auth.User) error
+	IsAssessmentRevoked(meetingID string) (bool, error)
+
+	NewShortCode(code, meetingID, beneficiaryID string, expiry time.Time, u auth.User) (impact.ShortCode, error)
+	// GetShortCode does not require a user, possession of the code grants access to the assessment
+	GetShortCode(code string) (impact.ShortCode, error)
+
+	NewAPIKey(name, hash string, scopes []string, u auth.User) (impact.APIKey, error)
+	GetAPIKeys(u auth.User) ([]impact.APIKey, error)
+	RevokeAPIKey(id string, u auth.User) error
+	// GetAPIKeyByHash does not require a user, as it is used to authenticate API key users
+	GetAPIKeyByHash(hash string) (impact.APIKey, error)
+
+	// NewAnswer stores the answer against the meeting, replacing any existing answer to the same question
 	NewAnswer(meetingID string, answer impact.Answer, u auth.User) (impact.Meeting, error)
 }
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"time"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	uuid "github.com/satori/go.uuid"
+)
+
+func (m *memory) NewAPIKey(name, hash string, scopes []string, u auth.User) (impact.APIKey, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.APIKey{}, err
+	}
+
+	key := impact.APIKey{
+		ID:             uuid.NewV4().String(),
+		OrganisationID: userOrg,
+		Name:           name,
+		Hash:           hash,
+		Scopes:         scopes,
+		Created:        time.Now(),
+		User:           u.UserID(),
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.apiKeys[key.ID] = key
+	return key, nil
+}
+
+func (m *memory) GetAPIKeys(u auth.User) ([]impact.APIKey, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	keys := []impact.APIKey{}
+	for _, key := range m.apiKeys {
+		if key.OrganisationID == userOrg {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (m *memory) RevokeAPIKey(id string, u auth.User) error {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	key, ok := m.apiKeys[id]
+	if !ok || key.OrganisationID != userOrg {
+		return data.NewNotFoundError("API key")
+	}
+	key.Revoked = true
+	m.apiKeys[id] = key
+	return nil
+}
+
+func (m *memory) GetAPIKeyByHash(hash string) (impact.APIKey, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for _, key := range m.apiKeys {
+		if key.Hash == hash {
+			return key, nil
+		}
+	}
+	return impact.APIKey{}, data.NewNotFoundError("API key")
+}
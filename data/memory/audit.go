@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"time"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	uuid "github.com/satori/go.uuid"
+)
+
+func (m *memory) NewAuditEntry(e impact.AuditEntry) (impact.AuditEntry, error) {
+	e.ID = uuid.NewV4().String()
+	e.Timestamp = time.Now()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.auditLog = append(m.auditLog, e)
+	return e, nil
+}
+
+func (m *memory) GetAuditEntries(filter data.AuditFilter, u auth.User) ([]impact.AuditEntry, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	entries := []impact.AuditEntry{}
+	// entries are appended in time order, so walking backwards returns the newest first
+	for i := len(m.auditLog) - 1; i >= 0; i-- {
+		e := m.auditLog[i]
+		if e.OrganisationID != userOrg || !filter.Matches(e) {
+			continue
+		}
+		entries = append(entries, e)
+		if filter.Limit > 0 && len(entries) == filter.Limit {
+			break
+		}
+	}
+	return entries, nil
+}
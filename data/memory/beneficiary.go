@@ -0,0 +1,197 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+)
+
+func copyBeneficiary(b impact.Beneficiary) impact.Beneficiary {
+	b.Tags = append([]string{}, b.Tags...)
+	return b
+}
+
+// findBeneficiary returns the stored beneficiary, the caller must hold the mutex
+func (m *memory) findBeneficiary(id, userOrg string) (*impact.Beneficiary, error) {
+	for _, b := range m.beneficiaries {
+		if b.ID == id && b.OrganisationID == userOrg {
+			return b, nil
+		}
+	}
+	return nil, data.NewNotFoundError("Beneficiary")
+}
+
+// ensureBeneficiary creates the beneficiary's record if it does not exist, the caller must hold the write lock
+func (m *memory) ensureBeneficiary(id, userOrg string) {
+	if _, err := m.findBeneficiary(id, userOrg); err == nil {
+		return
+	}
+	m.beneficiaries = append(m.beneficiaries, &impact.Beneficiary{
+		ID:             id,
+		OrganisationID: userOrg,
+		Created:        time.Now(),
+		Tags:           []string{},
+		Status:         impact.ACTIVE,
+	})
+}
+
+func (m *memory) GetBeneficiary(id string, u auth.User) (impact.Beneficiary, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Beneficiary{}, err
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	b, err := m.findBeneficiary(id, userOrg)
+	if err != nil {
+		return impact.Beneficiary{}, err
+	}
+	return copyBeneficiary(*b), nil
+}
+
+func (m *memory) GetBeneficiaries(u auth.User) ([]impact.Beneficiary, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	results := []impact.Beneficiary{}
+	for _, b := range m.beneficiaries {
+		if b.OrganisationID == userOrg {
+			results = append(results, copyBeneficiary(*b))
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].ID < results[j].ID
+	})
+	return results, nil
+}
+
+func (m *memory) EditBeneficiary(id string, tags []string, status impact.BeneficiaryStatus, u auth.User) (impact.Beneficiary, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Beneficiary{}, err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	b, err := m.findBeneficiary(id, userOrg)
+	if err != nil {
+		return impact.Beneficiary{}, err
+	}
+	b.Tags = append([]string{}, tags...)
+	b.Status = status
+	return copyBeneficiary(*b), nil
+}
+
+func (m *memory) RenameBeneficiary(id, newID string, u auth.User) (impact.Beneficiary, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Beneficiary{}, err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	b, err := m.findBeneficiary(id, userOrg)
+	if err != nil {
+		return impact.Beneficiary{}, err
+	}
+	if _, err := m.findBeneficiary(newID, userOrg); err == nil {
+		return impact.Beneficiary{}, data.ErrBeneficiaryExists
+	}
+	b.ID = newID
+	for _, meeting := range m.meetings {
+		if meeting.OrganisationID == userOrg && meeting.Beneficiary == id {
+			meeting.Beneficiary = newID
+			meeting.Modified = time.Now()
+		}
+	}
+	return copyBeneficiary(*b), nil
+}
+
+func (m *memory) GetAllMeetingsForBeneficiary(beneficiary string, u auth.User) ([]impact.Meeting, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	results := []impact.Meeting{}
+	for _, meeting := range m.meetings {
+		if meeting.OrganisationID == userOrg && meeting.Beneficiary == beneficiary {
+			results = append(results, copyMeeting(*meeting))
+		}
+	}
+	return results, nil
+}
+
+func (m *memory) EraseBeneficiary(id string, u auth.User) error {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	found := false
+	beneficiaries := []*impact.Beneficiary{}
+	for _, b := range m.beneficiaries {
+		if b.OrganisationID == userOrg && b.ID == id {
+			found = true
+			continue
+		}
+		beneficiaries = append(beneficiaries, b)
+	}
+	m.beneficiaries = beneficiaries
+
+	erased := map[string]bool{}
+	meetings := []*impact.Meeting{}
+	for _, meeting := range m.meetings {
+		if meeting.OrganisationID == userOrg && meeting.Beneficiary == id {
+			erased[meeting.ID] = true
+			continue
+		}
+		meetings = append(meetings, meeting)
+	}
+	m.meetings = meetings
+
+	for code, sc := range m.shortCodes {
+		if erased[sc.MeetingID] {
+			delete(m.shortCodes, code)
+		}
+	}
+	for meetingID := range erased {
+		delete(m.revokedAssessments, meetingID)
+	}
+
+	if !found && len(erased) == 0 {
+		return data.NewNotFoundError("Beneficiary")
+	}
+
+	references := []string{id}
+	for meetingID := range erased {
+		references = append(references, meetingID)
+	}
+	auditLog := []impact.AuditEntry{}
+	for _, e := range m.auditLog {
+		if e.OrganisationID == userOrg && data.ReferencesAny(e, references) {
+			continue
+		}
+		auditLog = append(auditLog, e)
+	}
+	m.auditLog = auditLog
+	return nil
+}
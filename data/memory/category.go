@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"errors"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	uuid "github.com/satori/go.uuid"
+)
+
+func (m *memory) GetCategory(outcomeSetID, categoryID string, u auth.User) (impact.Category, error) {
+	os, err := m.GetOutcomeSet(outcomeSetID, u)
+	if err != nil {
+		return impact.Category{}, err
+	}
+
+	for _, c := range os.Categories {
+		if c.ID == categoryID {
+			return c, nil
+		}
+	}
+	return impact.Category{}, data.NewNotFoundError("Category")
+}
+
+func (m *memory) NewCategory(outcomeSetID, name, description string, aggregation impact.Aggregation, u auth.User) (impact.Category, error) {
+	newCategory := impact.Category{
+		ID:          uuid.NewV4().String(),
+		Name:        name,
+		Description: description,
+		Aggregation: aggregation,
+	}
+
+	if err := m.mutateOutcomeSet(outcomeSetID, u, func(os *impact.OutcomeSet) error {
+		os.Categories = append(os.Categories, newCategory)
+		return nil
+	}); err != nil {
+		return impact.Category{}, err
+	}
+
+	return m.GetCategory(outcomeSetID, newCategory.ID, u)
+}
+
+func (m *memory) DeleteCategory(outcomeSetID, categoryID string, u auth.User) error {
+	return m.mutateOutcomeSet(outcomeSetID, u, func(os *impact.OutcomeSet) error {
+		if len(os.GetCategoryQuestions(categoryID)) > 0 {
+			return errors.New("Cannot delete a category which is being used")
+		}
+
+		for i := range os.Questions {
+			if os.Questions[i].CategoryID == categoryID {
+				os.Questions[i].CategoryID = ""
+			}
+		}
+
+		remaining := make([]impact.Category, 0, len(os.Categories))
+		for _, c := range os.Categories {
+			if c.ID != categoryID {
+				remaining = append(remaining, c)
+			}
+		}
+		os.Categories = remaining
+		return nil
+	})
+}
+
+func (m *memory) EditCategory(outcomeSetID, categoryID string, name, description string, aggregation impact.Aggregation, u auth.User) (impact.Category, error) {
+	if err := m.mutateOutcomeSet(outcomeSetID, u, func(os *impact.OutcomeSet) error {
+		for i := range os.Categories {
+			if os.Categories[i].ID == categoryID {
+				os.Categories[i].Name = name
+				os.Categories[i].Description = description
+				os.Categories[i].Aggregation = aggregation
+				return nil
+			}
+		}
+		return data.NewNotFoundError("Category")
+	}); err != nil {
+		return impact.Category{}, err
+	}
+	return m.GetCategory(outcomeSetID, categoryID, u)
+}
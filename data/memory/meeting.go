@@ -0,0 +1,176 @@
+package memory
+
+import (
+	"time"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	uuid "github.com/satori/go.uuid"
+)
+
+// findMeeting returns the stored meeting, the caller must hold the mutex
+func (m *memory) findMeeting(id, userOrg string) (*impact.Meeting, error) {
+	for _, meeting := range m.meetings {
+		if meeting.ID == id && meeting.OrganisationID == userOrg {
+			return meeting, nil
+		}
+	}
+	return nil, data.NewNotFoundError("Meeting")
+}
+
+func (m *memory) GetMeeting(id string, u auth.User) (impact.Meeting, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	meeting, err := m.findMeeting(id, userOrg)
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+	if meeting.Deleted {
+		return impact.Meeting{}, data.NewNotFoundError("Meeting")
+	}
+	return copyMeeting(*meeting), nil
+}
+
+func (m *memory) GetAssessmentMeeting(u auth.User) (impact.Meeting, error) {
+	id, ok := u.GetAssessmentScope()
+	if !ok {
+		return impact.Meeting{}, data.ErrNotScoped
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for _, meeting := range m.meetings {
+		if meeting.ID == id && !meeting.Deleted {
+			return copyMeeting(*meeting), nil
+		}
+	}
+	return impact.Meeting{}, data.NewNotFoundError("Meeting")
+}
+
+type meetingFilter func(meeting *impact.Meeting) bool
+
+func (m *memory) getMeetings(include meetingFilter, u auth.User) ([]impact.Meeting, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	results := []impact.Meeting{}
+	for _, meeting := range m.meetings {
+		if meeting.OrganisationID == userOrg && !meeting.Deleted && include(meeting) {
+			results = append(results, copyMeeting(*meeting))
+		}
+	}
+	return results, nil
+}
+
+func (m *memory) GetMeetingsForBeneficiary(beneficiary string, u auth.User) ([]impact.Meeting, error) {
+	return m.getMeetings(func(meeting *impact.Meeting) bool {
+		return meeting.Beneficiary == beneficiary
+	}, u)
+}
+
+func (m *memory) GetOSMeetingsForBeneficiary(beneficiary string, outcomeSetID string, u auth.User) ([]impact.Meeting, error) {
+	return m.getMeetings(func(meeting *impact.Meeting) bool {
+		return meeting.Beneficiary == beneficiary && meeting.OutcomeSetID == outcomeSetID
+	}, u)
+}
+
+func (m *memory) GetOSMeetingsInTimeRange(start, end time.Time, outcomeSetID string, u auth.User) ([]impact.Meeting, error) {
+	return m.getMeetings(func(meeting *impact.Meeting) bool {
+		return meeting.OutcomeSetID == outcomeSetID &&
+			!meeting.Conducted.Before(start) &&
+			!meeting.Conducted.After(end)
+	}, u)
+}
+
+func (m *memory) NewMeeting(beneficiaryID, outcomeSetID string, conducted time.Time, u auth.User) (impact.Meeting, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+
+	meeting := &impact.Meeting{
+		ID:             uuid.NewV4().String(),
+		OrganisationID: userOrg,
+		OutcomeSetID:   outcomeSetID,
+		Beneficiary:    beneficiaryID,
+		Answers:        []impact.Answer{},
+		Conducted:      conducted,
+		Created:        time.Now(),
+		Modified:       time.Now(),
+		User:           u.UserID(),
+		Status:         impact.IN_PROGRESS,
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if os, err := m.findOutcomeSet(outcomeSetID, userOrg); err == nil {
+		// outcome sets are published by their first meeting, so every meeting is pinned to a version
+		if os.Version == 0 {
+			m.publishOutcomeSet(os)
+		}
+		meeting.OutcomeSetVersion = os.Version
+	}
+	m.meetings = append(m.meetings, meeting)
+	m.ensureBeneficiary(beneficiaryID, userOrg)
+	return copyMeeting(*meeting), nil
+}
+
+// mutateMeeting applies fn to the stored meeting while holding the write lock
+func (m *memory) mutateMeeting(id string, u auth.User, fn func(meeting *impact.Meeting)) (impact.Meeting, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	meeting, err := m.findMeeting(id, userOrg)
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+	fn(meeting)
+	meeting.Modified = time.Now()
+	return copyMeeting(*meeting), nil
+}
+
+func (m *memory) NewAnswer(meetingID string, answer impact.Answer, u auth.User) (impact.Meeting, error) {
+	return m.mutateMeeting(meetingID, u, func(meeting *impact.Meeting) {
+		meeting.SetAnswer(answer)
+	})
+}
+
+func (m *memory) EditMeeting(id, beneficiaryID string, conducted time.Time, u auth.User) (impact.Meeting, error) {
+	return m.mutateMeeting(id, u, func(meeting *impact.Meeting) {
+		meeting.Beneficiary = beneficiaryID
+		meeting.Conducted = conducted
+		m.ensureBeneficiary(beneficiaryID, meeting.OrganisationID)
+	})
+}
+
+func (m *memory) DeleteMeeting(id string, u auth.User) error {
+	_, err := m.mutateMeeting(id, u, func(meeting *impact.Meeting) {
+		meeting.Deleted = true
+	})
+	return err
+}
+
+func (m *memory) SetMeetingStatus(id string, status impact.MeetingStatus, u auth.User) (impact.Meeting, error) {
+	return m.mutateMeeting(id, u, func(meeting *impact.Meeting) {
+		meeting.Status = status
+	})
+}
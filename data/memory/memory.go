@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"sync"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/data"
+)
+
+type memory struct {
+	mutex       sync.RWMutex
+	outcomeSets []*impact.OutcomeSet
+	// outcomeSetVersions holds the published copies of outcome sets, in the order they were published
+	outcomeSetVersions []impact.OutcomeSet
+	meetings           []*impact.Meeting
+	beneficiaries      []*impact.Beneficiary
+	organisations      []impact.Organisation
+	// revokedAssessments is keyed by meeting ID
+	revokedAssessments map[string]bool
+	shortCodes         map[string]impact.ShortCode
+	apiKeys            map[string]impact.APIKey
+	auditLog           []impact.AuditEntry
+}
+
+// New returns a data.Base which holds all data in memory. Nothing is persisted, so it is only suitable for local
+// development and tests. The provided organisations are made available to GetOrganisation.
+func New(orgs ...impact.Organisation) data.Base {
+	return &memory{
+		outcomeSets:        []*impact.OutcomeSet{},
+		meetings:           []*impact.Meeting{},
+		beneficiaries:      []*impact.Beneficiary{},
+		organisations:      orgs,
+		revokedAssessments: map[string]bool{},
+		shortCodes:         map[string]impact.ShortCode{},
+		apiKeys:            map[string]impact.APIKey{},
+	}
+}
+
+// the copy functions ensure callers can never mutate the stored data through returned slices or maps
+
+func copyOptions(in map[string]interface{}) map[string]interface{} {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
+
+func copyQuestion(q impact.Question) impact.Question {
+	q.Options = copyOptions(q.Options)
+	return q
+}
+
+func copyOutcomeSet(os impact.OutcomeSet) impact.OutcomeSet {
+	qs := make([]impact.Question, 0, len(os.Questions))
+	for _, q := range os.Questions {
+		qs = append(qs, copyQuestion(q))
+	}
+	os.Questions = qs
+	os.Categories = append([]impact.Category{}, os.Categories...)
+	return os
+}
+
+func copyMeeting(m impact.Meeting) impact.Meeting {
+	m.Answers = append([]impact.Answer{}, m.Answers...)
+	return m
+}
@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/impactasaurus/server/data"
+	"github.com/impactasaurus/server/data/conformance"
+	"github.com/impactasaurus/server/data/memory"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) data.Base {
+		return memory.New()
+	})
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"errors"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+)
+
+func (m *memory) GetOrganisation(id string, u auth.User) (impact.Organisation, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Organisation{}, err
+	}
+
+	if id != userOrg {
+		return impact.Organisation{}, errors.New("User does not have permission to view this organisation")
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for _, org := range m.organisations {
+		if org.ID == id {
+			return org, nil
+		}
+	}
+	return impact.Organisation{}, data.NewNotFoundError("Organisation")
+}
+
+func (m *memory) CreateOrganisation(name string, u auth.User) (impact.Organisation, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Organisation{}, err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, org := range m.organisations {
+		if org.ID == userOrg {
+			return impact.Organisation{}, data.ErrOrganisationExists
+		}
+	}
+	org := impact.Organisation{
+		ID:   userOrg,
+		Name: name,
+	}
+	m.organisations = append(m.organisations, org)
+	return org, nil
+}
+
+func (m *memory) EditOrganisation(id, name string, settings impact.OrganisationSettings, u auth.User) (impact.Organisation, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Organisation{}, err
+	}
+
+	if id != userOrg {
+		return impact.Organisation{}, errors.New("User does not have permission to edit this organisation")
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for i, org := range m.organisations {
+		if org.ID == id {
+			m.organisations[i].Name = name
+			m.organisations[i].Settings = settings
+			return m.organisations[i], nil
+		}
+	}
+	return impact.Organisation{}, data.NewNotFoundError("Organisation")
+}
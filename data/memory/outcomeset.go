@@ -0,0 +1,166 @@
+package memory
+
+import (
+	"errors"
+	"time"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	uuid "github.com/satori/go.uuid"
+)
+
+// findOutcomeSet returns the stored outcome set, the caller must hold the mutex
+func (m *memory) findOutcomeSet(id, userOrg string) (*impact.OutcomeSet, error) {
+	for _, os := range m.outcomeSets {
+		if os.ID == id && os.OrganisationID == userOrg {
+			return os, nil
+		}
+	}
+	return nil, data.NewNotFoundError("Outcome Set")
+}
+
+// mutateOutcomeSet finds the user's outcome set and applies fn to it whilst holding the write lock
+func (m *memory) mutateOutcomeSet(id string, u auth.User, fn func(os *impact.OutcomeSet) error) error {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	os, err := m.findOutcomeSet(id, userOrg)
+	if err != nil {
+		return err
+	}
+	return fn(os)
+}
+
+func (m *memory) GetOutcomeSet(id string, u auth.User) (impact.OutcomeSet, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.OutcomeSet{}, err
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	os, err := m.findOutcomeSet(id, userOrg)
+	if err != nil {
+		return impact.OutcomeSet{}, err
+	}
+	return copyOutcomeSet(*os), nil
+}
+
+func (m *memory) GetOutcomeSets(u auth.User) ([]impact.OutcomeSet, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	results := []impact.OutcomeSet{}
+	for _, os := range m.outcomeSets {
+		if os.OrganisationID == userOrg && !os.Deleted {
+			results = append(results, copyOutcomeSet(*os))
+		}
+	}
+	return results, nil
+}
+
+func (m *memory) NewOutcomeSet(name, description string, u auth.User) (impact.OutcomeSet, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.OutcomeSet{}, err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, os := range m.outcomeSets {
+		if os.Name == name && os.OrganisationID == userOrg && !os.Deleted {
+			return impact.OutcomeSet{}, errors.New("Name already in use")
+		}
+	}
+
+	newOS := &impact.OutcomeSet{
+		ID:             uuid.NewV4().String(),
+		Deleted:        false,
+		Description:    description,
+		Name:           name,
+		OrganisationID: userOrg,
+		Questions:      []impact.Question{},
+		Categories:     []impact.Category{},
+	}
+	m.outcomeSets = append(m.outcomeSets, newOS)
+	return copyOutcomeSet(*newOS), nil
+}
+
+func (m *memory) EditOutcomeSet(id, name, description string, u auth.User) (impact.OutcomeSet, error) {
+	if err := m.mutateOutcomeSet(id, u, func(os *impact.OutcomeSet) error {
+		os.Name = name
+		os.Description = description
+		return nil
+	}); err != nil {
+		return impact.OutcomeSet{}, err
+	}
+	return m.GetOutcomeSet(id, u)
+}
+
+func (m *memory) DeleteOutcomeSet(id string, u auth.User) error {
+	return m.mutateOutcomeSet(id, u, func(os *impact.OutcomeSet) error {
+		os.Deleted = true
+		return nil
+	})
+}
+
+func (m *memory) PublishOutcomeSet(id string, u auth.User) (impact.OutcomeSet, error) {
+	if err := m.mutateOutcomeSet(id, u, func(os *impact.OutcomeSet) error {
+		m.publishOutcomeSet(os)
+		return nil
+	}); err != nil {
+		return impact.OutcomeSet{}, err
+	}
+	return m.GetOutcomeSet(id, u)
+}
+
+// publishOutcomeSet stores a copy of the outcome set as its next version, the write lock must be held
+func (m *memory) publishOutcomeSet(os *impact.OutcomeSet) {
+	os.Version++
+	os.Published = time.Now()
+	m.outcomeSetVersions = append(m.outcomeSetVersions, copyOutcomeSet(*os))
+}
+
+func (m *memory) GetOutcomeSetVersion(id string, version int, u auth.User) (impact.OutcomeSet, error) {
+	versions, err := m.GetOutcomeSetVersions(id, u)
+	if err != nil {
+		return impact.OutcomeSet{}, err
+	}
+	for _, os := range versions {
+		if os.Version == version {
+			return os, nil
+		}
+	}
+	return impact.OutcomeSet{}, data.NewNotFoundError("Outcome Set Version")
+}
+
+func (m *memory) GetOutcomeSetVersions(id string, u auth.User) ([]impact.OutcomeSet, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	results := []impact.OutcomeSet{}
+	for _, os := range m.outcomeSetVersions {
+		if os.ID == id && os.OrganisationID == userOrg {
+			results = append(results, copyOutcomeSet(os))
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,28 @@
+package memory
+
+import (
+	"github.com/impactasaurus/server/auth"
+)
+
+func (m *memory) RevokeAssessment(meetingID string, u auth.User) error {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, err := m.findMeeting(meetingID, userOrg); err != nil {
+		return err
+	}
+	m.revokedAssessments[meetingID] = true
+	return nil
+}
+
+func (m *memory) IsAssessmentRevoked(meetingID string) (bool, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.revokedAssessments[meetingID], nil
+}
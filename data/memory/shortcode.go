@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"time"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+)
+
+func (m *memory) NewShortCode(code, meetingID, beneficiaryID string, expiry time.Time, u auth.User) (impact.ShortCode, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.ShortCode{}, err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, exists := m.shortCodes[code]; exists {
+		return impact.ShortCode{}, data.ErrShortCodeInUse
+	}
+	sc := impact.ShortCode{
+		Code:           code,
+		MeetingID:      meetingID,
+		Beneficiary:    beneficiaryID,
+		OrganisationID: userOrg,
+		Expiry:         expiry,
+	}
+	m.shortCodes[code] = sc
+	return sc, nil
+}
+
+func (m *memory) GetShortCode(code string) (impact.ShortCode, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	sc, ok := m.shortCodes[code]
+	if !ok {
+		return impact.ShortCode{}, data.NewNotFoundError("Short code")
+	}
+	return sc, nil
+}
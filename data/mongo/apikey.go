@@ -0,0 +1,94 @@
+package mongo
+
+import (
+	"time"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	uuid "github.com/satori/go.uuid"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func (m *mongo) NewAPIKey(name, hash string, scopes []string, u auth.User) (impact.APIKey, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.APIKey{}, err
+	}
+
+	col, closer := m.getAPIKeyCollection()
+	defer closer()
+
+	key := impact.APIKey{
+		ID:             uuid.NewV4().String(),
+		OrganisationID: userOrg,
+		Name:           name,
+		Hash:           hash,
+		Scopes:         scopes,
+		Created:        time.Now(),
+		User:           u.UserID(),
+	}
+	if err := col.Insert(key); err != nil {
+		return impact.APIKey{}, err
+	}
+	return key, nil
+}
+
+func (m *mongo) GetAPIKeys(u auth.User) ([]impact.APIKey, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	col, closer := m.getAPIKeyCollection()
+	defer closer()
+
+	keys := []impact.APIKey{}
+	if err := col.Find(bson.M{
+		"organisationID": userOrg,
+	}).All(&keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (m *mongo) RevokeAPIKey(id string, u auth.User) error {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return err
+	}
+
+	col, closer := m.getAPIKeyCollection()
+	defer closer()
+
+	err = col.Update(bson.M{
+		"_id":            id,
+		"organisationID": userOrg,
+	}, bson.M{
+		"$set": bson.M{
+			"revoked": true,
+		},
+	})
+	if mgo.ErrNotFound == err {
+		return data.NewNotFoundError("API key")
+	}
+	return err
+}
+
+func (m *mongo) GetAPIKeyByHash(hash string) (impact.APIKey, error) {
+	key := impact.APIKey{}
+
+	col, closer := m.getAPIKeyCollection()
+	defer closer()
+
+	if err := col.Find(bson.M{
+		"hash": hash,
+	}).One(&key); err != nil {
+		if mgo.ErrNotFound == err {
+			return key, data.NewNotFoundError("API key")
+		}
+		return key, err
+	}
+	return key, nil
+}
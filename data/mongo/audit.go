@@ -0,0 +1,88 @@
+package mongo
+
+import (
+	"time"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	uuid "github.com/satori/go.uuid"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func (m *mongo) NewAuditEntry(e impact.AuditEntry) (impact.AuditEntry, error) {
+	e.ID = uuid.NewV4().String()
+	e.Timestamp = time.Now()
+	e.Arguments = m.pseudonymiseArguments(e.OrganisationID, e.Arguments)
+
+	col, closer := m.getAuditCollection()
+	defer closer()
+
+	if err := col.Insert(e); err != nil {
+		return impact.AuditEntry{}, err
+	}
+	return e, nil
+}
+
+func (m *mongo) GetAuditEntries(filter data.AuditFilter, u auth.User) ([]impact.AuditEntry, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	col, closer := m.getAuditCollection()
+	defer closer()
+
+	find := bson.M{
+		"organisationID": userOrg,
+	}
+	if filter.Mutation != "" {
+		find["mutation"] = filter.Mutation
+	}
+	if filter.UserID != "" {
+		find["user"] = filter.UserID
+	}
+	timestamp := bson.M{}
+	if !filter.Start.IsZero() {
+		timestamp["$gte"] = filter.Start
+	}
+	if !filter.End.IsZero() {
+		timestamp["$lte"] = filter.End
+	}
+	if len(timestamp) > 0 {
+		find["timestamp"] = timestamp
+	}
+
+	entries := []impact.AuditEntry{}
+	if err := col.Find(find).Sort("-timestamp").Limit(filter.Limit).All(&entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// eraseAuditEntries removes the organisation's entries which reference any of the IDs
+func (m *mongo) eraseAuditEntries(userOrg string, references []string) error {
+	col, closer := m.getAuditCollection()
+	defer closer()
+
+	erased := []string{}
+	iter := col.Find(bson.M{
+		"organisationID": userOrg,
+	}).Iter()
+	e := impact.AuditEntry{}
+	for iter.Next(&e) {
+		if data.ReferencesAny(e, references) {
+			erased = append(erased, e.ID)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+	if len(erased) == 0 {
+		return nil
+	}
+	_, err := col.RemoveAll(bson.M{
+		"_id": bson.M{"$in": erased},
+	})
+	return err
+}
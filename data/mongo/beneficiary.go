@@ -0,0 +1,246 @@
+package mongo
+
+import (
+	"time"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ensureBeneficiary creates the beneficiary's record if it does not exist
+func ensureBeneficiary(col *mgo.Collection, id, userOrg string, created time.Time) error {
+	_, err := col.Upsert(bson.M{
+		"id":             id,
+		"organisationID": userOrg,
+	}, bson.M{
+		"$setOnInsert": bson.M{
+			"created": created,
+			"tags":    []string{},
+			"status":  impact.ACTIVE,
+		},
+	})
+	// a concurrent upsert of the same beneficiary violates the unique index, the record exists either way
+	if mgo.IsDup(err) {
+		return nil
+	}
+	return err
+}
+
+func (m *mongo) ensureBeneficiary(id, userOrg string, created time.Time) error {
+	col, closer := m.getBeneficiaryCollection()
+	defer closer()
+
+	return ensureBeneficiary(col, id, userOrg, created)
+}
+
+func (m *mongo) GetBeneficiary(id string, u auth.User) (impact.Beneficiary, error) {
+	ben := impact.Beneficiary{}
+
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return ben, err
+	}
+
+	col, closer := m.getBeneficiaryCollection()
+	defer closer()
+
+	if err := col.Find(bson.M{
+		"id":             m.beneficiaryKey(userOrg, id),
+		"organisationID": userOrg,
+	}).One(&ben); err != nil {
+		if mgo.ErrNotFound == err {
+			return ben, data.NewNotFoundError("Beneficiary")
+		}
+		return ben, err
+	}
+	return ben, nil
+}
+
+func (m *mongo) GetBeneficiaries(u auth.User) ([]impact.Beneficiary, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	col, closer := m.getBeneficiaryCollection()
+	defer closer()
+
+	results := []impact.Beneficiary{}
+	if err := col.Find(bson.M{
+		"organisationID": userOrg,
+	}).Sort("id").All(&results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (m *mongo) EditBeneficiary(id string, tags []string, status impact.BeneficiaryStatus, u auth.User) (impact.Beneficiary, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Beneficiary{}, err
+	}
+
+	col, closer := m.getBeneficiaryCollection()
+	defer closer()
+
+	if err := col.Update(bson.M{
+		"id":             m.beneficiaryKey(userOrg, id),
+		"organisationID": userOrg,
+	}, bson.M{
+		"$set": bson.M{
+			"tags":   tags,
+			"status": status,
+		},
+	}); err != nil {
+		if mgo.ErrNotFound == err {
+			return impact.Beneficiary{}, data.NewNotFoundError("Beneficiary")
+		}
+		return impact.Beneficiary{}, err
+	}
+	return m.GetBeneficiary(id, u)
+}
+
+func (m *mongo) RenameBeneficiary(id, newID string, u auth.User) (impact.Beneficiary, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Beneficiary{}, err
+	}
+
+	key := m.beneficiaryKey(userOrg, id)
+	newKey := m.beneficiaryKey(userOrg, newID)
+	if key == newKey {
+		if _, err := m.GetBeneficiary(id, u); err != nil {
+			return impact.Beneficiary{}, err
+		}
+		return impact.Beneficiary{}, data.ErrBeneficiaryExists
+	}
+
+	col, closer := m.getBeneficiaryCollection()
+	defer closer()
+
+	// the unique index prevents the beneficiary taking an ID which is already in use
+	if err := col.Update(bson.M{
+		"id":             key,
+		"organisationID": userOrg,
+	}, bson.M{
+		"$set": bson.M{
+			"id": newKey,
+		},
+	}); err != nil {
+		if mgo.ErrNotFound == err {
+			return impact.Beneficiary{}, data.NewNotFoundError("Beneficiary")
+		}
+		if mgo.IsDup(err) {
+			return impact.Beneficiary{}, data.ErrBeneficiaryExists
+		}
+		return impact.Beneficiary{}, err
+	}
+	if _, err := m.storeBeneficiaryKey(userOrg, newID); err != nil {
+		return impact.Beneficiary{}, err
+	}
+
+	meetingCol, meetingCloser := m.getMeetingCollection()
+	defer meetingCloser()
+
+	if _, err := meetingCol.UpdateAll(bson.M{
+		"beneficiary":    key,
+		"organisationID": userOrg,
+	}, bson.M{
+		"$set": bson.M{
+			"beneficiary": newKey,
+			"modified":    time.Now(),
+		},
+	}); err != nil {
+		return impact.Beneficiary{}, err
+	}
+	if err := m.forgetBeneficiaryKey(key); err != nil {
+		return impact.Beneficiary{}, err
+	}
+	return m.GetBeneficiary(newKey, u)
+}
+
+func (m *mongo) GetAllMeetingsForBeneficiary(beneficiary string, u auth.User) ([]impact.Meeting, error) {
+	return m.getMeetings(func(col *mgo.Collection, userOrg string) ([]impact.Meeting, error) {
+		results := []impact.Meeting{}
+		err := col.Find(bson.M{
+			"beneficiary":    m.beneficiaryKey(userOrg, beneficiary),
+			"organisationID": userOrg,
+		}).All(&results)
+		return results, err
+	}, u)
+}
+
+func (m *mongo) EraseBeneficiary(id string, u auth.User) error {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return err
+	}
+
+	key := m.beneficiaryKey(userOrg, id)
+
+	meetingCol, meetingCloser := m.getMeetingCollection()
+	defer meetingCloser()
+
+	meetings := []struct {
+		ID string `bson:"_id"`
+	}{}
+	if err := meetingCol.Find(bson.M{
+		"beneficiary":    key,
+		"organisationID": userOrg,
+	}).Select(bson.M{"_id": 1}).All(&meetings); err != nil {
+		return err
+	}
+	meetingIDs := make([]string, 0, len(meetings))
+	for _, meeting := range meetings {
+		meetingIDs = append(meetingIDs, meeting.ID)
+	}
+
+	benCol, benCloser := m.getBeneficiaryCollection()
+	defer benCloser()
+
+	err = benCol.Remove(bson.M{
+		"id":             key,
+		"organisationID": userOrg,
+	})
+	if err == mgo.ErrNotFound && len(meetingIDs) == 0 {
+		return data.NewNotFoundError("Beneficiary")
+	}
+	if err != nil && err != mgo.ErrNotFound {
+		return err
+	}
+	// entries made before pseudonyms were enabled hold the original ID
+	if err := m.eraseAuditEntries(userOrg, append([]string{id, key}, meetingIDs...)); err != nil {
+		return err
+	}
+	if len(meetingIDs) == 0 {
+		return m.forgetBeneficiaryKey(key)
+	}
+
+	// the meetings are removed last, so a failed erasure can be retried
+	scCol, scCloser := m.getShortCodeCollection()
+	defer scCloser()
+	if _, err := scCol.RemoveAll(bson.M{
+		"meetingID": bson.M{"$in": meetingIDs},
+	}); err != nil {
+		return err
+	}
+
+	revokedCol, revokedCloser := m.getRevokedAssessmentCollection()
+	defer revokedCloser()
+	if _, err := revokedCol.RemoveAll(bson.M{
+		"_id": bson.M{"$in": meetingIDs},
+	}); err != nil {
+		return err
+	}
+
+	if _, err := meetingCol.RemoveAll(bson.M{
+		"_id":            bson.M{"$in": meetingIDs},
+		"organisationID": userOrg,
+	}); err != nil {
+		return err
+	}
+	return m.forgetBeneficiaryKey(key)
+}
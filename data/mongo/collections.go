@@ -9,12 +9,42 @@ func (m *mongo) getOutcomeCollection() (*mgo.Collection, sessionEnder) {
 	return session.DB("").C("outcomesets"), session.Close
 }
 
+func (m *mongo) getOutcomeSetVersionCollection() (*mgo.Collection, sessionEnder) {
+	session := m.baseSession.Copy()
+	return session.DB("").C("outcomesetversions"), session.Close
+}
+
 func (m *mongo) getMeetingCollection() (*mgo.Collection, sessionEnder) {
 	session := m.baseSession.Copy()
 	return session.DB("").C("meetings"), session.Close
 }
 
+func (m *mongo) getBeneficiaryCollection() (*mgo.Collection, sessionEnder) {
+	session := m.baseSession.Copy()
+	return session.DB("").C("beneficiaries"), session.Close
+}
+
 func (m *mongo) getOrganisationCollection() (*mgo.Collection, sessionEnder) {
 	session := m.baseSession.Copy()
 	return session.DB("").C("organisations"), session.Close
 }
+
+func (m *mongo) getRevokedAssessmentCollection() (*mgo.Collection, sessionEnder) {
+	session := m.baseSession.Copy()
+	return session.DB("").C("revokedassessments"), session.Close
+}
+
+func (m *mongo) getShortCodeCollection() (*mgo.Collection, sessionEnder) {
+	session := m.baseSession.Copy()
+	return session.DB("").C("shortcodes"), session.Close
+}
+
+func (m *mongo) getAPIKeyCollection() (*mgo.Collection, sessionEnder) {
+	session := m.baseSession.Copy()
+	return session.DB("").C("apikeys"), session.Close
+}
+
+func (m *mongo) getAuditCollection() (*mgo.Collection, sessionEnder) {
+	session := m.baseSession.Copy()
+	return session.DB("").C("auditlog"), session.Close
+}
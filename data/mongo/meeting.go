@@ -10,6 +10,17 @@ import (
 	"time"
 )
 
+// legacyMeetings fills in the status of meetings stored before the completion lifecycle was introduced, which were
+// all reported on, so they are not excluded from reports until migration 3 has been applied
+func legacyMeetings(meetings []impact.Meeting) []impact.Meeting {
+	for i := range meetings {
+		if meetings[i].Status == "" {
+			meetings[i].Status = impact.COMPLETED
+		}
+	}
+	return meetings
+}
+
 func (m *mongo) GetMeeting(id string, u auth.User) (impact.Meeting, error) {
 	meeting := impact.Meeting{}
 
@@ -24,6 +35,7 @@ func (m *mongo) GetMeeting(id string, u auth.User) (impact.Meeting, error) {
 	err = col.Find(bson.M{
 		"_id":            id,
 		"organisationID": userOrg,
+		"deleted":        bson.M{"$ne": true},
 	}).One(&meeting)
 	if err != nil {
 		if mgo.ErrNotFound == err {
@@ -31,7 +43,30 @@ func (m *mongo) GetMeeting(id string, u auth.User) (impact.Meeting, error) {
 		}
 		return meeting, err
 	}
-	return meeting, nil
+	return legacyMeetings([]impact.Meeting{meeting})[0], nil
+}
+
+func (m *mongo) GetAssessmentMeeting(u auth.User) (impact.Meeting, error) {
+	meeting := impact.Meeting{}
+
+	id, ok := u.GetAssessmentScope()
+	if !ok {
+		return meeting, data.ErrNotScoped
+	}
+
+	col, closer := m.getMeetingCollection()
+	defer closer()
+
+	if err := col.Find(bson.M{
+		"_id":     id,
+		"deleted": bson.M{"$ne": true},
+	}).One(&meeting); err != nil {
+		if mgo.ErrNotFound == err {
+			return meeting, data.NewNotFoundError("Meeting")
+		}
+		return meeting, err
+	}
+	return legacyMeetings([]impact.Meeting{meeting})[0], nil
 }
 
 type meetingGetter func(col *mgo.Collection, userOrg string) ([]impact.Meeting, error)
@@ -44,15 +79,18 @@ func (m *mongo) getMeetings(inner meetingGetter, u auth.User) ([]impact.Meeting,
 	if err != nil {
 		return nil, err
 	}
-	return inner(col, userOrg)
+	meetings, err := inner(col, userOrg)
+	return legacyMeetings(meetings), err
 }
 
 func (m *mongo) GetMeetingsForBeneficiary(beneficiary string, u auth.User) ([]impact.Meeting, error) {
 	return m.getMeetings(func(col *mgo.Collection, userOrg string) ([]impact.Meeting, error) {
 		results := []impact.Meeting{}
 		err := col.Find(bson.M{
-			"beneficiary":    beneficiary,
+			"beneficiary":    m.beneficiaryKey(userOrg, beneficiary),
 			"organisationID": userOrg,
+			// meetings stored before soft deletion was introduced lack the field
+			"deleted": bson.M{"$ne": true},
 		}).All(&results)
 		return results, err
 	}, u)
@@ -62,9 +100,10 @@ func (m *mongo) GetOSMeetingsForBeneficiary(beneficiary string, outcomeSetID str
 	return m.getMeetings(func(col *mgo.Collection, userOrg string) ([]impact.Meeting, error) {
 		results := []impact.Meeting{}
 		err := col.Find(bson.M{
-			"beneficiary":    beneficiary,
+			"beneficiary":    m.beneficiaryKey(userOrg, beneficiary),
 			"organisationID": userOrg,
 			"outcomeSetID":   outcomeSetID,
+			"deleted":        bson.M{"$ne": true},
 		}).All(&results)
 		return results, err
 	}, u)
@@ -80,6 +119,7 @@ func (m *mongo) GetOSMeetingsInTimeRange(start, end time.Time, outcomeSetID stri
 				"$gte": start,
 				"$lte": end,
 			},
+			"deleted": bson.M{"$ne": true},
 		}).All(&results)
 		return results, err
 	}, u)
@@ -91,26 +131,69 @@ func (m *mongo) NewMeeting(beneficiaryID, outcomeSetID string, conducted time.Ti
 		return impact.Meeting{}, err
 	}
 
+	beneficiaryKey, err := m.storeBeneficiaryKey(userOrg, beneficiaryID)
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+
+	os, err := m.GetOutcomeSet(outcomeSetID, u)
+	if err != nil && !data.IsNotFound(err) {
+		return impact.Meeting{}, err
+	}
+	// outcome sets are published by their first meeting, so every meeting is pinned to a version
+	if err == nil && os.Version == 0 {
+		published, err := m.PublishOutcomeSet(outcomeSetID, u)
+		if err == errConcurrentPublish {
+			// published by a concurrent first meeting
+			published, err = m.GetOutcomeSet(outcomeSetID, u)
+		}
+		if err != nil {
+			return impact.Meeting{}, err
+		}
+		os = published
+	}
+
 	col, closer := m.getMeetingCollection()
 	defer closer()
 
 	meeting := impact.Meeting{
-		ID:             uuid.NewV4().String(),
-		OrganisationID: userOrg,
-		OutcomeSetID:   outcomeSetID,
-		Beneficiary:    beneficiaryID,
-		Conducted:      conducted,
-		Created:        time.Now(),
-		Modified:       time.Now(),
-		User:           u.UserID(),
+		ID:                uuid.NewV4().String(),
+		OrganisationID:    userOrg,
+		OutcomeSetID:      outcomeSetID,
+		Beneficiary:       beneficiaryKey,
+		Conducted:         conducted,
+		Created:           time.Now(),
+		Modified:          time.Now(),
+		User:              u.UserID(),
+		Status:            impact.IN_PROGRESS,
+		OutcomeSetVersion: os.Version,
 	}
 
 	if err := col.Insert(meeting); err != nil {
 		return impact.Meeting{}, err
 	}
+	if err := m.ensureBeneficiary(beneficiaryKey, userOrg, meeting.Created); err != nil {
+		return impact.Meeting{}, err
+	}
 	return meeting, nil
 }
 
+func (m *mongo) updateMeeting(id string, userOrg string, update bson.M) error {
+	col, closer := m.getMeetingCollection()
+	defer closer()
+
+	if err := col.Update(bson.M{
+		"_id":            id,
+		"organisationID": userOrg,
+	}, update); err != nil {
+		if mgo.ErrNotFound == err {
+			return data.NewNotFoundError("Meeting")
+		}
+		return err
+	}
+	return nil
+}
+
 func (m *mongo) NewAnswer(meetingID string, answer impact.Answer, u auth.User) (impact.Meeting, error) {
 	userOrg, err := u.Organisation()
 	if err != nil {
@@ -120,16 +203,102 @@ func (m *mongo) NewAnswer(meetingID string, answer impact.Answer, u auth.User) (
 	col, closer := m.getMeetingCollection()
 	defer closer()
 
-	if err := col.Update(bson.M{
-		"_id":            meetingID,
-		"organisationID": userOrg,
-	}, bson.M{
-		"$push": bson.M{
-			"answers": answer,
+	// replace an existing answer to the question in place, otherwise append it.
+	// the push is guarded so concurrent requests cannot append duplicates, if it matches nothing the answer was
+	// added concurrently, so the replacement is retried before the meeting is reported as missing.
+	replace := func() error {
+		return col.Update(bson.M{
+			"_id":                meetingID,
+			"organisationID":     userOrg,
+			"answers.questionID": answer.QuestionID,
+		}, bson.M{
+			"$set": bson.M{
+				"answers.$": answer,
+				"modified":  time.Now(),
+			},
+		})
+	}
+	err = replace()
+	if err == mgo.ErrNotFound {
+		err = col.Update(bson.M{
+			"_id":                meetingID,
+			"organisationID":     userOrg,
+			"answers.questionID": bson.M{"$ne": answer.QuestionID},
+		}, bson.M{
+			"$push": bson.M{
+				"answers": answer,
+			},
+			"$set": bson.M{
+				"modified": time.Now(),
+			},
+		})
+	}
+	if err == mgo.ErrNotFound {
+		err = replace()
+	}
+	if err != nil {
+		if mgo.ErrNotFound == err {
+			return impact.Meeting{}, data.NewNotFoundError("Meeting")
+		}
+		return impact.Meeting{}, err
+	}
+
+	return m.GetMeeting(meetingID, u)
+}
+
+func (m *mongo) EditMeeting(id, beneficiaryID string, conducted time.Time, u auth.User) (impact.Meeting, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+
+	beneficiaryKey, err := m.storeBeneficiaryKey(userOrg, beneficiaryID)
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+
+	if err := m.updateMeeting(id, userOrg, bson.M{
+		"$set": bson.M{
+			"beneficiary": beneficiaryKey,
+			"conducted":   conducted,
+			"modified":    time.Now(),
 		},
 	}); err != nil {
 		return impact.Meeting{}, err
 	}
+	if err := m.ensureBeneficiary(beneficiaryKey, userOrg, time.Now()); err != nil {
+		return impact.Meeting{}, err
+	}
+	return m.GetMeeting(id, u)
+}
 
-	return m.GetMeeting(meetingID, u)
+func (m *mongo) DeleteMeeting(id string, u auth.User) error {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return err
+	}
+
+	return m.updateMeeting(id, userOrg, bson.M{
+		"$set": bson.M{
+			"deleted":  true,
+			"modified": time.Now(),
+		},
+	})
+}
+
+func (m *mongo) SetMeetingStatus(id string, status impact.MeetingStatus, u auth.User) (impact.Meeting, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+
+	if err := m.updateMeeting(id, userOrg, bson.M{
+		"$set": bson.M{
+			"status":   status,
+			"modified": time.Now(),
+		},
+	}); err != nil {
+		return impact.Meeting{}, err
+	}
+	return m.GetMeeting(id, u)
 }
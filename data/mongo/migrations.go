@@ -0,0 +1,240 @@
+package mongo
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/log"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Migration evolves the shape of the documents stored in mongo
+type Migration struct {
+	// Version orders the migrations, it must be unique and must never change once released
+	Version int
+	// Description is a short, human readable summary of the migration
+	Description string
+	// Up applies the migration to the database. It should be safe to rerun if a previous attempt failed part way through.
+	Up func(db *mgo.Database) error
+}
+
+type appliedMigration struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	Applied     time.Time `bson:"applied"`
+}
+
+// migrations must be listed in ascending version order.
+// Existing migrations must never be edited, append a new migration instead.
+var migrations = []Migration{{
+	Version:     1,
+	Description: "Backfill modified on meetings which lack it",
+	Up: func(db *mgo.Database) error {
+		col := db.C("meetings")
+		iter := col.Find(bson.M{
+			"modified": bson.M{"$exists": false},
+		}).Select(bson.M{"created": 1}).Iter()
+		doc := struct {
+			ID      string    `bson:"_id"`
+			Created time.Time `bson:"created"`
+		}{}
+		for iter.Next(&doc) {
+			if err := col.UpdateId(doc.ID, bson.M{
+				"$set": bson.M{"modified": doc.Created},
+			}); err != nil {
+				iter.Close()
+				return err
+			}
+		}
+		return iter.Close()
+	},
+}, {
+	Version:     2,
+	Description: "Remove duplicate answers to the same question, keeping the latest",
+	Up: func(db *mgo.Database) error {
+		col := db.C("meetings")
+		iter := col.Find(nil).Select(bson.M{"answers": 1}).Iter()
+		doc := struct {
+			ID      string   `bson:"_id"`
+			Answers []bson.M `bson:"answers"`
+		}{}
+		for iter.Next(&doc) {
+			deduped := []bson.M{}
+			position := map[interface{}]int{}
+			for _, a := range doc.Answers {
+				if i, ok := position[a["questionID"]]; ok {
+					deduped[i] = a
+					continue
+				}
+				position[a["questionID"]] = len(deduped)
+				deduped = append(deduped, a)
+			}
+			if len(deduped) == len(doc.Answers) {
+				continue
+			}
+			if err := col.UpdateId(doc.ID, bson.M{
+				"$set": bson.M{"answers": deduped},
+			}); err != nil {
+				iter.Close()
+				return err
+			}
+		}
+		return iter.Close()
+	},
+}, {
+	Version:     3,
+	Description: "Set the status of meetings which predate the completion lifecycle",
+	Up: func(db *mgo.Database) error {
+		// existing meetings were all reported on so are marked as completed, matching the other backends
+		_, err := db.C("meetings").UpdateAll(bson.M{
+			"status": bson.M{"$exists": false},
+		}, bson.M{
+			"$set": bson.M{"status": impact.COMPLETED},
+		})
+		return err
+	},
+}, {
+	Version:     4,
+	Description: "Create records for the beneficiaries of existing meetings",
+	Up: func(db *mgo.Database) error {
+		iter := db.C("meetings").Pipe([]bson.M{{
+			"$group": bson.M{
+				"_id": bson.M{
+					"organisationID": "$organisationID",
+					"beneficiary":    "$beneficiary",
+				},
+				"created": bson.M{"$min": "$created"},
+			},
+		}}).Iter()
+		doc := struct {
+			ID struct {
+				OrganisationID string `bson:"organisationID"`
+				Beneficiary    string `bson:"beneficiary"`
+			} `bson:"_id"`
+			Created time.Time `bson:"created"`
+		}{}
+		beneficiaries := db.C("beneficiaries")
+		for iter.Next(&doc) {
+			if err := ensureBeneficiary(beneficiaries, doc.ID.Beneficiary, doc.ID.OrganisationID, doc.Created); err != nil {
+				iter.Close()
+				return err
+			}
+		}
+		return iter.Close()
+	},
+}, {
+	Version:     5,
+	Description: "Publish outcome sets which predate versioning and pin their meetings to that version",
+	Up: func(db *mgo.Database) error {
+		col := db.C("outcomesets")
+		unpublished := []impact.OutcomeSet{}
+		if err := col.Find(bson.M{
+			"version": bson.M{"$in": []interface{}{0, nil}},
+		}).All(&unpublished); err != nil {
+			return err
+		}
+		for _, os := range unpublished {
+			os.Version = 1
+			os.Published = time.Now()
+			// the copy may have been stored by a previous attempt
+			if err := insertOutcomeSetVersion(db.C("outcomesetversions"), os); err != nil && !mgo.IsDup(err) {
+				return err
+			}
+			if err := col.UpdateId(os.ID, bson.M{
+				"$set": bson.M{"version": os.Version, "published": os.Published},
+			}); err != nil {
+				return err
+			}
+		}
+
+		outcomeSets := []struct {
+			ID             string `bson:"_id"`
+			OrganisationID string `bson:"organisationID"`
+		}{}
+		if err := col.Find(nil).Select(bson.M{"organisationID": 1}).All(&outcomeSets); err != nil {
+			return err
+		}
+		for _, os := range outcomeSets {
+			if _, err := db.C("meetings").UpdateAll(bson.M{
+				"organisationID":    os.OrganisationID,
+				"outcomeSetID":      os.ID,
+				"outcomeSetVersion": bson.M{"$in": []interface{}{0, nil}},
+			}, bson.M{
+				"$set": bson.M{"outcomeSetVersion": 1},
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}}
+
+func (m *mongo) getMigrationCollection() (*mgo.Collection, sessionEnder) {
+	session := m.baseSession.Copy()
+	return session.DB("").C("schema_migrations"), session.Close
+}
+
+// pendingMigrations returns the migrations which have not been applied to the database, in the order they should be applied
+func (m *mongo) pendingMigrations() ([]Migration, error) {
+	col, closer := m.getMigrationCollection()
+	defer closer()
+
+	applied := []appliedMigration{}
+	if err := col.Find(nil).All(&applied); err != nil {
+		return nil, err
+	}
+	done := make(map[int]bool, len(applied))
+	for _, a := range applied {
+		done[a.Version] = true
+	}
+
+	pending := []Migration{}
+	for _, mig := range migrations {
+		if !done[mig.Version] {
+			pending = append(pending, mig)
+		}
+	}
+	return pending, nil
+}
+
+// Migrate applies any pending migrations to the database, returning those which were applied.
+// If dryRun is true, the pending migrations are returned without being applied.
+func Migrate(hostname string, port int, database, user, password string, dryRun bool) ([]Migration, error) {
+	session, err := dial(hostname, port, database, user, password)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	m := &mongo{
+		baseSession: session,
+	}
+	pending, err := m.pendingMigrations()
+	if err != nil || dryRun {
+		return pending, err
+	}
+
+	col, closer := m.getMigrationCollection()
+	defer closer()
+
+	for i, mig := range pending {
+		if err := mig.Up(col.Database); err != nil {
+			return pending[:i], fmt.Errorf("Mongo migration %d failed: %s", mig.Version, err.Error())
+		}
+		if err := col.Insert(appliedMigration{
+			Version:     mig.Version,
+			Description: mig.Description,
+			Applied:     time.Now(),
+		}); err != nil {
+			return pending[:i], err
+		}
+		log.Info("Applied mongo migration", map[string]string{
+			"version":     strconv.Itoa(mig.Version),
+			"description": mig.Description,
+		})
+	}
+	return pending, nil
+}
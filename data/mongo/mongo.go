@@ -3,17 +3,21 @@ package mongo
 import (
 	"fmt"
 	"github.com/impactasaurus/server/data"
+	"github.com/impactasaurus/server/log"
 	"gopkg.in/mgo.v2"
+	"strconv"
 	"time"
 )
 
 type mongo struct {
 	baseSession *mgo.Session
+	// pseudonyms is nil if beneficiary IDs are stored as provided
+	pseudonyms *pseudonymiser
 }
 
-func New(hostname string, port int, database, user, password string) (data.Base, error) {
+func dial(hostname string, port int, database, user, password string) (*mgo.Session, error) {
 	url := fmt.Sprint(hostname, ":", port)
-	session, err := mgo.DialWithInfo(&mgo.DialInfo{
+	return mgo.DialWithInfo(&mgo.DialInfo{
 		Addrs:    []string{url},
 		Timeout:  time.Duration(60) * time.Second,
 		Database: database,
@@ -21,7 +25,10 @@ func New(hostname string, port int, database, user, password string) (data.Base,
 		Username: user,
 		Password: password,
 	})
+}
 
+func New(hostname string, port int, database, user, password string, opts ...Option) (data.Base, error) {
+	session, err := dial(hostname, port, database, user, password)
 	if err != nil {
 		return nil, err
 	}
@@ -29,9 +36,28 @@ func New(hostname string, port int, database, user, password string) (data.Base,
 	m := &mongo{
 		baseSession: session,
 	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.pseudonyms != nil {
+		if err := m.pseudonyms.validate(); err != nil {
+			session.Close()
+			return nil, err
+		}
+	}
 	if err := m.ensureIndexes(); err != nil {
 		return nil, err
 	}
+	m.checkQueryPlans()
+	pending, err := m.pendingMigrations()
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) > 0 {
+		log.Info("Mongo migrations are pending, apply them with the migrate command", map[string]string{
+			"pending": strconv.Itoa(len(pending)),
+		})
+	}
 	return m, nil
 }
 
@@ -40,7 +66,77 @@ func (m *mongo) ensureIndexes() error {
 	defer osCloser()
 
 	if err := osCol.EnsureIndex(mgo.Index{
-		Key:    []string{"organisationID", "name"},
+		Key: []string{"organisationID", "name"},
+	}); err != nil {
+		return err
+	}
+
+	versionCol, versionCloser := m.getOutcomeSetVersionCollection()
+	defer versionCloser()
+
+	if err := versionCol.EnsureIndex(mgo.Index{
+		Key:    []string{"organisationID", "outcomeSetID", "version"},
+		Unique: true,
+	}); err != nil {
+		return err
+	}
+
+	meetingCol, meetingCloser := m.getMeetingCollection()
+	defer meetingCloser()
+
+	// also serves beneficiary lookups which are not scoped to an outcome set
+	if err := meetingCol.EnsureIndex(mgo.Index{
+		Key: []string{"organisationID", "beneficiary", "outcomeSetID"},
+	}); err != nil {
+		return err
+	}
+	if err := meetingCol.EnsureIndex(mgo.Index{
+		Key: []string{"organisationID", "outcomeSetID", "conducted"},
+	}); err != nil {
+		return err
+	}
+
+	benCol, benCloser := m.getBeneficiaryCollection()
+	defer benCloser()
+
+	if err := benCol.EnsureIndex(mgo.Index{
+		Key:    []string{"organisationID", "id"},
+		Unique: true,
+	}); err != nil {
+		return err
+	}
+
+	scCol, scCloser := m.getShortCodeCollection()
+	defer scCloser()
+
+	// mongo removes short codes shortly after they expire
+	if err := scCol.EnsureIndex(mgo.Index{
+		Key:         []string{"expiry"},
+		ExpireAfter: time.Second,
+	}); err != nil {
+		return err
+	}
+
+	keyCol, keyCloser := m.getAPIKeyCollection()
+	defer keyCloser()
+
+	if err := keyCol.EnsureIndex(mgo.Index{
+		Key:    []string{"hash"},
+		Unique: true,
+	}); err != nil {
+		return err
+	}
+	if err := keyCol.EnsureIndex(mgo.Index{
+		Key: []string{"organisationID"},
+	}); err != nil {
+		return err
+	}
+
+	auditCol, auditCloser := m.getAuditCollection()
+	defer auditCloser()
+
+	if err := auditCol.EnsureIndex(mgo.Index{
+		Key: []string{"organisationID", "-timestamp"},
 	}); err != nil {
 		return err
 	}
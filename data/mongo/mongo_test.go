@@ -0,0 +1,263 @@
+// +build integration
+
+package mongo_test
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/impactasaurus/server/data"
+	"github.com/impactasaurus/server/data/conformance"
+	"github.com/impactasaurus/server/data/mongo"
+	"github.com/impactasaurus/server/mock"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func mustGetPort(t *testing.T) int {
+	port, err := strconv.Atoi(os.Getenv("MONGO_PORT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return port
+}
+
+func TestConformance(t *testing.T) {
+	port := mustGetPort(t)
+	conformance.Run(t, func(t *testing.T) data.Base {
+		// a fresh database per test case keeps the cases independent
+		database := fmt.Sprintf("conformance%d", time.Now().UnixNano())
+		db, err := mongo.New(os.Getenv("MONGO_URL"), port, database, os.Getenv("MONGO_USER"), os.Getenv("MONGO_PASS"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return db
+	})
+}
+
+func TestMigrate(t *testing.T) {
+	port := mustGetPort(t)
+	database := fmt.Sprintf("migrate%d", time.Now().UnixNano())
+	migrate := func(dryRun bool) []mongo.Migration {
+		migrations, err := mongo.Migrate(os.Getenv("MONGO_URL"), port, database, os.Getenv("MONGO_USER"), os.Getenv("MONGO_PASS"), dryRun)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return migrations
+	}
+
+	pending := migrate(true)
+	assert.NotEmpty(t, pending)
+	assert.Len(t, migrate(true), len(pending), "a dry run should not apply migrations")
+	assert.Len(t, migrate(false), len(pending))
+	assert.Empty(t, migrate(false), "migrations should only be applied once")
+}
+
+func TestLegacyMeetingStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	u := mock.NewMockUser(ctrl)
+	u.EXPECT().Organisation().Return("org", nil).AnyTimes()
+
+	port := mustGetPort(t)
+	database := fmt.Sprintf("legacy%d", time.Now().UnixNano())
+	session, err := mgo.Dial(fmt.Sprint(os.Getenv("MONGO_URL"), ":", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+	// stored before the completion lifecycle, so without a status
+	if err := session.DB(database).C("meetings").Insert(bson.M{
+		"_id":            "legacy",
+		"organisationID": "org",
+		"outcomeSetID":   "os",
+		"beneficiary":    "ben",
+		"answers":        []bson.M{},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := mongo.New(os.Getenv("MONGO_URL"), port, database, os.Getenv("MONGO_USER"), os.Getenv("MONGO_PASS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := db.GetMeeting("legacy", u)
+	assert.Nil(t, err)
+	assert.True(t, m.IsComplete(), "meetings should be reported on before migrations are applied")
+
+	if _, err := mongo.Migrate(os.Getenv("MONGO_URL"), port, database, os.Getenv("MONGO_USER"), os.Getenv("MONGO_PASS"), false); err != nil {
+		t.Fatal(err)
+	}
+	stored := bson.M{}
+	assert.Nil(t, session.DB(database).C("meetings").FindId("legacy").One(&stored))
+	assert.Equal(t, "completed", stored["status"])
+}
+
+func TestLegacyOutcomeSetVersions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	u := mock.NewMockUser(ctrl)
+	u.EXPECT().Organisation().Return("org", nil).AnyTimes()
+
+	port := mustGetPort(t)
+	database := fmt.Sprintf("versions%d", time.Now().UnixNano())
+	session, err := mgo.Dial(fmt.Sprint(os.Getenv("MONGO_URL"), ":", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+	// stored before outcome set versioning, so without versions
+	if err := session.DB(database).C("outcomesets").Insert(bson.M{
+		"_id":            "os",
+		"organisationID": "org",
+		"name":           "legacy",
+		"questions":      []bson.M{},
+		"categories":     []bson.M{},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := session.DB(database).C("meetings").Insert(bson.M{
+		"_id":            "legacy",
+		"organisationID": "org",
+		"outcomeSetID":   "os",
+		"beneficiary":    "ben",
+		"answers":        []bson.M{},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := mongo.Migrate(os.Getenv("MONGO_URL"), port, database, os.Getenv("MONGO_USER"), os.Getenv("MONGO_PASS"), false); err != nil {
+		t.Fatal(err)
+	}
+	db, err := mongo.New(os.Getenv("MONGO_URL"), port, database, os.Getenv("MONGO_USER"), os.Getenv("MONGO_PASS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := db.GetMeeting("legacy", u)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, m.OutcomeSetVersion)
+	published, err := db.GetOutcomeSetVersion("os", 1, u)
+	assert.Nil(t, err)
+	assert.Equal(t, "legacy", published.Name)
+	outcomeSet, err := db.GetOutcomeSet("os", u)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, outcomeSet.Version)
+}
+
+func TestPublishOutcomeSetFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	u := mock.NewMockUser(ctrl)
+	u.EXPECT().Organisation().Return("org", nil).AnyTimes()
+	u.EXPECT().UserID().Return("user").AnyTimes()
+
+	port := mustGetPort(t)
+	database := fmt.Sprintf("publish%d", time.Now().UnixNano())
+	session, err := mgo.Dial(fmt.Sprint(os.Getenv("MONGO_URL"), ":", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+	db, err := mongo.New(os.Getenv("MONGO_URL"), port, database, os.Getenv("MONGO_USER"), os.Getenv("MONGO_PASS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	outcomeSet, err := db.NewOutcomeSet("os", "", u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// occupies the next version, so storing the published copy fails
+	if err := session.DB(database).C("outcomesetversions").Insert(bson.M{
+		"_id":            outcomeSet.ID + ":1",
+		"organisationID": "other",
+		"outcomeSetID":   outcomeSet.ID,
+		"version":        1,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.PublishOutcomeSet(outcomeSet.ID, u)
+	assert.NotNil(t, err)
+	stored, err := db.GetOutcomeSet(outcomeSet.ID, u)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, stored.Version, "a version should not be advertised unless its copy was stored")
+}
+
+func TestPseudonyms(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	u := mock.NewMockUser(ctrl)
+	u.EXPECT().Organisation().Return("org", nil).AnyTimes()
+	u.EXPECT().UserID().Return("user").AnyTimes()
+
+	port := mustGetPort(t)
+	database := fmt.Sprintf("pseudonyms%d", time.Now().UnixNano())
+	mappingDatabase := database + "mapping"
+	key := []byte("a-test-key-which-is-long-enough-to-use")
+	connect := func(opts ...mongo.Option) data.Base {
+		db, err := mongo.New(os.Getenv("MONGO_URL"), port, database, os.Getenv("MONGO_USER"), os.Getenv("MONGO_PASS"), opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return db
+	}
+	session, err := mgo.Dial(fmt.Sprint(os.Getenv("MONGO_URL"), ":", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+	countPlaintext := func(col string) int {
+		n, err := session.DB(database).C(col).Find(bson.M{"beneficiary": bson.RegEx{Pattern: "Doe"}}).Count()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	_, err = mongo.New(os.Getenv("MONGO_URL"), port, database, os.Getenv("MONGO_USER"), os.Getenv("MONGO_PASS"), mongo.WithPseudonyms([]byte("short"), ""))
+	assert.NotNil(t, err, "short keys should be rejected")
+
+	// meetings stored before pseudonyms were enabled are converted by Pseudonymise
+	plain := connect()
+	_, err = plain.NewMeeting("John Doe", "os", time.Now(), u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, countPlaintext("meetings"))
+
+	db := connect(mongo.WithPseudonyms(key, mappingDatabase))
+	m, err := db.NewMeeting("Jane Doe", "os", time.Now(), u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Regexp(t, "^psn_[0-9a-f]{32}$", m.Beneficiary)
+	assert.Equal(t, 1, countPlaintext("meetings"), "new meetings should be stored with a pseudonym")
+
+	byName, err := db.GetMeetingsForBeneficiary("Jane Doe", u)
+	assert.Nil(t, err)
+	assert.Len(t, byName, 1)
+	byPseudonym, err := db.GetMeetingsForBeneficiary(m.Beneficiary, u)
+	assert.Nil(t, err)
+	assert.Len(t, byPseudonym, 1)
+
+	mappings, err := session.DB(mappingDatabase).C("beneficiarypseudonyms").Find(bson.M{"beneficiary": "Jane Doe"}).Count()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, mappings)
+
+	updated, err := mongo.Pseudonymise(os.Getenv("MONGO_URL"), port, database, os.Getenv("MONGO_USER"), os.Getenv("MONGO_PASS"), key, mappingDatabase)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, updated)
+	assert.Equal(t, 0, countPlaintext("meetings"))
+	john, err := db.GetMeetingsForBeneficiary("John Doe", u)
+	assert.Nil(t, err)
+	assert.Len(t, john, 1)
+
+	updated, err = mongo.Pseudonymise(os.Getenv("MONGO_URL"), port, database, os.Getenv("MONGO_USER"), os.Getenv("MONGO_PASS"), key, mappingDatabase)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, updated, "pseudonymising should be idempotent")
+}
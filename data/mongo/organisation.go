@@ -5,6 +5,7 @@ import (
 	"github.com/impactasaurus/server/auth"
 	"github.com/impactasaurus/server/data"
 	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
 	"errors"
 )
 
@@ -32,3 +33,52 @@ func (m *mongo) GetOrganisation(id string, u auth.User) (impact.Organisation, er
 	}
 	return org, nil
 }
+
+func (m *mongo) CreateOrganisation(name string, u auth.User) (impact.Organisation, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Organisation{}, err
+	}
+
+	col, closer := m.getOrganisationCollection()
+	defer closer()
+
+	org := impact.Organisation{
+		ID:   userOrg,
+		Name: name,
+	}
+	if err := col.Insert(org); err != nil {
+		if mgo.IsDup(err) {
+			return impact.Organisation{}, data.ErrOrganisationExists
+		}
+		return impact.Organisation{}, err
+	}
+	return org, nil
+}
+
+func (m *mongo) EditOrganisation(id, name string, settings impact.OrganisationSettings, u auth.User) (impact.Organisation, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Organisation{}, err
+	}
+
+	if id != userOrg {
+		return impact.Organisation{}, errors.New("User does not have permission to edit this organisation")
+	}
+
+	col, closer := m.getOrganisationCollection()
+	defer closer()
+
+	if err := col.UpdateId(id, bson.M{
+		"$set": bson.M{
+			"name":     name,
+			"settings": settings,
+		},
+	}); err != nil {
+		if mgo.ErrNotFound == err {
+			return impact.Organisation{}, data.NewNotFoundError("Organisation")
+		}
+		return impact.Organisation{}, err
+	}
+	return m.GetOrganisation(id, u)
+}
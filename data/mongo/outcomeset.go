@@ -2,10 +2,14 @@ package mongo
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
+	"time"
 
 	impact "github.com/impactasaurus/server"
 	"github.com/impactasaurus/server/auth"
 	"github.com/impactasaurus/server/data"
+	"github.com/impactasaurus/server/log"
 	uuid "github.com/satori/go.uuid"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
@@ -131,3 +135,127 @@ func (m *mongo) DeleteOutcomeSet(id string, u auth.User) error {
 		},
 	})
 }
+
+// outcomeSetVersion wraps a published copy of an outcome set, as the copy's _id would clash with its outcome set's
+type outcomeSetVersion struct {
+	ID             string            `bson:"_id"`
+	OutcomeSetID   string            `bson:"outcomeSetID"`
+	OrganisationID string            `bson:"organisationID"`
+	Version        int               `bson:"version"`
+	OutcomeSet     impact.OutcomeSet `bson:"outcomeSet"`
+}
+
+func outcomeSetVersionID(id string, version int) string {
+	return fmt.Sprintf("%s:%d", id, version)
+}
+
+func insertOutcomeSetVersion(col *mgo.Collection, os impact.OutcomeSet) error {
+	return col.Insert(outcomeSetVersion{
+		ID:             outcomeSetVersionID(os.ID, os.Version),
+		OutcomeSetID:   os.ID,
+		OrganisationID: os.OrganisationID,
+		Version:        os.Version,
+		OutcomeSet:     os,
+	})
+}
+
+var errConcurrentPublish = errors.New("Outcome set was published by another request, please retry")
+
+func (m *mongo) PublishOutcomeSet(id string, u auth.User) (impact.OutcomeSet, error) {
+	os, err := m.GetOutcomeSet(id, u)
+	if err != nil {
+		return impact.OutcomeSet{}, err
+	}
+	previous := os.Version
+	os.Version++
+	os.Published = time.Now()
+
+	versionCol, versionCloser := m.getOutcomeSetVersionCollection()
+	defer versionCloser()
+
+	// the copy is stored before the outcome set advertises its version, so an advertised version always exists
+	if err := insertOutcomeSetVersion(versionCol, os); err != nil {
+		if mgo.IsDup(err) {
+			return impact.OutcomeSet{}, errConcurrentPublish
+		}
+		return impact.OutcomeSet{}, err
+	}
+
+	col, closer := m.getOutcomeCollection()
+	defer closer()
+
+	// the version only advances if the outcome set has not been published since it was read
+	var current interface{} = previous
+	if previous == 0 {
+		// outcome sets which predate versioning lack the field
+		current = bson.M{"$in": []interface{}{0, nil}}
+	}
+	if err := col.Update(bson.M{
+		"_id":            id,
+		"organisationID": os.OrganisationID,
+		"version":        current,
+	}, bson.M{
+		"$set": bson.M{
+			"version":   os.Version,
+			"published": os.Published,
+		},
+	}); err != nil {
+		if removeErr := versionCol.RemoveId(outcomeSetVersionID(id, os.Version)); removeErr != nil {
+			log.Error(removeErr, map[string]string{
+				"outcomeSetID": id,
+				"version":      strconv.Itoa(os.Version),
+			})
+		}
+		if mgo.ErrNotFound == err {
+			return impact.OutcomeSet{}, errConcurrentPublish
+		}
+		return impact.OutcomeSet{}, err
+	}
+	return os, nil
+}
+
+func (m *mongo) GetOutcomeSetVersion(id string, version int, u auth.User) (impact.OutcomeSet, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.OutcomeSet{}, err
+	}
+
+	col, closer := m.getOutcomeSetVersionCollection()
+	defer closer()
+
+	v := outcomeSetVersion{}
+	if err := col.Find(bson.M{
+		"organisationID": userOrg,
+		"outcomeSetID":   id,
+		"version":        version,
+	}).One(&v); err != nil {
+		if mgo.ErrNotFound == err {
+			return impact.OutcomeSet{}, data.NewNotFoundError("Outcome Set Version")
+		}
+		return impact.OutcomeSet{}, err
+	}
+	return v.OutcomeSet, nil
+}
+
+func (m *mongo) GetOutcomeSetVersions(id string, u auth.User) ([]impact.OutcomeSet, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	col, closer := m.getOutcomeSetVersionCollection()
+	defer closer()
+
+	versions := []outcomeSetVersion{}
+	if err := col.Find(bson.M{
+		"organisationID": userOrg,
+		"outcomeSetID":   id,
+	}).Sort("version").All(&versions); err != nil {
+		return nil, err
+	}
+	results := make([]impact.OutcomeSet, 0, len(versions))
+	for _, v := range versions {
+		results = append(results, v.OutcomeSet)
+	}
+	return results, nil
+}
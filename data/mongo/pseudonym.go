@@ -0,0 +1,251 @@
+package mongo
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"regexp"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// minPseudonymKeyLength is the minimum length of the key used to derive pseudonyms, in bytes
+const minPseudonymKeyLength = 32
+
+// pseudonyms are the first 128 bits of the HMAC, hex encoded and prefixed so they can be recognised
+var pseudonymPattern = regexp.MustCompile(`^psn_[0-9a-f]{32}$`)
+
+// beneficiaryArguments are the audited mutation arguments which hold beneficiary IDs
+var beneficiaryArguments = []string{"beneficiaryID", "newBeneficiaryID"}
+
+type pseudonymiser struct {
+	key []byte
+	// database holds the mapping from pseudonyms to beneficiary IDs, if empty the pseudonymised database is used
+	database string
+}
+
+// Option configures the mongo backend
+type Option func(m *mongo)
+
+// WithPseudonyms stores beneficiary IDs as HMAC pseudonyms, keyed per organisation, rather than as provided.
+// Beneficiary IDs can be provided as either the original ID or its pseudonym, but only pseudonyms are returned,
+// so names entered as beneficiary IDs are not exposed by reports or database dumps.
+// The mapping from pseudonyms to beneficiary IDs is stored in the named database, which should be secured separately.
+// The key must be at least 32 bytes and must not change, otherwise existing beneficiaries can no longer be found.
+func WithPseudonyms(key []byte, mappingDatabase string) Option {
+	return func(m *mongo) {
+		m.pseudonyms = &pseudonymiser{
+			key:      key,
+			database: mappingDatabase,
+		}
+	}
+}
+
+func (p *pseudonymiser) validate() error {
+	if len(p.key) < minPseudonymKeyLength {
+		return errors.New("Pseudonym key must be at least 32 bytes")
+	}
+	return nil
+}
+
+// pseudonym derives the beneficiary's pseudonym. Each organisation has its own key, so the same beneficiary ID has
+// unrelated pseudonyms in different organisations.
+func (p *pseudonymiser) pseudonym(userOrg, id string) string {
+	if pseudonymPattern.MatchString(id) {
+		return id
+	}
+	orgMac := hmac.New(sha256.New, p.key)
+	orgMac.Write([]byte(userOrg))
+	mac := hmac.New(sha256.New, orgMac.Sum(nil))
+	mac.Write([]byte(id))
+	return "psn_" + hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+type pseudonymMapping struct {
+	Pseudonym      string `bson:"_id"`
+	OrganisationID string `bson:"organisationID"`
+	Beneficiary    string `bson:"beneficiary"`
+}
+
+func (m *mongo) getPseudonymCollection() (*mgo.Collection, sessionEnder) {
+	session := m.baseSession.Copy()
+	return session.DB(m.pseudonyms.database).C("beneficiarypseudonyms"), session.Close
+}
+
+// beneficiaryKey returns the ID the beneficiary is stored under
+func (m *mongo) beneficiaryKey(userOrg, id string) string {
+	if m.pseudonyms == nil {
+		return id
+	}
+	return m.pseudonyms.pseudonym(userOrg, id)
+}
+
+// storeBeneficiaryKey returns the ID the beneficiary is stored under, recording the pseudonym's mapping
+func (m *mongo) storeBeneficiaryKey(userOrg, id string) (string, error) {
+	key := m.beneficiaryKey(userOrg, id)
+	if key == id {
+		return id, nil
+	}
+
+	col, closer := m.getPseudonymCollection()
+	defer closer()
+
+	_, err := col.UpsertId(key, pseudonymMapping{
+		Pseudonym:      key,
+		OrganisationID: userOrg,
+		Beneficiary:    id,
+	})
+	return key, err
+}
+
+// forgetBeneficiaryKey removes the mapping of the beneficiary's pseudonym
+func (m *mongo) forgetBeneficiaryKey(key string) error {
+	if m.pseudonyms == nil {
+		return nil
+	}
+
+	col, closer := m.getPseudonymCollection()
+	defer closer()
+
+	err := col.RemoveId(key)
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+// pseudonymiseArguments replaces the beneficiary IDs within JSON encoded mutation arguments
+func (m *mongo) pseudonymiseArguments(userOrg, arguments string) string {
+	if m.pseudonyms == nil {
+		return arguments
+	}
+	args := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		return arguments
+	}
+	changed := false
+	for _, name := range beneficiaryArguments {
+		if id, ok := args[name].(string); ok {
+			args[name] = m.beneficiaryKey(userOrg, id)
+			changed = changed || args[name] != id
+		}
+	}
+	if !changed {
+		return arguments
+	}
+	b, err := json.Marshal(args)
+	if err != nil {
+		return arguments
+	}
+	return string(b)
+}
+
+// Pseudonymise replaces the beneficiary IDs stored in the database with pseudonyms, see WithPseudonyms.
+// It should be run when pseudonyms are first enabled, it is safe to rerun. The number of meetings updated is returned.
+func Pseudonymise(hostname string, port int, database, user, password string, key []byte, mappingDatabase string) (int, error) {
+	session, err := dial(hostname, port, database, user, password)
+	if err != nil {
+		return 0, err
+	}
+	defer session.Close()
+
+	m := &mongo{
+		baseSession: session,
+	}
+	WithPseudonyms(key, mappingDatabase)(m)
+	if err := m.pseudonyms.validate(); err != nil {
+		return 0, err
+	}
+	db := session.DB("")
+	notPseudonym := bson.M{"$not": bson.RegEx{Pattern: pseudonymPattern.String()}}
+
+	updated := 0
+	meetings := db.C("meetings")
+	iter := meetings.Find(bson.M{"beneficiary": notPseudonym}).Select(bson.M{"organisationID": 1, "beneficiary": 1}).Iter()
+	meeting := struct {
+		ID             string `bson:"_id"`
+		OrganisationID string `bson:"organisationID"`
+		Beneficiary    string `bson:"beneficiary"`
+	}{}
+	for iter.Next(&meeting) {
+		key, err := m.storeBeneficiaryKey(meeting.OrganisationID, meeting.Beneficiary)
+		if err != nil {
+			iter.Close()
+			return updated, err
+		}
+		if err := meetings.UpdateId(meeting.ID, bson.M{"$set": bson.M{"beneficiary": key}}); err != nil {
+			iter.Close()
+			return updated, err
+		}
+		updated++
+	}
+	if err := iter.Close(); err != nil {
+		return updated, err
+	}
+
+	beneficiaries := db.C("beneficiaries")
+	iter = beneficiaries.Find(bson.M{"id": notPseudonym}).Iter()
+	ben := struct {
+		ID             bson.ObjectId `bson:"_id"`
+		BeneficiaryID  string        `bson:"id"`
+		OrganisationID string        `bson:"organisationID"`
+	}{}
+	for iter.Next(&ben) {
+		key, err := m.storeBeneficiaryKey(ben.OrganisationID, ben.BeneficiaryID)
+		if err != nil {
+			iter.Close()
+			return updated, err
+		}
+		err = beneficiaries.UpdateId(ben.ID, bson.M{"$set": bson.M{"id": key}})
+		// a record was created for the pseudonym after pseudonyms were enabled, it supersedes the original record
+		if mgo.IsDup(err) {
+			err = beneficiaries.RemoveId(ben.ID)
+		}
+		if err != nil {
+			iter.Close()
+			return updated, err
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return updated, err
+	}
+
+	shortCodes := db.C("shortcodes")
+	iter = shortCodes.Find(bson.M{"beneficiary": notPseudonym}).Select(bson.M{"organisationID": 1, "beneficiary": 1}).Iter()
+	sc := struct {
+		Code           string `bson:"_id"`
+		OrganisationID string `bson:"organisationID"`
+		Beneficiary    string `bson:"beneficiary"`
+	}{}
+	for iter.Next(&sc) {
+		if err := shortCodes.UpdateId(sc.Code, bson.M{"$set": bson.M{"beneficiary": m.beneficiaryKey(sc.OrganisationID, sc.Beneficiary)}}); err != nil {
+			iter.Close()
+			return updated, err
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return updated, err
+	}
+
+	audit := db.C("auditlog")
+	iter = audit.Find(nil).Select(bson.M{"organisationID": 1, "arguments": 1}).Iter()
+	entry := struct {
+		ID             string `bson:"_id"`
+		OrganisationID string `bson:"organisationID"`
+		Arguments      string `bson:"arguments"`
+	}{}
+	for iter.Next(&entry) {
+		args := m.pseudonymiseArguments(entry.OrganisationID, entry.Arguments)
+		if args == entry.Arguments {
+			continue
+		}
+		if err := audit.UpdateId(entry.ID, bson.M{"$set": bson.M{"arguments": args}}); err != nil {
+			iter.Close()
+			return updated, err
+		}
+	}
+	return updated, iter.Close()
+}
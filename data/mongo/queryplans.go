@@ -0,0 +1,111 @@
+package mongo
+
+import (
+	"errors"
+	"time"
+
+	"github.com/impactasaurus/server/log"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type queryPattern struct {
+	name  string
+	get   func() (*mgo.Collection, sessionEnder)
+	query bson.M
+}
+
+// queryPatterns mirror the shape of the queries made by this package.
+// The values are placeholders, only the fields and operators influence the chosen plan.
+func (m *mongo) queryPatterns() []queryPattern {
+	return []queryPattern{{
+		name: "outcome sets for organisation",
+		get:  m.getOutcomeCollection,
+		query: bson.M{
+			"organisationID": "",
+			"deleted":        false,
+		},
+	}, {
+		name: "outcome sets by name",
+		get:  m.getOutcomeCollection,
+		query: bson.M{
+			"organisationID": "",
+			"name":           "",
+		},
+	}, {
+		name: "meetings for beneficiary",
+		get:  m.getMeetingCollection,
+		query: bson.M{
+			"organisationID": "",
+			"beneficiary":    "",
+			"deleted":        bson.M{"$ne": true},
+		},
+	}, {
+		name: "outcome set meetings for beneficiary",
+		get:  m.getMeetingCollection,
+		query: bson.M{
+			"organisationID": "",
+			"outcomeSetID":   "",
+			"beneficiary":    "",
+			"deleted":        bson.M{"$ne": true},
+		},
+	}, {
+		name: "outcome set meetings in time range",
+		get:  m.getMeetingCollection,
+		query: bson.M{
+			"organisationID": "",
+			"outcomeSetID":   "",
+			"conducted": bson.M{
+				"$gte": time.Time{},
+				"$lte": time.Now(),
+			},
+			"deleted": bson.M{"$ne": true},
+		},
+	}}
+}
+
+// checkQueryPlans logs an error for each query pattern which mongo would satisfy with a collection scan.
+// This does not prevent startup, it highlights missing indexes before they become a performance problem.
+func (m *mongo) checkQueryPlans() {
+	for _, p := range m.queryPatterns() {
+		tags := map[string]string{
+			"query": p.name,
+		}
+		col, closer := p.get()
+		tags["collection"] = col.Name
+		plan := bson.M{}
+		err := col.Find(p.query).Explain(&plan)
+		closer()
+		if err != nil {
+			log.Error(err, tags)
+			continue
+		}
+		if isCollectionScan(plan) {
+			log.Error(errors.New("Mongo query is running as a collection scan"), tags)
+		}
+	}
+}
+
+// isCollectionScan walks the explain output looking for a COLLSCAN stage,
+// or a BasicCursor for servers which predate query planner stages
+func isCollectionScan(v interface{}) bool {
+	switch t := v.(type) {
+	case bson.M:
+		if t["stage"] == "COLLSCAN" || t["cursor"] == "BasicCursor" {
+			return true
+		}
+		for k, child := range t {
+			// rejected plans were not used, so a scan within them is not a problem
+			if k != "rejectedPlans" && isCollectionScan(child) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, child := range t {
+			if isCollectionScan(child) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/impactasaurus/server/auth"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func (m *mongo) RevokeAssessment(meetingID string, u auth.User) error {
+	if _, err := m.GetMeeting(meetingID, u); err != nil {
+		return err
+	}
+
+	col, closer := m.getRevokedAssessmentCollection()
+	defer closer()
+
+	_, err := col.UpsertId(meetingID, bson.M{
+		"$setOnInsert": bson.M{
+			"revoked": time.Now(),
+		},
+	})
+	return err
+}
+
+func (m *mongo) IsAssessmentRevoked(meetingID string) (bool, error) {
+	col, closer := m.getRevokedAssessmentCollection()
+	defer closer()
+
+	count, err := col.FindId(meetingID).Count()
+	return count > 0, err
+}
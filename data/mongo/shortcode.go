@@ -0,0 +1,50 @@
+package mongo
+
+import (
+	"time"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	"gopkg.in/mgo.v2"
+)
+
+func (m *mongo) NewShortCode(code, meetingID, beneficiaryID string, expiry time.Time, u auth.User) (impact.ShortCode, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.ShortCode{}, err
+	}
+
+	col, closer := m.getShortCodeCollection()
+	defer closer()
+
+	sc := impact.ShortCode{
+		Code:           code,
+		MeetingID:      meetingID,
+		Beneficiary:    m.beneficiaryKey(userOrg, beneficiaryID),
+		OrganisationID: userOrg,
+		Expiry:         expiry,
+	}
+	if err := col.Insert(sc); err != nil {
+		if mgo.IsDup(err) {
+			return impact.ShortCode{}, data.ErrShortCodeInUse
+		}
+		return impact.ShortCode{}, err
+	}
+	return sc, nil
+}
+
+func (m *mongo) GetShortCode(code string) (impact.ShortCode, error) {
+	sc := impact.ShortCode{}
+
+	col, closer := m.getShortCodeCollection()
+	defer closer()
+
+	if err := col.FindId(code).One(&sc); err != nil {
+		if mgo.ErrNotFound == err {
+			return sc, data.NewNotFoundError("Short code")
+		}
+		return sc, err
+	}
+	return sc, nil
+}
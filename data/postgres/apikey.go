@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	"github.com/lib/pq"
+	uuid "github.com/satori/go.uuid"
+)
+
+const apiKeyColumns = `id, organisation_id, name, hash, scopes, created, user_id, revoked`
+
+func scanAPIKey(row interface {
+	Scan(dest ...interface{}) error
+}) (impact.APIKey, error) {
+	key := impact.APIKey{}
+	err := row.Scan(&key.ID, &key.OrganisationID, &key.Name, &key.Hash, pq.Array(&key.Scopes), &key.Created, &key.User, &key.Revoked)
+	return key, err
+}
+
+func (p *postgres) NewAPIKey(name, hash string, scopes []string, u auth.User) (impact.APIKey, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.APIKey{}, err
+	}
+
+	key := impact.APIKey{
+		ID:             uuid.NewV4().String(),
+		OrganisationID: userOrg,
+		Name:           name,
+		Hash:           hash,
+		Scopes:         scopes,
+		Created:        time.Now(),
+		User:           u.UserID(),
+	}
+	if _, err := p.db.Exec(`INSERT INTO api_keys (`+apiKeyColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, FALSE)`,
+		key.ID, key.OrganisationID, key.Name, key.Hash, pq.Array(key.Scopes), key.Created, key.User); err != nil {
+		return impact.APIKey{}, err
+	}
+	return key, nil
+}
+
+func (p *postgres) GetAPIKeys(u auth.User) ([]impact.APIKey, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := p.db.Query(`SELECT `+apiKeyColumns+` FROM api_keys WHERE organisation_id = $1 ORDER BY created`, userOrg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []impact.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (p *postgres) RevokeAPIKey(id string, u auth.User) error {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return err
+	}
+
+	res, err := p.db.Exec(`UPDATE api_keys SET revoked = TRUE WHERE id = $1 AND organisation_id = $2`, id, userOrg)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res, "API key")
+}
+
+func (p *postgres) GetAPIKeyByHash(hash string) (impact.APIKey, error) {
+	key, err := scanAPIKey(p.db.QueryRow(`SELECT `+apiKeyColumns+` FROM api_keys WHERE hash = $1`, hash))
+	if err == sql.ErrNoRows {
+		return key, data.NewNotFoundError("API key")
+	}
+	return key, err
+}
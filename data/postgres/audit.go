@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	"github.com/lib/pq"
+	uuid "github.com/satori/go.uuid"
+)
+
+func (p *postgres) NewAuditEntry(e impact.AuditEntry) (impact.AuditEntry, error) {
+	e.ID = uuid.NewV4().String()
+	e.Timestamp = time.Now()
+
+	if _, err := p.db.Exec(`INSERT INTO audit_log (id, organisation_id, user_id, mutation, arguments, before, after, timestamp)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		e.ID, e.OrganisationID, e.User, e.Mutation, e.Arguments, e.Before, e.After, e.Timestamp); err != nil {
+		return impact.AuditEntry{}, err
+	}
+	return e, nil
+}
+
+func (p *postgres) GetAuditEntries(filter data.AuditFilter, u auth.User) ([]impact.AuditEntry, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT id, organisation_id, user_id, mutation, arguments, before, after, timestamp
+		FROM audit_log WHERE organisation_id = $1`
+	args := []interface{}{userOrg}
+	where := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		query += " AND " + condition + " $" + strconv.Itoa(len(args))
+	}
+	if filter.Mutation != "" {
+		where("mutation =", filter.Mutation)
+	}
+	if filter.UserID != "" {
+		where("user_id =", filter.UserID)
+	}
+	if !filter.Start.IsZero() {
+		where("timestamp >=", filter.Start)
+	}
+	if !filter.End.IsZero() {
+		where("timestamp <=", filter.End)
+	}
+	query += " ORDER BY timestamp DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT " + strconv.Itoa(filter.Limit)
+	}
+
+	rows, err := p.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []impact.AuditEntry{}
+	for rows.Next() {
+		e := impact.AuditEntry{}
+		if err := rows.Scan(&e.ID, &e.OrganisationID, &e.User, &e.Mutation, &e.Arguments, &e.Before, &e.After, &e.Timestamp); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// eraseAuditEntries removes the organisation's entries which reference any of the IDs
+func eraseAuditEntries(tx *sql.Tx, userOrg string, references []string) error {
+	rows, err := tx.Query(`SELECT id, user_id, arguments, before, after FROM audit_log WHERE organisation_id = $1`, userOrg)
+	if err != nil {
+		return err
+	}
+	erased := []string{}
+	for rows.Next() {
+		e := impact.AuditEntry{}
+		if err := rows.Scan(&e.ID, &e.User, &e.Arguments, &e.Before, &e.After); err != nil {
+			rows.Close()
+			return err
+		}
+		if data.ReferencesAny(e, references) {
+			erased = append(erased, e.ID)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(erased) == 0 {
+		return nil
+	}
+	_, err = tx.Exec(`DELETE FROM audit_log WHERE id = ANY($1)`, pq.Array(erased))
+	return err
+}
@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	"github.com/lib/pq"
+)
+
+const beneficiaryColumns = `id, organisation_id, created, tags, status`
+
+func scanBeneficiary(row interface {
+	Scan(dest ...interface{}) error
+}) (impact.Beneficiary, error) {
+	b := impact.Beneficiary{}
+	err := row.Scan(&b.ID, &b.OrganisationID, &b.Created, pq.Array(&b.Tags), &b.Status)
+	if b.Tags == nil {
+		b.Tags = []string{}
+	}
+	return b, err
+}
+
+// ensureBeneficiary creates the beneficiary's record if it does not exist
+func ensureBeneficiary(q queryer, id, userOrg string, created time.Time) error {
+	_, err := q.Exec(`INSERT INTO beneficiaries (`+beneficiaryColumns+`) VALUES ($1, $2, $3, '{}', $4)
+		ON CONFLICT DO NOTHING`, id, userOrg, created, impact.ACTIVE)
+	return err
+}
+
+func (p *postgres) GetBeneficiary(id string, u auth.User) (impact.Beneficiary, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Beneficiary{}, err
+	}
+
+	b, err := scanBeneficiary(p.db.QueryRow(`SELECT `+beneficiaryColumns+` FROM beneficiaries
+		WHERE id = $1 AND organisation_id = $2`, id, userOrg))
+	if err == sql.ErrNoRows {
+		return b, data.NewNotFoundError("Beneficiary")
+	}
+	return b, err
+}
+
+func (p *postgres) GetBeneficiaries(u auth.User) ([]impact.Beneficiary, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := p.db.Query(`SELECT `+beneficiaryColumns+` FROM beneficiaries WHERE organisation_id = $1 ORDER BY id`, userOrg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []impact.Beneficiary{}
+	for rows.Next() {
+		b, err := scanBeneficiary(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, b)
+	}
+	return results, rows.Err()
+}
+
+func (p *postgres) EditBeneficiary(id string, tags []string, status impact.BeneficiaryStatus, u auth.User) (impact.Beneficiary, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Beneficiary{}, err
+	}
+
+	if tags == nil {
+		tags = []string{}
+	}
+	res, err := p.db.Exec(`UPDATE beneficiaries SET tags = $3, status = $4 WHERE id = $1 AND organisation_id = $2`,
+		id, userOrg, pq.Array(tags), status)
+	if err != nil {
+		return impact.Beneficiary{}, err
+	}
+	if err := expectAffected(res, "Beneficiary"); err != nil {
+		return impact.Beneficiary{}, err
+	}
+	return p.GetBeneficiary(id, u)
+}
+
+func (p *postgres) RenameBeneficiary(id, newID string, u auth.User) (impact.Beneficiary, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Beneficiary{}, err
+	}
+
+	if id == newID {
+		if _, err := p.GetBeneficiary(id, u); err != nil {
+			return impact.Beneficiary{}, err
+		}
+		return impact.Beneficiary{}, data.ErrBeneficiaryExists
+	}
+
+	if err := p.withTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(`UPDATE beneficiaries SET id = $3 WHERE id = $1 AND organisation_id = $2`, id, userOrg, newID)
+		if err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == uniqueViolation {
+				return data.ErrBeneficiaryExists
+			}
+			return err
+		}
+		if err := expectAffected(res, "Beneficiary"); err != nil {
+			return err
+		}
+		_, err = tx.Exec(`UPDATE meetings SET beneficiary = $3, modified = $4 WHERE beneficiary = $1 AND organisation_id = $2`,
+			id, userOrg, newID, time.Now())
+		return err
+	}); err != nil {
+		return impact.Beneficiary{}, err
+	}
+	return p.GetBeneficiary(newID, u)
+}
+
+func (p *postgres) GetAllMeetingsForBeneficiary(beneficiary string, u auth.User) ([]impact.Meeting, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+	return p.getMeetings(`organisation_id = $1 AND beneficiary = $2 ORDER BY conducted`, userOrg, beneficiary)
+}
+
+func (p *postgres) EraseBeneficiary(id string, u auth.User) error {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return err
+	}
+
+	return p.withTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(`DELETE FROM beneficiaries WHERE id = $1 AND organisation_id = $2`, id, userOrg)
+		if err != nil {
+			return err
+		}
+		records, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		erased := `SELECT id FROM meetings WHERE beneficiary = $1 AND organisation_id = $2`
+		references := []string{id}
+		rows, err := tx.Query(erased, id, userOrg)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var meetingID string
+			if err := rows.Scan(&meetingID); err != nil {
+				rows.Close()
+				return err
+			}
+			references = append(references, meetingID)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`DELETE FROM short_codes WHERE meeting_id IN (`+erased+`)`, id, userOrg); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`DELETE FROM revoked_assessments WHERE meeting_id IN (`+erased+`)`, id, userOrg); err != nil {
+			return err
+		}
+		// answers are removed by the cascading foreign key
+		res, err = tx.Exec(`DELETE FROM meetings WHERE beneficiary = $1 AND organisation_id = $2`, id, userOrg)
+		if err != nil {
+			return err
+		}
+		meetings, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if records == 0 && meetings == 0 {
+			return data.NewNotFoundError("Beneficiary")
+		}
+		return eraseAuditEntries(tx, userOrg, references)
+	})
+}
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	uuid "github.com/satori/go.uuid"
+)
+
+func (p *postgres) GetCategory(outcomeSetID, categoryID string, u auth.User) (impact.Category, error) {
+	os, err := p.GetOutcomeSet(outcomeSetID, u)
+	if err != nil {
+		return impact.Category{}, err
+	}
+
+	for _, c := range os.Categories {
+		if c.ID == categoryID {
+			return c, nil
+		}
+	}
+	return impact.Category{}, data.NewNotFoundError("Category")
+}
+
+func (p *postgres) NewCategory(outcomeSetID, name, description string, aggregation impact.Aggregation, u auth.User) (impact.Category, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Category{}, err
+	}
+
+	id := uuid.NewV4().String()
+	if err := p.withTx(func(tx *sql.Tx) error {
+		if err := checkOutcomeSet(tx, outcomeSetID, userOrg); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`INSERT INTO categories (id, outcome_set_id, position, name, description, aggregation)
+			VALUES ($1, $2, (SELECT COALESCE(MAX(position) + 1, 0) FROM categories WHERE outcome_set_id = $2), $3, $4, $5)`,
+			id, outcomeSetID, name, description, aggregation)
+		return err
+	}); err != nil {
+		return impact.Category{}, err
+	}
+
+	return p.GetCategory(outcomeSetID, id, u)
+}
+
+func (p *postgres) DeleteCategory(outcomeSetID, categoryID string, u auth.User) error {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return err
+	}
+
+	return p.withTx(func(tx *sql.Tx) error {
+		os, err := getOutcomeSet(tx, outcomeSetID, userOrg)
+		if err != nil {
+			return err
+		}
+		if len(os.GetCategoryQuestions(categoryID)) > 0 {
+			return errors.New("Cannot delete a category which is being used")
+		}
+		// only archived questions can still reference the category
+		if _, err := tx.Exec(`UPDATE questions SET category_id = NULL
+			WHERE outcome_set_id = $1 AND category_id = $2`, outcomeSetID, categoryID); err != nil {
+			return err
+		}
+		_, err = tx.Exec(`DELETE FROM categories WHERE id = $1 AND outcome_set_id = $2`, categoryID, outcomeSetID)
+		return err
+	})
+}
+
+func (p *postgres) EditCategory(outcomeSetID, categoryID string, name, description string, aggregation impact.Aggregation, u auth.User) (impact.Category, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Category{}, err
+	}
+
+	res, err := p.db.Exec(`UPDATE categories SET name = $4, description = $5, aggregation = $6
+		WHERE id = $1 AND outcome_set_id = $2
+		AND EXISTS (SELECT 1 FROM outcome_sets WHERE id = $2 AND organisation_id = $3)`,
+		categoryID, outcomeSetID, userOrg, name, description, aggregation)
+	if err != nil {
+		return impact.Category{}, err
+	}
+	if err := expectAffected(res, "Category"); err != nil {
+		return impact.Category{}, err
+	}
+	return p.GetCategory(outcomeSetID, categoryID, u)
+}
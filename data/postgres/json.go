@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+
+	impact "github.com/impactasaurus/server"
+)
+
+// JSONB columns hold question options and answer values. encoding/json decodes all numbers as float64,
+// whereas the rest of the server expects whole numbers to be ints, as they are when read from mongo.
+// Values are passed to the driver as strings, as lib/pq may otherwise send []byte as bytea.
+
+func encodeJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+func encodeOptions(options map[string]interface{}) (sql.NullString, error) {
+	if options == nil {
+		return sql.NullString{}, nil
+	}
+	s, err := encodeJSON(options)
+	return sql.NullString{
+		String: s,
+		Valid:  true,
+	}, err
+}
+
+func decodeJSON(b []byte) (interface{}, error) {
+	if b == nil {
+		return nil, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var out interface{}
+	if err := dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	return normaliseNumbers(out), nil
+}
+
+func decodeOptions(b []byte) (map[string]interface{}, error) {
+	decoded, err := decodeJSON(b)
+	if err != nil || decoded == nil {
+		return nil, err
+	}
+	options, _ := decoded.(map[string]interface{})
+	return options, nil
+}
+
+// decodeOutcomeSet decodes a published outcome set version, which is stored whole as a single JSON document
+func decodeOutcomeSet(b []byte) (impact.OutcomeSet, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	os := impact.OutcomeSet{}
+	if err := dec.Decode(&os); err != nil {
+		return os, err
+	}
+	for i, q := range os.Questions {
+		if q.Options != nil {
+			os.Questions[i].Options = normaliseNumbers(q.Options).(map[string]interface{})
+		}
+	}
+	return os, nil
+}
+
+func normaliseNumbers(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := strconv.Atoi(t.String()); err == nil {
+			return i
+		}
+		f, _ := t.Float64()
+		return f
+	case map[string]interface{}:
+		for k, inner := range t {
+			t[k] = normaliseNumbers(inner)
+		}
+		return t
+	case []interface{}:
+		for i, inner := range t {
+			t[i] = normaliseNumbers(inner)
+		}
+		return t
+	default:
+		return v
+	}
+}
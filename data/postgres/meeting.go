@@ -0,0 +1,270 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	"github.com/lib/pq"
+	uuid "github.com/satori/go.uuid"
+)
+
+const meetingColumns = `id, organisation_id, outcome_set_id, beneficiary, user_id, conducted, created, modified, deleted, status, outcome_set_version`
+
+func scanMeetings(rows *sql.Rows) ([]impact.Meeting, error) {
+	defer rows.Close()
+	meetings := []impact.Meeting{}
+	for rows.Next() {
+		m := impact.Meeting{
+			Answers: []impact.Answer{},
+		}
+		if err := rows.Scan(&m.ID, &m.OrganisationID, &m.OutcomeSetID, &m.Beneficiary, &m.User, &m.Conducted, &m.Created, &m.Modified, &m.Deleted, &m.Status, &m.OutcomeSetVersion); err != nil {
+			return nil, err
+		}
+		meetings = append(meetings, m)
+	}
+	return meetings, rows.Err()
+}
+
+// attachAnswers loads the answers of all the provided meetings in a single query
+func attachAnswers(q queryer, meetings []impact.Meeting) error {
+	if len(meetings) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(meetings))
+	idx := make(map[string]int, len(meetings))
+	for i, m := range meetings {
+		ids = append(ids, m.ID)
+		idx[m.ID] = i
+	}
+
+	rows, err := q.Query(`SELECT meeting_id, question_id, type, answer FROM answers
+		WHERE meeting_id = ANY($1) ORDER BY meeting_id, position`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var meetingID string
+		var value []byte
+		a := impact.Answer{}
+		if err := rows.Scan(&meetingID, &a.QuestionID, &a.Type, &value); err != nil {
+			return err
+		}
+		if a.Answer, err = decodeJSON(value); err != nil {
+			return err
+		}
+		m := &meetings[idx[meetingID]]
+		m.Answers = append(m.Answers, a)
+	}
+	return rows.Err()
+}
+
+func (p *postgres) getMeetings(query string, args ...interface{}) ([]impact.Meeting, error) {
+	rows, err := p.db.Query(`SELECT `+meetingColumns+` FROM meetings WHERE `+query, args...)
+	if err != nil {
+		return nil, err
+	}
+	meetings, err := scanMeetings(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := attachAnswers(p.db, meetings); err != nil {
+		return nil, err
+	}
+	return meetings, nil
+}
+
+func (p *postgres) GetMeeting(id string, u auth.User) (impact.Meeting, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+
+	meetings, err := p.getMeetings(`id = $1 AND organisation_id = $2 AND NOT deleted`, id, userOrg)
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+	if len(meetings) == 0 {
+		return impact.Meeting{}, data.NewNotFoundError("Meeting")
+	}
+	return meetings[0], nil
+}
+
+func (p *postgres) GetAssessmentMeeting(u auth.User) (impact.Meeting, error) {
+	id, ok := u.GetAssessmentScope()
+	if !ok {
+		return impact.Meeting{}, data.ErrNotScoped
+	}
+
+	meetings, err := p.getMeetings(`id = $1 AND NOT deleted`, id)
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+	if len(meetings) == 0 {
+		return impact.Meeting{}, data.NewNotFoundError("Meeting")
+	}
+	return meetings[0], nil
+}
+
+func (p *postgres) GetMeetingsForBeneficiary(beneficiary string, u auth.User) ([]impact.Meeting, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+	return p.getMeetings(`organisation_id = $1 AND beneficiary = $2 AND NOT deleted ORDER BY conducted`, userOrg, beneficiary)
+}
+
+func (p *postgres) GetOSMeetingsForBeneficiary(beneficiary string, outcomeSetID string, u auth.User) ([]impact.Meeting, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+	return p.getMeetings(`organisation_id = $1 AND beneficiary = $2 AND outcome_set_id = $3 AND NOT deleted ORDER BY conducted`,
+		userOrg, beneficiary, outcomeSetID)
+}
+
+func (p *postgres) GetOSMeetingsInTimeRange(start, end time.Time, outcomeSetID string, u auth.User) ([]impact.Meeting, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+	return p.getMeetings(`organisation_id = $1 AND outcome_set_id = $2 AND conducted BETWEEN $3 AND $4 AND NOT deleted ORDER BY conducted`,
+		userOrg, outcomeSetID, start, end)
+}
+
+func (p *postgres) NewMeeting(beneficiaryID, outcomeSetID string, conducted time.Time, u auth.User) (impact.Meeting, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+
+	meeting := impact.Meeting{
+		ID:             uuid.NewV4().String(),
+		OrganisationID: userOrg,
+		OutcomeSetID:   outcomeSetID,
+		Beneficiary:    beneficiaryID,
+		Answers:        []impact.Answer{},
+		Conducted:      conducted,
+		Created:        time.Now(),
+		Modified:       time.Now(),
+		User:           u.UserID(),
+		Status:         impact.IN_PROGRESS,
+	}
+
+	if err := p.withTx(func(tx *sql.Tx) error {
+		// pinned to the outcome set's latest published version, outcome sets which do not exist have no versions.
+		// Outcome sets are published by their first meeting, the row is locked so concurrent first meetings share the version.
+		err := tx.QueryRow(`SELECT version FROM outcome_sets WHERE id = $1 AND organisation_id = $2 FOR UPDATE`,
+			outcomeSetID, userOrg).Scan(&meeting.OutcomeSetVersion)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if err == nil && meeting.OutcomeSetVersion == 0 {
+			os, err := publishOutcomeSet(tx, outcomeSetID, userOrg)
+			if err != nil {
+				return err
+			}
+			meeting.OutcomeSetVersion = os.Version
+		}
+		if _, err := tx.Exec(`INSERT INTO meetings (`+meetingColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+			meeting.ID, meeting.OrganisationID, meeting.OutcomeSetID, meeting.Beneficiary, meeting.User,
+			meeting.Conducted, meeting.Created, meeting.Modified, meeting.Deleted, meeting.Status, meeting.OutcomeSetVersion); err != nil {
+			return err
+		}
+		return ensureBeneficiary(tx, beneficiaryID, userOrg, meeting.Created)
+	}); err != nil {
+		return impact.Meeting{}, err
+	}
+	return meeting, nil
+}
+
+func (p *postgres) NewAnswer(meetingID string, answer impact.Answer, u auth.User) (impact.Meeting, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+
+	value, err := encodeJSON(answer.Answer)
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+
+	if err := p.withTx(func(tx *sql.Tx) error {
+		// locking the meeting row serialises answer positions
+		res, err := tx.Exec(`UPDATE meetings SET modified = $3 WHERE id = $1 AND organisation_id = $2`,
+			meetingID, userOrg, time.Now())
+		if err != nil {
+			return err
+		}
+		if err := expectAffected(res, "Meeting"); err != nil {
+			return err
+		}
+		// a replaced answer keeps its original position
+		_, err = tx.Exec(`INSERT INTO answers (meeting_id, position, question_id, type, answer)
+			VALUES ($1, (SELECT COALESCE(MAX(position) + 1, 0) FROM answers WHERE meeting_id = $1), $2, $3, $4)
+			ON CONFLICT (meeting_id, question_id) DO UPDATE SET type = EXCLUDED.type, answer = EXCLUDED.answer`,
+			meetingID, answer.QuestionID, answer.Type, value)
+		return err
+	}); err != nil {
+		return impact.Meeting{}, err
+	}
+
+	return p.GetMeeting(meetingID, u)
+}
+
+func (p *postgres) EditMeeting(id, beneficiaryID string, conducted time.Time, u auth.User) (impact.Meeting, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+
+	modified := time.Now()
+	if err := p.withTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(`UPDATE meetings SET beneficiary = $3, conducted = $4, modified = $5
+			WHERE id = $1 AND organisation_id = $2`, id, userOrg, beneficiaryID, conducted, modified)
+		if err != nil {
+			return err
+		}
+		if err := expectAffected(res, "Meeting"); err != nil {
+			return err
+		}
+		return ensureBeneficiary(tx, beneficiaryID, userOrg, modified)
+	}); err != nil {
+		return impact.Meeting{}, err
+	}
+	return p.GetMeeting(id, u)
+}
+
+func (p *postgres) DeleteMeeting(id string, u auth.User) error {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return err
+	}
+
+	res, err := p.db.Exec(`UPDATE meetings SET deleted = TRUE, modified = $3
+		WHERE id = $1 AND organisation_id = $2`, id, userOrg, time.Now())
+	if err != nil {
+		return err
+	}
+	return expectAffected(res, "Meeting")
+}
+
+func (p *postgres) SetMeetingStatus(id string, status impact.MeetingStatus, u auth.User) (impact.Meeting, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+
+	res, err := p.db.Exec(`UPDATE meetings SET status = $3, modified = $4
+		WHERE id = $1 AND organisation_id = $2`, id, userOrg, status, time.Now())
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+	if err := expectAffected(res, "Meeting"); err != nil {
+		return impact.Meeting{}, err
+	}
+	return p.GetMeeting(id, u)
+}
@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/impactasaurus/server/log"
+)
+
+// migrations are applied in order and exactly once. The version of a migration is its index plus one.
+// Existing migrations must never be edited, append a new migration instead.
+var migrations = []string{
+	// 1: initial schema
+	`CREATE TABLE organisations (
+		id   TEXT PRIMARY KEY,
+		name TEXT NOT NULL
+	);
+
+	CREATE TABLE outcome_sets (
+		id              TEXT PRIMARY KEY,
+		organisation_id TEXT NOT NULL,
+		name            TEXT NOT NULL,
+		description     TEXT NOT NULL DEFAULT '',
+		deleted         BOOLEAN NOT NULL DEFAULT FALSE
+	);
+	CREATE INDEX outcome_sets_organisation_name ON outcome_sets (organisation_id, name);
+	CREATE UNIQUE INDEX outcome_sets_active_name ON outcome_sets (organisation_id, name) WHERE NOT deleted;
+
+	CREATE TABLE categories (
+		id             TEXT PRIMARY KEY,
+		outcome_set_id TEXT NOT NULL REFERENCES outcome_sets (id),
+		position       INTEGER NOT NULL,
+		name           TEXT NOT NULL,
+		description    TEXT NOT NULL DEFAULT '',
+		aggregation    TEXT NOT NULL
+	);
+	CREATE INDEX categories_outcome_set ON categories (outcome_set_id, position);
+
+	CREATE TABLE questions (
+		id             TEXT PRIMARY KEY,
+		outcome_set_id TEXT NOT NULL REFERENCES outcome_sets (id),
+		position       INTEGER NOT NULL,
+		question       TEXT NOT NULL,
+		description    TEXT NOT NULL DEFAULT '',
+		type           TEXT NOT NULL,
+		options        JSONB,
+		category_id    TEXT REFERENCES categories (id),
+		deleted        BOOLEAN NOT NULL DEFAULT FALSE
+	);
+	CREATE INDEX questions_outcome_set ON questions (outcome_set_id, position);
+
+	CREATE TABLE meetings (
+		id              TEXT PRIMARY KEY,
+		organisation_id TEXT NOT NULL,
+		outcome_set_id  TEXT NOT NULL,
+		beneficiary     TEXT NOT NULL,
+		user_id         TEXT NOT NULL,
+		conducted       TIMESTAMPTZ NOT NULL,
+		created         TIMESTAMPTZ NOT NULL,
+		modified        TIMESTAMPTZ NOT NULL
+	);
+	CREATE INDEX meetings_beneficiary ON meetings (organisation_id, beneficiary, outcome_set_id);
+	CREATE INDEX meetings_conducted ON meetings (organisation_id, outcome_set_id, conducted);
+
+	CREATE TABLE answers (
+		meeting_id  TEXT NOT NULL REFERENCES meetings (id) ON DELETE CASCADE,
+		position    INTEGER NOT NULL,
+		question_id TEXT NOT NULL,
+		type        TEXT NOT NULL,
+		answer      JSONB,
+		PRIMARY KEY (meeting_id, position)
+	);`,
+	// 2: meeting soft deletion and one answer per question
+	`ALTER TABLE meetings ADD COLUMN deleted BOOLEAN NOT NULL DEFAULT FALSE;
+
+	DELETE FROM answers a USING answers later
+		WHERE a.meeting_id = later.meeting_id AND a.question_id = later.question_id AND a.position < later.position;
+	CREATE UNIQUE INDEX answers_question ON answers (meeting_id, question_id);`,
+	// 3: meeting completion lifecycle, existing meetings were all reported on so are marked as completed
+	`ALTER TABLE meetings ADD COLUMN status TEXT NOT NULL DEFAULT 'completed';
+	ALTER TABLE meetings ALTER COLUMN status DROP DEFAULT;`,
+	// 4: beneficiary JWT revocation
+	`CREATE TABLE revoked_assessments (
+		meeting_id TEXT PRIMARY KEY REFERENCES meetings (id),
+		revoked    TIMESTAMPTZ NOT NULL
+	);`,
+	// 5: short codes for remote assessments
+	`CREATE TABLE short_codes (
+		code            TEXT PRIMARY KEY,
+		meeting_id      TEXT NOT NULL REFERENCES meetings (id),
+		beneficiary     TEXT NOT NULL,
+		organisation_id TEXT NOT NULL,
+		expiry          TIMESTAMPTZ NOT NULL
+	);`,
+	// 6: organisation settings
+	`ALTER TABLE organisations
+		ADD COLUMN timezone TEXT NOT NULL DEFAULT '',
+		ADD COLUMN default_outcome_set_id TEXT NOT NULL DEFAULT '',
+		ADD COLUMN locale TEXT NOT NULL DEFAULT '';`,
+	// 7: api keys
+	`CREATE TABLE api_keys (
+		id              TEXT PRIMARY KEY,
+		organisation_id TEXT NOT NULL,
+		name            TEXT NOT NULL,
+		hash            TEXT NOT NULL UNIQUE,
+		scopes          TEXT[] NOT NULL,
+		created         TIMESTAMPTZ NOT NULL,
+		user_id         TEXT NOT NULL,
+		revoked         BOOLEAN NOT NULL DEFAULT FALSE
+	);
+	CREATE INDEX api_keys_organisation ON api_keys (organisation_id);`,
+	// 8: audit log
+	`CREATE TABLE audit_log (
+		id              TEXT PRIMARY KEY,
+		organisation_id TEXT NOT NULL,
+		user_id         TEXT NOT NULL,
+		mutation        TEXT NOT NULL,
+		arguments       JSONB NOT NULL,
+		before          JSONB NOT NULL,
+		after           JSONB NOT NULL,
+		timestamp       TIMESTAMPTZ NOT NULL
+	);
+	CREATE INDEX audit_log_organisation_timestamp ON audit_log (organisation_id, timestamp DESC);`,
+	// 9: beneficiary records, created for the beneficiaries of existing meetings
+	`CREATE TABLE beneficiaries (
+		id              TEXT NOT NULL,
+		organisation_id TEXT NOT NULL,
+		created         TIMESTAMPTZ NOT NULL,
+		tags            TEXT[] NOT NULL,
+		status          TEXT NOT NULL,
+		PRIMARY KEY (organisation_id, id)
+	);
+	INSERT INTO beneficiaries (id, organisation_id, created, tags, status)
+		SELECT beneficiary, organisation_id, MIN(created), '{}', 'active' FROM meetings GROUP BY organisation_id, beneficiary;`,
+	// 10: outcome set versioning, published copies are stored whole so they are unaffected by later edits
+	`ALTER TABLE outcome_sets
+		ADD COLUMN version INTEGER NOT NULL DEFAULT 0,
+		ADD COLUMN published TIMESTAMPTZ;
+	ALTER TABLE meetings ADD COLUMN outcome_set_version INTEGER NOT NULL DEFAULT 0;
+
+	CREATE TABLE outcome_set_versions (
+		outcome_set_id  TEXT NOT NULL REFERENCES outcome_sets (id),
+		version         INTEGER NOT NULL,
+		organisation_id TEXT NOT NULL,
+		outcome_set     JSONB NOT NULL,
+		PRIMARY KEY (outcome_set_id, version)
+	);`,
+	// 11: pin meetings which predate versioning to their outcome set's first version, published by migrationSteps
+	`UPDATE meetings SET outcome_set_version = 1
+		WHERE outcome_set_version = 0 AND EXISTS (
+			SELECT 1 FROM outcome_sets WHERE outcome_sets.id = meetings.outcome_set_id AND outcome_sets.organisation_id = meetings.organisation_id
+		);`,
+}
+
+// migrationSteps hold changes which can not be expressed in SQL, keyed by the version of the migration they belong to.
+// A step runs after its migration's SQL, within the same transaction.
+var migrationSteps = map[int]func(tx *sql.Tx) error{
+	11: publishUnversionedOutcomeSets,
+}
+
+func (p *postgres) migrate() error {
+	if _, err := p.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return err
+	}
+
+	var current int
+	if err := p.db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return err
+	}
+
+	for i := current; i < len(migrations); i++ {
+		version := i + 1
+		if err := p.withTx(func(tx *sql.Tx) error {
+			if _, err := tx.Exec(migrations[i]); err != nil {
+				return err
+			}
+			if step, ok := migrationSteps[version]; ok {
+				if err := step(tx); err != nil {
+					return err
+				}
+			}
+			_, err := tx.Exec(`INSERT INTO schema_migrations (version) VALUES ($1)`, version)
+			return err
+		}); err != nil {
+			return fmt.Errorf("Postgres migration %d failed: %s", version, err.Error())
+		}
+		log.Info("Applied postgres migration", map[string]string{
+			"version": strconv.Itoa(version),
+		})
+	}
+	return nil
+}
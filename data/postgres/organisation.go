@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	"github.com/lib/pq"
+)
+
+func (p *postgres) GetOrganisation(id string, u auth.User) (impact.Organisation, error) {
+	org := impact.Organisation{}
+
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return org, err
+	}
+
+	if id != userOrg {
+		return org, errors.New("User does not have permission to view this organisation")
+	}
+
+	err = p.db.QueryRow(`SELECT id, name, timezone, default_outcome_set_id, locale FROM organisations WHERE id = $1`, id).
+		Scan(&org.ID, &org.Name, &org.Settings.Timezone, &org.Settings.DefaultOutcomeSetID, &org.Settings.Locale)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return org, data.NewNotFoundError("Organisation")
+		}
+		return org, err
+	}
+	return org, nil
+}
+
+func (p *postgres) CreateOrganisation(name string, u auth.User) (impact.Organisation, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Organisation{}, err
+	}
+
+	if _, err := p.db.Exec(`INSERT INTO organisations (id, name) VALUES ($1, $2)`, userOrg, name); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == uniqueViolation {
+			return impact.Organisation{}, data.ErrOrganisationExists
+		}
+		return impact.Organisation{}, err
+	}
+	return impact.Organisation{
+		ID:   userOrg,
+		Name: name,
+	}, nil
+}
+
+func (p *postgres) EditOrganisation(id, name string, settings impact.OrganisationSettings, u auth.User) (impact.Organisation, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Organisation{}, err
+	}
+
+	if id != userOrg {
+		return impact.Organisation{}, errors.New("User does not have permission to edit this organisation")
+	}
+
+	res, err := p.db.Exec(`UPDATE organisations SET name = $2, timezone = $3, default_outcome_set_id = $4, locale = $5
+		WHERE id = $1`, id, name, settings.Timezone, settings.DefaultOutcomeSetID, settings.Locale)
+	if err != nil {
+		return impact.Organisation{}, err
+	}
+	if err := expectAffected(res, "Organisation"); err != nil {
+		return impact.Organisation{}, err
+	}
+	return p.GetOrganisation(id, u)
+}
@@ -0,0 +1,301 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	"github.com/lib/pq"
+	uuid "github.com/satori/go.uuid"
+)
+
+// checkOutcomeSet returns a not found error if the outcome set does not exist within the organisation
+func checkOutcomeSet(q queryer, id, userOrg string) error {
+	var exists bool
+	if err := q.QueryRow(`SELECT EXISTS (SELECT 1 FROM outcome_sets WHERE id = $1 AND organisation_id = $2)`, id, userOrg).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return data.NewNotFoundError("Outcome Set")
+	}
+	return nil
+}
+
+func getQuestions(q queryer, outcomeSetID string) ([]impact.Question, error) {
+	rows, err := q.Query(`SELECT id, question, description, type, options, COALESCE(category_id, ''), deleted
+		FROM questions WHERE outcome_set_id = $1 ORDER BY position`, outcomeSetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	questions := []impact.Question{}
+	for rows.Next() {
+		var q impact.Question
+		var options []byte
+		if err := rows.Scan(&q.ID, &q.Question, &q.Description, &q.Type, &options, &q.CategoryID, &q.Deleted); err != nil {
+			return nil, err
+		}
+		if q.Options, err = decodeOptions(options); err != nil {
+			return nil, err
+		}
+		questions = append(questions, q)
+	}
+	return questions, rows.Err()
+}
+
+func getCategories(q queryer, outcomeSetID string) ([]impact.Category, error) {
+	rows, err := q.Query(`SELECT id, name, description, aggregation
+		FROM categories WHERE outcome_set_id = $1 ORDER BY position`, outcomeSetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []impact.Category{}
+	for rows.Next() {
+		var c impact.Category
+		if err := rows.Scan(&c.ID, &c.Name, &c.Description, &c.Aggregation); err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	return categories, rows.Err()
+}
+
+func getOutcomeSet(q queryer, id, userOrg string) (impact.OutcomeSet, error) {
+	os := impact.OutcomeSet{}
+	var published pq.NullTime
+	err := q.QueryRow(`SELECT id, organisation_id, name, description, deleted, version, published
+		FROM outcome_sets WHERE id = $1 AND organisation_id = $2`, id, userOrg).
+		Scan(&os.ID, &os.OrganisationID, &os.Name, &os.Description, &os.Deleted, &os.Version, &published)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return os, data.NewNotFoundError("Outcome Set")
+		}
+		return os, err
+	}
+	os.Published = published.Time
+	if os.Questions, err = getQuestions(q, id); err != nil {
+		return os, err
+	}
+	if os.Categories, err = getCategories(q, id); err != nil {
+		return os, err
+	}
+	return os, nil
+}
+
+func (p *postgres) GetOutcomeSet(id string, u auth.User) (impact.OutcomeSet, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.OutcomeSet{}, err
+	}
+	return getOutcomeSet(p.db, id, userOrg)
+}
+
+func (p *postgres) GetOutcomeSets(u auth.User) ([]impact.OutcomeSet, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := p.db.Query(`SELECT id FROM outcome_sets WHERE organisation_id = $1 AND NOT deleted ORDER BY name`, userOrg)
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	results := make([]impact.OutcomeSet, 0, len(ids))
+	for _, id := range ids {
+		os, err := getOutcomeSet(p.db, id, userOrg)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, os)
+	}
+	return results, nil
+}
+
+func (p *postgres) NewOutcomeSet(name, description string, u auth.User) (impact.OutcomeSet, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.OutcomeSet{}, err
+	}
+
+	id := uuid.NewV4().String()
+	if err := p.withTx(func(tx *sql.Tx) error {
+		var existing int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM outcome_sets
+			WHERE name = $1 AND organisation_id = $2 AND NOT deleted`, name, userOrg).Scan(&existing); err != nil {
+			return err
+		}
+		if existing != 0 {
+			return errors.New("Name already in use")
+		}
+		_, err := tx.Exec(`INSERT INTO outcome_sets (id, organisation_id, name, description, deleted)
+			VALUES ($1, $2, $3, $4, FALSE)`, id, userOrg, name, description)
+		return err
+	}); err != nil {
+		return impact.OutcomeSet{}, err
+	}
+	return p.GetOutcomeSet(id, u)
+}
+
+func (p *postgres) EditOutcomeSet(id, name, description string, u auth.User) (impact.OutcomeSet, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.OutcomeSet{}, err
+	}
+
+	res, err := p.db.Exec(`UPDATE outcome_sets SET name = $1, description = $2
+		WHERE id = $3 AND organisation_id = $4`, name, description, id, userOrg)
+	if err != nil {
+		return impact.OutcomeSet{}, err
+	}
+	if err := expectAffected(res, "Outcome Set"); err != nil {
+		return impact.OutcomeSet{}, err
+	}
+	return p.GetOutcomeSet(id, u)
+}
+
+func (p *postgres) DeleteOutcomeSet(id string, u auth.User) error {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return err
+	}
+
+	res, err := p.db.Exec(`UPDATE outcome_sets SET deleted = TRUE
+		WHERE id = $1 AND organisation_id = $2`, id, userOrg)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res, "Outcome Set")
+}
+
+func (p *postgres) PublishOutcomeSet(id string, u auth.User) (impact.OutcomeSet, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.OutcomeSet{}, err
+	}
+
+	var os impact.OutcomeSet
+	err = p.withTx(func(tx *sql.Tx) error {
+		os, err = publishOutcomeSet(tx, id, userOrg)
+		return err
+	})
+	return os, err
+}
+
+// publishOutcomeSet stores a copy of the outcome set as its next version, returning the published outcome set
+func publishOutcomeSet(tx *sql.Tx, id, userOrg string) (impact.OutcomeSet, error) {
+	res, err := tx.Exec(`UPDATE outcome_sets SET version = version + 1, published = $1
+		WHERE id = $2 AND organisation_id = $3`, time.Now(), id, userOrg)
+	if err != nil {
+		return impact.OutcomeSet{}, err
+	}
+	if err := expectAffected(res, "Outcome Set"); err != nil {
+		return impact.OutcomeSet{}, err
+	}
+	os, err := getOutcomeSet(tx, id, userOrg)
+	if err != nil {
+		return os, err
+	}
+	doc, err := encodeJSON(os)
+	if err != nil {
+		return os, err
+	}
+	_, err = tx.Exec(`INSERT INTO outcome_set_versions (outcome_set_id, version, organisation_id, outcome_set)
+		VALUES ($1, $2, $3, $4)`, id, os.Version, userOrg, doc)
+	return os, err
+}
+
+// publishUnversionedOutcomeSets publishes the outcome sets which predate versioning as their first version
+func publishUnversionedOutcomeSets(tx *sql.Tx) error {
+	rows, err := tx.Query(`SELECT id, organisation_id FROM outcome_sets WHERE version = 0`)
+	if err != nil {
+		return err
+	}
+	// the rows are read in full first, as a transaction can not run statements while a query's rows are open
+	type outcomeSet struct {
+		id, userOrg string
+	}
+	unpublished := []outcomeSet{}
+	for rows.Next() {
+		os := outcomeSet{}
+		if err := rows.Scan(&os.id, &os.userOrg); err != nil {
+			rows.Close()
+			return err
+		}
+		unpublished = append(unpublished, os)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, os := range unpublished {
+		if _, err := publishOutcomeSet(tx, os.id, os.userOrg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *postgres) GetOutcomeSetVersion(id string, version int, u auth.User) (impact.OutcomeSet, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.OutcomeSet{}, err
+	}
+
+	var doc []byte
+	if err := p.db.QueryRow(`SELECT outcome_set FROM outcome_set_versions
+		WHERE outcome_set_id = $1 AND version = $2 AND organisation_id = $3`, id, version, userOrg).Scan(&doc); err != nil {
+		if err == sql.ErrNoRows {
+			return impact.OutcomeSet{}, data.NewNotFoundError("Outcome Set Version")
+		}
+		return impact.OutcomeSet{}, err
+	}
+	return decodeOutcomeSet(doc)
+}
+
+func (p *postgres) GetOutcomeSetVersions(id string, u auth.User) ([]impact.OutcomeSet, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := p.db.Query(`SELECT outcome_set FROM outcome_set_versions
+		WHERE outcome_set_id = $1 AND organisation_id = $2 ORDER BY version`, id, userOrg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []impact.OutcomeSet{}
+	for rows.Next() {
+		var doc []byte
+		if err := rows.Scan(&doc); err != nil {
+			return nil, err
+		}
+		os, err := decodeOutcomeSet(doc)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, os)
+	}
+	return results, rows.Err()
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/impactasaurus/server/data"
+	// registers the postgres driver with database/sql
+	_ "github.com/lib/pq"
+)
+
+type postgres struct {
+	db *sql.DB
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Tx
+type queryer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// New returns a data.Base backed by the PostgreSQL database at the provided connection URL.
+// Any outstanding schema migrations are applied before returning.
+func New(url string) (data.Base, error) {
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		return nil, err
+	}
+
+	p := &postgres{
+		db: db,
+	}
+	if err := p.migrate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *postgres) withTx(fn func(tx *sql.Tx) error) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// expectAffected returns a not found error if the update or delete did not touch any rows
+func expectAffected(res sql.Result, thing string) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return data.NewNotFoundError(thing)
+	}
+	return nil
+}
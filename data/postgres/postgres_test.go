@@ -0,0 +1,42 @@
+// +build integration
+
+package postgres_test
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/impactasaurus/server/data"
+	"github.com/impactasaurus/server/data/conformance"
+	"github.com/impactasaurus/server/data/postgres"
+)
+
+func TestConformance(t *testing.T) {
+	url := os.Getenv("POSTGRES_URL")
+	admin, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer admin.Close()
+
+	conformance.Run(t, func(t *testing.T) data.Base {
+		// a fresh schema per test case keeps the cases independent
+		schema := fmt.Sprintf("conformance%d", time.Now().UnixNano())
+		if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+			t.Fatal(err)
+		}
+		separator := "?"
+		if strings.Contains(url, "?") {
+			separator = "&"
+		}
+		db, err := postgres.New(url + separator + "search_path=" + schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return db
+	})
+}
@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"database/sql"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	uuid "github.com/satori/go.uuid"
+)
+
+// questionScope restricts a statement to a question ($1) within an outcome set ($2) owned by the organisation ($3)
+const questionScope = `id = $1 AND outcome_set_id = $2
+	AND EXISTS (SELECT 1 FROM outcome_sets WHERE id = $2 AND organisation_id = $3)`
+
+func (p *postgres) GetQuestion(outcomeSetID string, questionID string, u auth.User) (impact.Question, error) {
+	os, err := p.GetOutcomeSet(outcomeSetID, u)
+	if err != nil {
+		return impact.Question{}, err
+	}
+
+	for _, q := range os.Questions {
+		if q.ID == questionID {
+			return q, nil
+		}
+	}
+	return impact.Question{}, data.NewNotFoundError("Question")
+}
+
+func (p *postgres) NewQuestion(outcomeSetID, question, description string, questionType impact.QuestionType, options map[string]interface{}, u auth.User) (impact.Question, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Question{}, err
+	}
+
+	encodedOptions, err := encodeOptions(options)
+	if err != nil {
+		return impact.Question{}, err
+	}
+
+	id := uuid.NewV4().String()
+	if err := p.withTx(func(tx *sql.Tx) error {
+		if err := checkOutcomeSet(tx, outcomeSetID, userOrg); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`INSERT INTO questions (id, outcome_set_id, position, question, description, type, options, deleted)
+			VALUES ($1, $2, (SELECT COALESCE(MAX(position) + 1, 0) FROM questions WHERE outcome_set_id = $2), $3, $4, $5, $6, FALSE)`,
+			id, outcomeSetID, question, description, questionType, encodedOptions)
+		return err
+	}); err != nil {
+		return impact.Question{}, err
+	}
+
+	return p.GetQuestion(outcomeSetID, id, u)
+}
+
+func (p *postgres) DeleteQuestion(outcomeSetID, questionID string, u auth.User) error {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return err
+	}
+
+	res, err := p.db.Exec(`UPDATE questions SET deleted = TRUE WHERE `+questionScope, questionID, outcomeSetID, userOrg)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res, "Question")
+}
+
+func (p *postgres) EditQuestion(outcomeSetID, questionID, question, description string, questionType impact.QuestionType, options map[string]interface{}, u auth.User) (impact.Question, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Question{}, err
+	}
+
+	encodedOptions, err := encodeOptions(options)
+	if err != nil {
+		return impact.Question{}, err
+	}
+
+	newQ := impact.Question{
+		ID:          questionID,
+		Question:    question,
+		Description: description,
+		Type:        questionType,
+		Options:     options,
+		Deleted:     false,
+	}
+
+	res, err := p.db.Exec(`UPDATE questions
+		SET question = $4, description = $5, type = $6, options = $7, category_id = NULL, deleted = FALSE
+		WHERE `+questionScope, questionID, outcomeSetID, userOrg, question, description, questionType, encodedOptions)
+	if err != nil {
+		return impact.Question{}, err
+	}
+	if err := expectAffected(res, "Question"); err != nil {
+		return impact.Question{}, err
+	}
+	return newQ, nil
+}
+
+func (p *postgres) MoveQuestion(outcomeSetID, questionID string, newIndex uint, u auth.User) error {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return err
+	}
+
+	return p.withTx(func(tx *sql.Tx) error {
+		if err := checkOutcomeSet(tx, outcomeSetID, userOrg); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(`SELECT id FROM questions WHERE outcome_set_id = $1 ORDER BY position FOR UPDATE`, outcomeSetID)
+		if err != nil {
+			return err
+		}
+		nonMovingQuestions := []string{}
+		found := false
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			if id == questionID {
+				found = true
+				continue
+			}
+			nonMovingQuestions = append(nonMovingQuestions, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if !found {
+			return data.NewNotFoundError("Question")
+		}
+
+		if newIndex > uint(len(nonMovingQuestions)) {
+			newIndex = uint(len(nonMovingQuestions))
+		}
+		newOrder := make([]string, 0, len(nonMovingQuestions)+1)
+		newOrder = append(newOrder, nonMovingQuestions[:newIndex]...)
+		newOrder = append(newOrder, questionID)
+		newOrder = append(newOrder, nonMovingQuestions[newIndex:]...)
+
+		for position, id := range newOrder {
+			if _, err := tx.Exec(`UPDATE questions SET position = $1 WHERE id = $2`, position, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (p *postgres) setQuestionCategory(outcomeSetID, questionID string, categoryID *string, u auth.User) (impact.Question, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.Question{}, err
+	}
+
+	res, err := p.db.Exec(`UPDATE questions SET category_id = $4 WHERE `+questionScope, questionID, outcomeSetID, userOrg, categoryID)
+	if err != nil {
+		return impact.Question{}, err
+	}
+	if err := expectAffected(res, "Question"); err != nil {
+		return impact.Question{}, err
+	}
+	return p.GetQuestion(outcomeSetID, questionID, u)
+}
+
+func (p *postgres) SetCategory(outcomeSetID, questionID, categoryID string, u auth.User) (impact.Question, error) {
+	_, err := p.GetCategory(outcomeSetID, categoryID, u)
+	if err != nil {
+		return impact.Question{}, data.NewNotFoundError("Category")
+	}
+	return p.setQuestionCategory(outcomeSetID, questionID, &categoryID, u)
+}
+
+func (p *postgres) RemoveCategory(outcomeSetID, questionID string, u auth.User) (impact.Question, error) {
+	return p.setQuestionCategory(outcomeSetID, questionID, nil, u)
+}
@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/impactasaurus/server/auth"
+)
+
+func (p *postgres) RevokeAssessment(meetingID string, u auth.User) error {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return err
+	}
+
+	// revoking an already revoked assessment keeps the original time, but still counts as an affected row
+	res, err := p.db.Exec(`INSERT INTO revoked_assessments (meeting_id, revoked)
+		SELECT id, $3 FROM meetings WHERE id = $1 AND organisation_id = $2
+		ON CONFLICT (meeting_id) DO UPDATE SET revoked = revoked_assessments.revoked`, meetingID, userOrg, time.Now())
+	if err != nil {
+		return err
+	}
+	return expectAffected(res, "Meeting")
+}
+
+func (p *postgres) IsAssessmentRevoked(meetingID string) (bool, error) {
+	var revoked bool
+	err := p.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM revoked_assessments WHERE meeting_id = $1)`, meetingID).Scan(&revoked)
+	return revoked, err
+}
@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+	"github.com/lib/pq"
+)
+
+// uniqueViolation is the postgres error code raised when a unique constraint is violated
+const uniqueViolation = "23505"
+
+func (p *postgres) NewShortCode(code, meetingID, beneficiaryID string, expiry time.Time, u auth.User) (impact.ShortCode, error) {
+	userOrg, err := u.Organisation()
+	if err != nil {
+		return impact.ShortCode{}, err
+	}
+
+	_, err = p.db.Exec(`INSERT INTO short_codes (code, meeting_id, beneficiary, organisation_id, expiry)
+		VALUES ($1, $2, $3, $4, $5)`, code, meetingID, beneficiaryID, userOrg, expiry)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == uniqueViolation {
+			return impact.ShortCode{}, data.ErrShortCodeInUse
+		}
+		return impact.ShortCode{}, err
+	}
+	return impact.ShortCode{
+		Code:           code,
+		MeetingID:      meetingID,
+		Beneficiary:    beneficiaryID,
+		OrganisationID: userOrg,
+		Expiry:         expiry,
+	}, nil
+}
+
+func (p *postgres) GetShortCode(code string) (impact.ShortCode, error) {
+	sc := impact.ShortCode{}
+	err := p.db.QueryRow(`SELECT code, meeting_id, beneficiary, organisation_id, expiry
+		FROM short_codes WHERE code = $1`, code).
+		Scan(&sc.Code, &sc.MeetingID, &sc.Beneficiary, &sc.OrganisationID, &sc.Expiry)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return sc, data.NewNotFoundError("Short code")
+		}
+		return sc, err
+	}
+	return sc, nil
+}
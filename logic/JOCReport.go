@@ -13,6 +13,7 @@ import (
 
 type JOCDatabase interface {
 	GetOutcomeSet(id string, u auth.User) (impact.OutcomeSet, error)
+	GetOutcomeSetVersion(id string, version int, u auth.User) (impact.OutcomeSet, error)
 	GetOSMeetingsForBeneficiary(beneficiary string, outcomeSetID string, u auth.User) ([]impact.Meeting, error)
 	GetOSMeetingsInTimeRange(start, end time.Time, outcomeSetID string, u auth.User) ([]impact.Meeting, error)
 }
@@ -31,12 +32,29 @@ type jocReporter struct {
 	excludedCategoryIDs []string
 	excludedQuestionIDs []string
 	excludedBenIDs      []string
+	incompleteMeetings  map[string]bool
 }
 
 func (j *jocReporter) addGlobalWarning(warning string) {
 	j.globalWarnings = append(j.globalWarnings, warning)
 }
 
+// completedMeetings filters out meetings which have not been completed, warning once for each excluded meeting
+func (j *jocReporter) completedMeetings(meetings []impact.Meeting) []impact.Meeting {
+	out := make([]impact.Meeting, 0, len(meetings))
+	for _, meeting := range meetings {
+		if meeting.IsComplete() {
+			out = append(out, meeting)
+			continue
+		}
+		if !j.incompleteMeetings[meeting.ID] {
+			j.incompleteMeetings[meeting.ID] = true
+			j.addGlobalWarning(fmt.Sprintf("Meeting %s with beneficiary %s was not included as it has not been completed", meeting.ID, meeting.Beneficiary))
+		}
+	}
+	return out
+}
+
 func (j *jocReporter) getLastMeetingForEachBen(meetingsInRange []impact.Meeting) map[string]impact.Meeting {
 	lastMeetings := map[string]impact.Meeting{}
 	for _, meeting := range meetingsInRange {
@@ -53,6 +71,13 @@ func (j *jocReporter) getLastMeetingForEachBen(meetingsInRange []impact.Meeting)
 	return lastMeetings
 }
 
+func versionName(version int) string {
+	if version == 0 {
+		return "unpublished"
+	}
+	return fmt.Sprintf("version %d", version)
+}
+
 func (j *jocReporter) getFirstAndLastMeetings(lastMeetings map[string]impact.Meeting) map[string]firstAndLastMeetings {
 	firstAndLast := map[string]firstAndLastMeetings{}
 	for ben, lastMeeting := range lastMeetings {
@@ -68,6 +93,7 @@ func (j *jocReporter) getFirstAndLastMeetings(lastMeetings map[string]impact.Mee
 			})
 			continue
 		}
+		benMeetings = j.completedMeetings(benMeetings)
 		if len(benMeetings) == 0 {
 			j.addGlobalWarning(fmt.Sprintf("Could not include beneficiary %s as we could not find their first meeting. Please contact support.", ben))
 			log.Error(errors.New("No benificary meetings found"), map[string]string{
@@ -92,6 +118,10 @@ func (j *jocReporter) getFirstAndLastMeetings(lastMeetings map[string]impact.Mee
 			j.excludedBenIDs = append(j.excludedBenIDs, ben)
 			continue
 		}
+		if firstMeeting.OutcomeSetVersion != lastMeeting.OutcomeSetVersion {
+			j.addGlobalWarning(fmt.Sprintf("Beneficiary %s's first and last meetings were conducted against different versions of the question set (%s and %s), so their answers may not be comparable",
+				ben, versionName(firstMeeting.OutcomeSetVersion), versionName(lastMeeting.OutcomeSetVersion)))
+		}
 		firstAndLast[ben] = firstAndLastMeetings{
 			first: firstMeeting,
 			last:  lastMeeting,
@@ -176,6 +206,11 @@ func (j *jocReporter) getQuestionAggregations(firstAndLast map[string]firstAndLa
 		Delta: make([]impact.QBenAgg, 0, len(activeQs)),
 	}
 	for _, q := range activeQs {
+		// answers such as free text and choices have no numeric value to compare between meetings
+		if !q.IsNumeric() {
+			j.excludedQuestionIDs = append(j.excludedQuestionIDs, q.ID)
+			continue
+		}
 		benAggregator := newBenAgg(q.ID, len(firstAndLast))
 		for ben, fl := range firstAndLast {
 			firstAnswer := fl.first.GetAnswer(q.ID)
@@ -188,8 +223,8 @@ func (j *jocReporter) getQuestionAggregations(firstAndLast map[string]firstAndLa
 				benAggregator.addBenificaryWarning(fmt.Sprintf("Beneficiary %s not included as the answers were not of an expected format", ben))
 				continue
 			}
-			fV, fE := firstAnswer.ToFloat()
-			lV, lE := lastAnswer.ToFloat()
+			fV, fE := ScoreAnswer(q, *firstAnswer)
+			lV, lE := ScoreAnswer(q, *lastAnswer)
 			if fE != nil || lE != nil {
 				benAggregator.addBenificaryWarning(fmt.Sprintf("Beneficiary %s not included as the answers were not of an expected format", ben))
 				continue
@@ -249,6 +284,12 @@ func GetJOCServiceReport(start, end time.Time, questionSetID string, db JOCDatab
 	if err != nil {
 		return nil, err
 	}
+	// unpublished edits are not reported on until they are published
+	if os.Version > 0 {
+		if os, err = db.GetOutcomeSetVersion(questionSetID, os.Version, u); err != nil {
+			return nil, err
+		}
+	}
 	j := jocReporter{
 		questionSetID:       questionSetID,
 		db:                  db,
@@ -258,6 +299,7 @@ func GetJOCServiceReport(start, end time.Time, questionSetID string, db JOCDatab
 		excludedCategoryIDs: []string{},
 		excludedQuestionIDs: []string{},
 		excludedBenIDs:      []string{},
+		incompleteMeetings:  map[string]bool{},
 	}
 
 	meetingsInRange, err := db.GetOSMeetingsInTimeRange(start, end, questionSetID, u)
@@ -267,6 +309,10 @@ func GetJOCServiceReport(start, end time.Time, questionSetID string, db JOCDatab
 	if len(meetingsInRange) == 0 {
 		return nil, errors.New("No meetings found for the question set within the given date range")
 	}
+	meetingsInRange = j.completedMeetings(meetingsInRange)
+	if len(meetingsInRange) == 0 {
+		return nil, errors.New("No completed meetings found for the question set within the given date range")
+	}
 
 	lastMeetings := j.getLastMeetingForEachBen(meetingsInRange)
 	firstAndLast := j.getFirstAndLastMeetings(lastMeetings)
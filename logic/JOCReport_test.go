@@ -31,6 +31,7 @@ func getDefaultMeetings(start, end time.Time, questionSetID string) map[string]i
 			Beneficiary:  "B1",
 			OutcomeSetID: questionSetID,
 			Conducted:    start.Add(-time.Hour * 84),
+			Status:       impact.COMPLETED,
 			Answers: []impact.Answer{{
 				QuestionID: "Q1",
 				Type:       impact.INT,
@@ -54,6 +55,7 @@ func getDefaultMeetings(start, end time.Time, questionSetID string) map[string]i
 			Beneficiary:  "B1",
 			OutcomeSetID: questionSetID,
 			Conducted:    end,
+			Status:       impact.COMPLETED,
 			Answers: []impact.Answer{{
 				QuestionID: "Q1",
 				Type:       impact.INT,
@@ -77,6 +79,7 @@ func getDefaultMeetings(start, end time.Time, questionSetID string) map[string]i
 			Beneficiary:  "B2",
 			OutcomeSetID: questionSetID,
 			Conducted:    start.Add(time.Hour),
+			Status:       impact.COMPLETED,
 			Answers: []impact.Answer{{
 				QuestionID: "Q1",
 				Type:       impact.INT,
@@ -100,6 +103,7 @@ func getDefaultMeetings(start, end time.Time, questionSetID string) map[string]i
 			Beneficiary:  "B2",
 			OutcomeSetID: questionSetID,
 			Conducted:    end,
+			Status:       impact.COMPLETED,
 			Answers: []impact.Answer{{
 				QuestionID: "Q1",
 				Type:       impact.INT,
@@ -123,6 +127,7 @@ func getDefaultMeetings(start, end time.Time, questionSetID string) map[string]i
 			Beneficiary:  "B3",
 			OutcomeSetID: questionSetID,
 			Conducted:    start.Add(-time.Hour),
+			Status:       impact.COMPLETED,
 			Answers: []impact.Answer{{
 				QuestionID: "Q1",
 				Type:       impact.INT,
@@ -146,6 +151,7 @@ func getDefaultMeetings(start, end time.Time, questionSetID string) map[string]i
 			Beneficiary:  "B3",
 			OutcomeSetID: questionSetID,
 			Conducted:    start.Add(time.Hour),
+			Status:       impact.COMPLETED,
 			Answers: []impact.Answer{{
 				QuestionID: "Q1",
 				Type:       impact.INT,
@@ -169,6 +175,7 @@ func getDefaultMeetings(start, end time.Time, questionSetID string) map[string]i
 			Beneficiary:  "B3",
 			OutcomeSetID: questionSetID,
 			Conducted:    end,
+			Status:       impact.COMPLETED,
 			Answers: []impact.Answer{{
 				QuestionID: "Q1",
 				Type:       impact.INT,
@@ -542,3 +549,149 @@ func TestBenMeetingFailure(t *testing.T) {
 		assert.Regexp(t, regexp.MustCompile("Could not include beneficiary B2 due to an system error.*"), result.Warnings[0])
 	})
 }
+
+func TestIncompleteMeetingsExcluded(t *testing.T) {
+	end := time.Unix(10000, 0)
+	start := end.Add(-time.Hour * 24)
+	os := getDefaultOutcomeSet(questionSetID)
+	meetings := getDefaultMeetings(start, end, questionSetID)
+
+	b1m2 := meetings["B1M2"]
+	b1m2.Status = impact.IN_PROGRESS
+
+	inRangeMeetings := []impact.Meeting{b1m2, meetings["B2M1"], meetings["B2M2"]}
+	b2Meetings := []impact.Meeting{meetings["B2M1"], meetings["B2M2"]}
+
+	setupWrapper(t, func(mockUser *mock.MockUser, mockDB *mock.MockBase) {
+		mockDB.EXPECT().GetOutcomeSet(questionSetID, mockUser).Return(os, nil)
+		mockDB.EXPECT().GetOSMeetingsInTimeRange(start, end, questionSetID, mockUser).Return(inRangeMeetings, nil)
+		mockDB.EXPECT().GetOSMeetingsForBeneficiary("B2", questionSetID, mockUser).Return(b2Meetings, nil)
+
+		result, err := logic.GetJOCServiceReport(start, end, questionSetID, mockDB, mockUser)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"B2"}, result.BeneficiaryIDs)
+		if assert.Len(t, result.Warnings, 1) {
+			assert.Regexp(t, regexp.MustCompile("Meeting B1M2 .* not included as it has not been completed"), result.Warnings[0])
+		}
+	})
+}
+
+func TestOnlyIncompleteMeetingsInRange(t *testing.T) {
+	meetings := getDefaultMeetings(time.Now().Add(-time.Hour*10), time.Now(), "qid")
+	b1m1 := meetings["B1M1"]
+	b1m1.Status = impact.ABANDONED
+
+	setupWrapper(t, func(mockUser *mock.MockUser, mockDB *mock.MockBase) {
+		mockDB.EXPECT().GetOutcomeSet("q", mockUser).Return(impact.OutcomeSet{}, nil)
+		mockDB.EXPECT().GetOSMeetingsInTimeRange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]impact.Meeting{b1m1}, nil)
+		result, err := logic.GetJOCServiceReport(time.Now(), time.Now(), "q", mockDB, mockUser)
+		assert.Nil(t, result)
+		assert.Error(t, err)
+	})
+}
+
+func TestNonNumericQuestionsExcluded(t *testing.T) {
+	end := time.Unix(10000, 0)
+	start := end.Add(-time.Hour * 24)
+	os := getDefaultOutcomeSet(questionSetID)
+	os.Questions = append(os.Questions, impact.Question{
+		ID:         "Q5",
+		Type:       impact.FREE_TEXT,
+		CategoryID: "C1",
+	})
+	meetings := getDefaultMeetings(start, end, questionSetID)
+
+	b1m1 := meetings["B1M1"]
+	b1m2 := meetings["B1M2"]
+	for _, m := range []*impact.Meeting{&b1m1, &b1m2} {
+		m.Answers = append(m.Answers, impact.Answer{
+			QuestionID: "Q5",
+			Type:       impact.STRING,
+			Answer:     "Some notes",
+		})
+	}
+
+	inRangeMeetings := []impact.Meeting{b1m2}
+	b1Meetings := []impact.Meeting{b1m1, b1m2}
+
+	setupWrapper(t, func(mockUser *mock.MockUser, mockDB *mock.MockBase) {
+		mockDB.EXPECT().GetOutcomeSet(questionSetID, mockUser).Return(os, nil)
+		mockDB.EXPECT().GetOSMeetingsInTimeRange(start, end, questionSetID, mockUser).Return(inRangeMeetings, nil)
+		mockDB.EXPECT().GetOSMeetingsForBeneficiary("B1", questionSetID, mockUser).Return(b1Meetings, nil)
+
+		result, err := logic.GetJOCServiceReport(start, end, questionSetID, mockDB, mockUser)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Q5"}, result.Excluded.QuestionIDs)
+		assert.Len(t, result.Warnings, 0)
+		assert.Len(t, result.QuestionAggregates.First, 4)
+		for _, cba := range result.CategoryAggregates.Delta {
+			assert.Len(t, cba.Warnings, 0)
+			if cba.CategoryID == "C1" {
+				assert.Equal(t, float32(3.5), cba.Value)
+			}
+		}
+	})
+}
+
+func TestReverseScoredQuestions(t *testing.T) {
+	end := time.Unix(10000, 0)
+	start := end.Add(-time.Hour * 24)
+	os := getDefaultOutcomeSet(questionSetID)
+	for i := range os.Questions {
+		os.Questions[i].Options = map[string]interface{}{"minValue": 1, "maxValue": 10}
+	}
+	// Q2 rising from 5 to 8 is scored as a fall from 6 to 3
+	os.Questions[1].Options["reverseScored"] = true
+	meetings := getDefaultMeetings(start, end, questionSetID)
+
+	inRangeMeetings := []impact.Meeting{meetings["B1M2"]}
+	b1Meetings := []impact.Meeting{meetings["B1M1"], meetings["B1M2"]}
+
+	setupWrapper(t, func(mockUser *mock.MockUser, mockDB *mock.MockBase) {
+		mockDB.EXPECT().GetOutcomeSet(questionSetID, mockUser).Return(os, nil)
+		mockDB.EXPECT().GetOSMeetingsInTimeRange(start, end, questionSetID, mockUser).Return(inRangeMeetings, nil)
+		mockDB.EXPECT().GetOSMeetingsForBeneficiary("B1", questionSetID, mockUser).Return(b1Meetings, nil)
+
+		result, err := logic.GetJOCServiceReport(start, end, questionSetID, mockDB, mockUser)
+		assert.NoError(t, err)
+		for _, qba := range result.QuestionAggregates.Delta {
+			if qba.QuestionID == "Q2" {
+				assert.Equal(t, float32(-3), qba.Value)
+			}
+		}
+		for _, cba := range result.CategoryAggregates.Delta {
+			if cba.CategoryID == "C1" {
+				assert.Equal(t, float32(0.5), cba.Value)
+			}
+		}
+	})
+}
+
+func TestDifferentOutcomeSetVersions(t *testing.T) {
+	end := time.Unix(10000, 0)
+	start := end.Add(-time.Hour * 24)
+	draft := getDefaultOutcomeSet(questionSetID)
+	draft.Version = 2
+	published := getDefaultOutcomeSet(questionSetID)
+	published.Version = 2
+	meetings := getDefaultMeetings(start, end, questionSetID)
+
+	b1m1 := meetings["B1M1"]
+	b1m1.OutcomeSetVersion = 1
+	b1m2 := meetings["B1M2"]
+	b1m2.OutcomeSetVersion = 2
+
+	setupWrapper(t, func(mockUser *mock.MockUser, mockDB *mock.MockBase) {
+		mockDB.EXPECT().GetOutcomeSet(questionSetID, mockUser).Return(draft, nil)
+		mockDB.EXPECT().GetOutcomeSetVersion(questionSetID, 2, mockUser).Return(published, nil)
+		mockDB.EXPECT().GetOSMeetingsInTimeRange(start, end, questionSetID, mockUser).Return([]impact.Meeting{b1m2}, nil)
+		mockDB.EXPECT().GetOSMeetingsForBeneficiary("B1", questionSetID, mockUser).Return([]impact.Meeting{b1m1, b1m2}, nil)
+
+		result, err := logic.GetJOCServiceReport(start, end, questionSetID, mockDB, mockUser)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"B1"}, result.BeneficiaryIDs)
+		if assert.Len(t, result.Warnings, 1) {
+			assert.Regexp(t, regexp.MustCompile("Beneficiary B1's .* different versions .* \\(version 1 and version 2\\)"), result.Warnings[0])
+		}
+	})
+}
@@ -30,7 +30,24 @@ func aggregate(in []float32, aggregation impact.Aggregation) (float32, error) {
 	}
 }
 
+// ScoreAnswer returns the numeric value of the answer to the question.
+// Answers to reverse scored Likert questions are flipped within the scale, so a higher score is always better.
+func ScoreAnswer(q impact.Question, a impact.Answer) (float32, error) {
+	v, err := a.ToFloat()
+	if err != nil || !q.IsReverseScored() {
+		return v, err
+	}
+	min, _ := optionInt(q, "minValue")
+	max, ok := optionInt(q, "maxValue")
+	if !ok {
+		return 0, fmt.Errorf("Likert question %s is missing a maxValue", q.ID)
+	}
+	return float32(min+max) - v, nil
+}
+
 // GetCategoryAggregate aggregates multiple answers into a single value.
+// Only numeric answers are aggregated, answers to other question types within the category are skipped.
+// Reverse scored answers are flipped before aggregating, see ScoreAnswer.
 // If the returned CategoryAggregate is nil, there were no answers available for the category.
 func GetCategoryAggregate(m impact.Meeting, categoryID string, os impact.OutcomeSet) (*impact.CategoryAggregate, error) {
 	c := os.GetCategory(categoryID)
@@ -40,8 +57,9 @@ func GetCategoryAggregate(m impact.Meeting, categoryID string, os impact.Outcome
 	vals := make([]float32, 0, len(m.Answers))
 	for _, a := range m.Answers {
 		q := os.GetQuestion(a.QuestionID)
-		if q.CategoryID == categoryID {
-			f, err := a.ToFloat()
+		// answers to questions missing from the outcome set cannot be categorised
+		if q != nil && q.CategoryID == categoryID && a.IsNumeric() {
+			f, err := ScoreAnswer(*q, a)
 			if err != nil {
 				return nil, err
 			}
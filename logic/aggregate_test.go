@@ -0,0 +1,119 @@
+package logic_test
+
+import (
+	"testing"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/logic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategoryAggregateIgnoresUnknownQuestions(t *testing.T) {
+	os := impact.OutcomeSet{
+		Questions: []impact.Question{{
+			ID:         "Q1",
+			CategoryID: "C1",
+		}},
+		Categories: []impact.Category{{
+			ID:          "C1",
+			Aggregation: impact.SUM,
+		}},
+	}
+	m := impact.Meeting{
+		Answers: []impact.Answer{{
+			QuestionID: "unknown",
+			Type:       impact.INT,
+			Answer:     3,
+		}, {
+			QuestionID: "Q1",
+			Type:       impact.INT,
+			Answer:     2,
+		}},
+	}
+
+	ag, err := logic.GetCategoryAggregate(m, "C1", os)
+	assert.NoError(t, err)
+	if assert.NotNil(t, ag) {
+		assert.Equal(t, float32(2), ag.Value)
+	}
+}
+
+func TestCategoryAggregateSkipsNonNumericAnswers(t *testing.T) {
+	os := impact.OutcomeSet{
+		Questions: []impact.Question{{
+			ID:         "Q1",
+			Type:       impact.NUMERIC,
+			CategoryID: "C1",
+		}, {
+			ID:         "Q2",
+			Type:       impact.FREE_TEXT,
+			CategoryID: "C1",
+		}, {
+			ID:         "Q3",
+			Type:       impact.MULTI_CHOICE,
+			CategoryID: "C1",
+		}},
+		Categories: []impact.Category{{
+			ID:          "C1",
+			Aggregation: impact.MEAN,
+		}},
+	}
+	m := impact.Meeting{
+		Answers: []impact.Answer{{
+			QuestionID: "Q1",
+			Type:       impact.FLOAT,
+			Answer:     2.5,
+		}, {
+			QuestionID: "Q2",
+			Type:       impact.STRING,
+			Answer:     "Feeling better",
+		}, {
+			QuestionID: "Q3",
+			Type:       impact.STRING_LIST,
+			Answer:     []string{"a", "b"},
+		}},
+	}
+
+	ag, err := logic.GetCategoryAggregate(m, "C1", os)
+	assert.NoError(t, err)
+	if assert.NotNil(t, ag) {
+		assert.Equal(t, float32(2.5), ag.Value)
+	}
+}
+
+func TestCategoryAggregateReverseScored(t *testing.T) {
+	os := impact.OutcomeSet{
+		Questions: []impact.Question{{
+			ID:         "Q1",
+			Type:       impact.LIKERT,
+			CategoryID: "C1",
+			Options:    map[string]interface{}{"minValue": 1, "maxValue": 5},
+		}, {
+			ID:         "Q2",
+			Type:       impact.LIKERT,
+			CategoryID: "C1",
+			Options:    map[string]interface{}{"minValue": 1, "maxValue": 5, "reverseScored": true},
+		}},
+		Categories: []impact.Category{{
+			ID:          "C1",
+			Aggregation: impact.SUM,
+		}},
+	}
+	m := impact.Meeting{
+		Answers: []impact.Answer{{
+			QuestionID: "Q1",
+			Type:       impact.INT,
+			Answer:     4,
+		}, {
+			QuestionID: "Q2",
+			Type:       impact.INT,
+			Answer:     2,
+		}},
+	}
+
+	ag, err := logic.GetCategoryAggregate(m, "C1", os)
+	assert.NoError(t, err)
+	if assert.NotNil(t, ag) {
+		assert.Equal(t, float32(8), ag.Value)
+	}
+}
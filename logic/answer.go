@@ -0,0 +1,171 @@
+package logic
+
+import (
+	"fmt"
+
+	impact "github.com/impactasaurus/server"
+)
+
+// AnswerErrorCode identifies why an answer was rejected, allowing clients to react without parsing messages
+type AnswerErrorCode string
+
+const (
+	QuestionNotFound AnswerErrorCode = "QUESTION_NOT_FOUND"
+	QuestionArchived AnswerErrorCode = "QUESTION_ARCHIVED"
+	WrongAnswerType  AnswerErrorCode = "WRONG_ANSWER_TYPE"
+	AnswerOutOfRange AnswerErrorCode = "ANSWER_OUT_OF_RANGE"
+	UnknownChoice    AnswerErrorCode = "UNKNOWN_CHOICE"
+)
+
+// AnswerError is returned when an answer is not valid for its question
+type AnswerError struct {
+	Code    AnswerErrorCode
+	Message string
+}
+
+func (e *AnswerError) Error() string {
+	return e.Message
+}
+
+// Extensions exposes the error code within the graphql error's extensions
+func (e *AnswerError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": string(e.Code),
+	}
+}
+
+func answerError(code AnswerErrorCode, format string, args ...interface{}) error {
+	return &AnswerError{
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// optionInt returns the numeric option as an int, stores may decode numbers as any numeric type
+func optionInt(q impact.Question, key string) (int, bool) {
+	switch v := q.Options[key].(type) {
+	case int:
+		return v, true
+	case int32:
+		return int(v), true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	default:
+		return 0, false
+	}
+}
+
+func hasChoice(q impact.Question, choice string) bool {
+	for _, c := range q.Choices() {
+		if c == choice {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateAnswer checks that the answer is appropriate for the question it answers within the outcome set.
+// Problems with the answer are reported as an *AnswerError.
+func ValidateAnswer(os impact.OutcomeSet, a impact.Answer) error {
+	q := os.GetQuestion(a.QuestionID)
+	if q == nil {
+		return answerError(QuestionNotFound, "Question %s does not exist in outcome set %s", a.QuestionID, os.ID)
+	}
+	if q.Deleted {
+		return answerError(QuestionArchived, "Question %s has been archived and can no longer be answered", q.ID)
+	}
+	switch q.Type {
+	case impact.LIKERT:
+		return validateLikertAnswer(*q, a)
+	case impact.FREE_TEXT:
+		return validateFreeTextAnswer(*q, a)
+	case impact.YES_NO:
+		return validateYesNoAnswer(*q, a)
+	case impact.SINGLE_CHOICE:
+		return validateSingleChoiceAnswer(*q, a)
+	case impact.MULTI_CHOICE:
+		return validateMultiChoiceAnswer(*q, a)
+	case impact.NUMERIC:
+		return validateNumericAnswer(*q, a)
+	default:
+		return answerError(WrongAnswerType, "Question %s has an unsupported type %s", q.ID, q.Type)
+	}
+}
+
+func validateLikertAnswer(q impact.Question, a impact.Answer) error {
+	if a.Type != impact.INT {
+		return answerError(WrongAnswerType, "Likert question %s expects an int answer, got %s", q.ID, a.Type)
+	}
+	v, ok := a.Answer.(int)
+	if !ok {
+		return answerError(WrongAnswerType, "Likert question %s expects an int answer", q.ID)
+	}
+	min, _ := optionInt(q, "minValue")
+	max, ok := optionInt(q, "maxValue")
+	if !ok {
+		return fmt.Errorf("Likert question %s is missing a maxValue", q.ID)
+	}
+	if v < min || v > max {
+		return answerError(AnswerOutOfRange, "Answer %d is outside of the range %d to %d for question %s", v, min, max, q.ID)
+	}
+	return nil
+}
+
+func validateFreeTextAnswer(q impact.Question, a impact.Answer) error {
+	if _, ok := a.Answer.(string); !ok || a.Type != impact.STRING {
+		return answerError(WrongAnswerType, "Free text question %s expects a string answer", q.ID)
+	}
+	return nil
+}
+
+func validateYesNoAnswer(q impact.Question, a impact.Answer) error {
+	if _, ok := a.Answer.(bool); !ok || a.Type != impact.BOOL {
+		return answerError(WrongAnswerType, "Yes/no question %s expects a bool answer", q.ID)
+	}
+	return nil
+}
+
+func validateSingleChoiceAnswer(q impact.Question, a impact.Answer) error {
+	v, ok := a.Answer.(string)
+	if !ok || a.Type != impact.STRING {
+		return answerError(WrongAnswerType, "Single choice question %s expects a string answer", q.ID)
+	}
+	if !hasChoice(q, v) {
+		return answerError(UnknownChoice, "%s is not one of the choices for question %s", v, q.ID)
+	}
+	return nil
+}
+
+func validateMultiChoiceAnswer(q impact.Question, a impact.Answer) error {
+	values := a.ToStrings()
+	if values == nil || a.Type != impact.STRING_LIST {
+		return answerError(WrongAnswerType, "Multi choice question %s expects a string list answer", q.ID)
+	}
+	seen := map[string]bool{}
+	for _, v := range values {
+		if !hasChoice(q, v) {
+			return answerError(UnknownChoice, "%s is not one of the choices for question %s", v, q.ID)
+		}
+		if seen[v] {
+			return answerError(UnknownChoice, "%s was chosen more than once for question %s", v, q.ID)
+		}
+		seen[v] = true
+	}
+	return nil
+}
+
+func validateNumericAnswer(q impact.Question, a impact.Answer) error {
+	v, ok := a.Answer.(float64)
+	if !ok || a.Type != impact.FLOAT {
+		return answerError(WrongAnswerType, "Numeric question %s expects a float answer", q.ID)
+	}
+	if min, ok := q.NumericOption("minValue"); ok && v < min {
+		return answerError(AnswerOutOfRange, "Answer %g is less than the minimum %g for question %s", v, min, q.ID)
+	}
+	if max, ok := q.NumericOption("maxValue"); ok && v > max {
+		return answerError(AnswerOutOfRange, "Answer %g is greater than the maximum %g for question %s", v, max, q.ID)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package logic_test
+
+import (
+	"testing"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/logic"
+	"github.com/stretchr/testify/assert"
+)
+
+func getValidationOutcomeSet() impact.OutcomeSet {
+	return impact.OutcomeSet{
+		ID: "os",
+		Questions: []impact.Question{{
+			ID:   "likert",
+			Type: impact.LIKERT,
+			Options: map[string]interface{}{
+				"minValue": 1,
+				"maxValue": float64(5),
+			},
+		}, {
+			ID:      "archived",
+			Type:    impact.LIKERT,
+			Deleted: true,
+			Options: map[string]interface{}{
+				"minValue": 1,
+				"maxValue": 5,
+			},
+		}},
+	}
+}
+
+func assertAnswerError(t *testing.T, code logic.AnswerErrorCode, err error) {
+	aErr, ok := err.(*logic.AnswerError)
+	if assert.True(t, ok, "expected an *AnswerError, got %v", err) {
+		assert.Equal(t, code, aErr.Code)
+		assert.Equal(t, string(code), aErr.Extensions()["code"])
+	}
+}
+
+func TestValidAnswer(t *testing.T) {
+	os := getValidationOutcomeSet()
+	for _, v := range []int{1, 3, 5} {
+		assert.Nil(t, logic.ValidateAnswer(os, impact.Answer{
+			QuestionID: "likert",
+			Type:       impact.INT,
+			Answer:     v,
+		}))
+	}
+}
+
+func TestAnswerUnknownQuestion(t *testing.T) {
+	assertAnswerError(t, logic.QuestionNotFound, logic.ValidateAnswer(getValidationOutcomeSet(), impact.Answer{
+		QuestionID: "unknown",
+		Type:       impact.INT,
+		Answer:     1,
+	}))
+}
+
+func TestAnswerArchivedQuestion(t *testing.T) {
+	assertAnswerError(t, logic.QuestionArchived, logic.ValidateAnswer(getValidationOutcomeSet(), impact.Answer{
+		QuestionID: "archived",
+		Type:       impact.INT,
+		Answer:     1,
+	}))
+}
+
+func TestAnswerWrongType(t *testing.T) {
+	assertAnswerError(t, logic.WrongAnswerType, logic.ValidateAnswer(getValidationOutcomeSet(), impact.Answer{
+		QuestionID: "likert",
+		Type:       impact.AnswerType("string"),
+		Answer:     "1",
+	}))
+}
+
+func TestAnswerOutOfRange(t *testing.T) {
+	os := getValidationOutcomeSet()
+	for _, v := range []int{0, 6} {
+		assertAnswerError(t, logic.AnswerOutOfRange, logic.ValidateAnswer(os, impact.Answer{
+			QuestionID: "likert",
+			Type:       impact.INT,
+			Answer:     v,
+		}))
+	}
+}
+
+func TestQuestionTypeAnswers(t *testing.T) {
+	os := impact.OutcomeSet{
+		ID: "os",
+		Questions: []impact.Question{{
+			ID:   "text",
+			Type: impact.FREE_TEXT,
+		}, {
+			ID:   "yesno",
+			Type: impact.YES_NO,
+		}, {
+			ID:      "single",
+			Type:    impact.SINGLE_CHOICE,
+			Options: map[string]interface{}{"choices": []interface{}{"red", "green", "blue"}},
+		}, {
+			ID:      "multi",
+			Type:    impact.MULTI_CHOICE,
+			Options: map[string]interface{}{"choices": []string{"red", "green", "blue"}},
+		}, {
+			ID:      "numeric",
+			Type:    impact.NUMERIC,
+			Options: map[string]interface{}{"minValue": 0, "maxValue": 10.5},
+		}},
+	}
+
+	valid := []impact.Answer{
+		{QuestionID: "text", Type: impact.STRING, Answer: "Notes"},
+		{QuestionID: "yesno", Type: impact.BOOL, Answer: false},
+		{QuestionID: "single", Type: impact.STRING, Answer: "green"},
+		{QuestionID: "multi", Type: impact.STRING_LIST, Answer: []string{"red", "blue"}},
+		{QuestionID: "multi", Type: impact.STRING_LIST, Answer: []string{}},
+		{QuestionID: "numeric", Type: impact.FLOAT, Answer: 10.5},
+	}
+	for _, a := range valid {
+		assert.Nil(t, logic.ValidateAnswer(os, a), "answer to %s should be valid", a.QuestionID)
+	}
+
+	assertAnswerError(t, logic.WrongAnswerType, logic.ValidateAnswer(os, impact.Answer{QuestionID: "text", Type: impact.INT, Answer: 1}))
+	assertAnswerError(t, logic.WrongAnswerType, logic.ValidateAnswer(os, impact.Answer{QuestionID: "yesno", Type: impact.STRING, Answer: "yes"}))
+	assertAnswerError(t, logic.UnknownChoice, logic.ValidateAnswer(os, impact.Answer{QuestionID: "single", Type: impact.STRING, Answer: "purple"}))
+	assertAnswerError(t, logic.UnknownChoice, logic.ValidateAnswer(os, impact.Answer{QuestionID: "multi", Type: impact.STRING_LIST, Answer: []string{"red", "red"}}))
+	assertAnswerError(t, logic.AnswerOutOfRange, logic.ValidateAnswer(os, impact.Answer{QuestionID: "numeric", Type: impact.FLOAT, Answer: -0.5}))
+	assertAnswerError(t, logic.AnswerOutOfRange, logic.ValidateAnswer(os, impact.Answer{QuestionID: "numeric", Type: impact.FLOAT, Answer: 11.0}))
+}
@@ -0,0 +1,29 @@
+package logic
+
+import (
+	"errors"
+	"strings"
+)
+
+// NormaliseTags trims whitespace from the tags, removing empty and duplicate tags
+func NormaliseTags(tags []string) []string {
+	normalised := []string{}
+	seen := map[string]bool{}
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		normalised = append(normalised, t)
+	}
+	return normalised
+}
+
+// ValidateBeneficiaryID checks the ID can be used to identify a beneficiary
+func ValidateBeneficiaryID(id string) error {
+	if strings.TrimSpace(id) == "" {
+		return errors.New("Beneficiary ID must not be empty")
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package logic
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+)
+
+type ExportDatabase interface {
+	GetBeneficiary(id string, u auth.User) (impact.Beneficiary, error)
+	GetAllMeetingsForBeneficiary(beneficiary string, u auth.User) ([]impact.Meeting, error)
+	GetOutcomeSet(id string, u auth.User) (impact.OutcomeSet, error)
+}
+
+// ExportedAnswer is an answer along with the question it answers
+type ExportedAnswer struct {
+	QuestionID string      `json:"questionID"`
+	Question   string      `json:"question"`
+	Answer     interface{} `json:"answer"`
+}
+
+// ExportedMeeting is a meeting along with the name of its outcome set and the text of the questions answered
+type ExportedMeeting struct {
+	ID           string               `json:"id"`
+	OutcomeSetID string               `json:"outcomeSetID"`
+	OutcomeSet   string               `json:"outcomeSet"`
+	User         string               `json:"user"`
+	Conducted    time.Time            `json:"conducted"`
+	Created      time.Time            `json:"created"`
+	Modified     time.Time            `json:"modified"`
+	Status       impact.MeetingStatus `json:"status"`
+	Deleted      bool                 `json:"deleted"`
+	Answers      []ExportedAnswer     `json:"answers"`
+}
+
+// BeneficiaryExport is everything held about a beneficiary, as required to fulfil a subject access request
+type BeneficiaryExport struct {
+	BeneficiaryID string `json:"beneficiaryID"`
+	// Beneficiary is nil if the beneficiary's meetings predate beneficiary records
+	Beneficiary *impact.Beneficiary `json:"beneficiary"`
+	Meetings    []ExportedMeeting   `json:"meetings"`
+}
+
+// ExportBeneficiary gathers everything held about the beneficiary, including deleted meetings
+func ExportBeneficiary(id string, db ExportDatabase, u auth.User) (BeneficiaryExport, error) {
+	export := BeneficiaryExport{
+		BeneficiaryID: id,
+		Meetings:      []ExportedMeeting{},
+	}
+	b, err := db.GetBeneficiary(id, u)
+	if err != nil && !data.IsNotFound(err) {
+		return export, err
+	}
+	if err == nil {
+		export.Beneficiary = &b
+	}
+
+	meetings, err := db.GetAllMeetingsForBeneficiary(id, u)
+	if err != nil {
+		return export, err
+	}
+	if export.Beneficiary == nil && len(meetings) == 0 {
+		return export, data.NewNotFoundError("Beneficiary")
+	}
+	sort.Slice(meetings, func(i, j int) bool {
+		return meetings[i].Conducted.Before(meetings[j].Conducted)
+	})
+
+	outcomeSets := map[string]impact.OutcomeSet{}
+	for _, m := range meetings {
+		os, ok := outcomeSets[m.OutcomeSetID]
+		if !ok {
+			os, err = db.GetOutcomeSet(m.OutcomeSetID, u)
+			if err != nil && !data.IsNotFound(err) {
+				return export, err
+			}
+			outcomeSets[m.OutcomeSetID] = os
+		}
+		em := ExportedMeeting{
+			ID:           m.ID,
+			OutcomeSetID: m.OutcomeSetID,
+			OutcomeSet:   os.Name,
+			User:         m.User,
+			Conducted:    m.Conducted,
+			Created:      m.Created,
+			Modified:     m.Modified,
+			Status:       m.Status,
+			Deleted:      m.Deleted,
+			Answers:      []ExportedAnswer{},
+		}
+		for _, a := range m.Answers {
+			ea := ExportedAnswer{
+				QuestionID: a.QuestionID,
+				Answer:     a.Answer,
+			}
+			if q := os.GetQuestion(a.QuestionID); q != nil {
+				ea.Question = q.Question
+			}
+			em.Answers = append(em.Answers, ea)
+		}
+		export.Meetings = append(export.Meetings, em)
+	}
+	return export, nil
+}
+
+// JSON encodes the export as a JSON document
+func (e BeneficiaryExport) JSON() ([]byte, error) {
+	return json.MarshalIndent(e, "", "  ")
+}
+
+// formatAnswer formats an answer for a CSV cell, the choices made in multi choice answers are separated by semicolons
+func formatAnswer(answer interface{}) string {
+	if choices := (impact.Answer{Answer: answer}).ToStrings(); choices != nil {
+		return strings.Join(choices, "; ")
+	}
+	return fmt.Sprint(answer)
+}
+
+// CSV encodes the export with a row per answer, meetings without answers are included as a single row
+func (e BeneficiaryExport) CSV() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	rows := [][]string{{
+		"beneficiary", "meetingID", "outcomeSetID", "outcomeSet", "user", "conducted", "created", "modified",
+		"status", "deleted", "questionID", "question", "answer",
+	}}
+	for _, m := range e.Meetings {
+		meeting := []string{
+			e.BeneficiaryID, m.ID, m.OutcomeSetID, m.OutcomeSet, m.User, m.Conducted.Format(time.RFC3339),
+			m.Created.Format(time.RFC3339), m.Modified.Format(time.RFC3339), string(m.Status), strconv.FormatBool(m.Deleted),
+		}
+		if len(m.Answers) == 0 {
+			rows = append(rows, append(meeting, "", "", ""))
+		}
+		for _, a := range m.Answers {
+			row := append([]string{}, meeting...)
+			rows = append(rows, append(row, a.QuestionID, a.Question, formatAnswer(a.Answer)))
+		}
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,69 @@
+package logic
+
+import (
+	"fmt"
+	"strings"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+)
+
+type MeetingDatabase interface {
+	GetMeeting(id string, u auth.User) (impact.Meeting, error)
+	GetOutcomeSet(id string, u auth.User) (impact.OutcomeSet, error)
+	GetOutcomeSetVersion(id string, version int, u auth.User) (impact.OutcomeSet, error)
+	SetMeetingStatus(id string, status impact.MeetingStatus, u auth.User) (impact.Meeting, error)
+}
+
+type OutcomeSetVersionDatabase interface {
+	GetOutcomeSet(id string, u auth.User) (impact.OutcomeSet, error)
+	GetOutcomeSetVersion(id string, version int, u auth.User) (impact.OutcomeSet, error)
+}
+
+// MeetingOutcomeSet returns the version of the outcome set the meeting was conducted against.
+// Meetings without a version, as their outcome set did not exist when they were created, use the outcome set as it currently stands.
+func MeetingOutcomeSet(m impact.Meeting, db OutcomeSetVersionDatabase, u auth.User) (impact.OutcomeSet, error) {
+	if m.OutcomeSetVersion > 0 {
+		return db.GetOutcomeSetVersion(m.OutcomeSetID, m.OutcomeSetVersion, u)
+	}
+	return db.GetOutcomeSet(m.OutcomeSetID, u)
+}
+
+// UnansweredQuestions returns the IDs of the outcome set's active questions which the meeting has not answered
+func UnansweredQuestions(m impact.Meeting, os impact.OutcomeSet) []string {
+	out := []string{}
+	for _, q := range os.ActiveQuestions() {
+		if m.GetAnswer(q.ID) == nil {
+			out = append(out, q.ID)
+		}
+	}
+	return out
+}
+
+// CompleteMeeting marks the meeting as completed, which is only possible once all active questions have been answered
+func CompleteMeeting(meetingID string, db MeetingDatabase, u auth.User) (impact.Meeting, error) {
+	m, err := db.GetMeeting(meetingID, u)
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+	os, err := MeetingOutcomeSet(m, db, u)
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+	if unanswered := UnansweredQuestions(m, os); len(unanswered) > 0 {
+		return impact.Meeting{}, fmt.Errorf("Meeting cannot be completed as questions %s have not been answered", strings.Join(unanswered, ", "))
+	}
+	return db.SetMeetingStatus(meetingID, impact.COMPLETED, u)
+}
+
+// AbandonMeeting marks a meeting which will not be completed, so it is excluded from reports
+func AbandonMeeting(meetingID string, db MeetingDatabase, u auth.User) (impact.Meeting, error) {
+	m, err := db.GetMeeting(meetingID, u)
+	if err != nil {
+		return impact.Meeting{}, err
+	}
+	if m.IsComplete() {
+		return impact.Meeting{}, fmt.Errorf("Meeting %s has been completed so cannot be abandoned", meetingID)
+	}
+	return db.SetMeetingStatus(meetingID, impact.ABANDONED, u)
+}
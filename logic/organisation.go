@@ -0,0 +1,39 @@
+package logic
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+)
+
+type OrganisationDatabase interface {
+	GetOutcomeSet(id string, u auth.User) (impact.OutcomeSet, error)
+}
+
+// matches BCP 47 language tags such as en, en-GB and zh-Hant-TW
+var localePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// ValidateOrganisationSettings checks the settings are valid for the user's organisation, empty settings are always valid
+func ValidateOrganisationSettings(s impact.OrganisationSettings, db OrganisationDatabase, u auth.User) error {
+	if s.Timezone != "" {
+		if _, err := time.LoadLocation(s.Timezone); err != nil {
+			return fmt.Errorf("Unknown timezone %s", s.Timezone)
+		}
+	}
+	if s.Locale != "" && !localePattern.MatchString(s.Locale) {
+		return fmt.Errorf("Invalid locale %s, expecting a language tag such as en-GB", s.Locale)
+	}
+	if s.DefaultOutcomeSetID != "" {
+		os, err := db.GetOutcomeSet(s.DefaultOutcomeSetID, u)
+		if err != nil {
+			return err
+		}
+		if os.Deleted {
+			return fmt.Errorf("Outcome set %s has been deleted", s.DefaultOutcomeSetID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// NormaliseChoices trims the choices offered by a single or multi choice question, checking at least two distinct choices remain
+func NormaliseChoices(choices []string) ([]string, error) {
+	out := make([]string, 0, len(choices))
+	seen := map[string]bool{}
+	for _, c := range choices {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			return nil, errors.New("Choices must not be empty")
+		}
+		if seen[c] {
+			return nil, fmt.Errorf("Choice %s is listed more than once", c)
+		}
+		seen[c] = true
+		out = append(out, c)
+	}
+	if len(out) < 2 {
+		return nil, errors.New("At least two choices must be provided")
+	}
+	return out, nil
+}
+
+// ValidateNumericRange checks that the optional bounds of a numeric question are in order
+func ValidateNumericRange(min, max *float64) error {
+	if min != nil && max != nil && *min > *max {
+		return fmt.Errorf("minValue %g must not be greater than maxValue %g", *min, *max)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package logic
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	impact "github.com/impactasaurus/server"
+	"github.com/impactasaurus/server/auth"
+	"github.com/impactasaurus/server/data"
+)
+
+// shortCodeAlphabet excludes characters which are easily confused when read aloud or written down, e.g. 0 and O
+const shortCodeAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+
+// ShortCodeLength is the number of characters in a short code
+const ShortCodeLength = 8
+
+const shortCodeAttempts = 5
+
+type ShortCodeDatabase interface {
+	NewShortCode(code, meetingID, beneficiaryID string, expiry time.Time, u auth.User) (impact.ShortCode, error)
+}
+
+func generateShortCode() (string, error) {
+	max := big.NewInt(int64(len(shortCodeAlphabet)))
+	code := make([]byte, ShortCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = shortCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// NewShortCode stores a randomly generated short code which can be exchanged for a beneficiary JWT until the expiry
+func NewShortCode(meetingID, beneficiaryID string, expiry time.Time, db ShortCodeDatabase, u auth.User) (impact.ShortCode, error) {
+	for i := 0; i < shortCodeAttempts; i++ {
+		code, err := generateShortCode()
+		if err != nil {
+			return impact.ShortCode{}, err
+		}
+		sc, err := db.NewShortCode(code, meetingID, beneficiaryID, expiry, u)
+		if err == data.ErrShortCodeInUse {
+			continue
+		}
+		return sc, err
+	}
+	return impact.ShortCode{}, errors.New("Failed to generate a unique short code")
+}
+
+// NormaliseShortCode removes formatting which users may add when entering a short code
+func NormaliseShortCode(code string) string {
+	return strings.ToUpper(strings.Replace(strings.TrimSpace(code), "-", "", -1))
+}
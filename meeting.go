@@ -7,7 +7,22 @@ import (
 
 type AnswerType string
 
-const INT AnswerType = "int"
+const (
+	INT         AnswerType = "int"
+	FLOAT       AnswerType = "float"
+	STRING      AnswerType = "string"
+	BOOL        AnswerType = "bool"
+	STRING_LIST AnswerType = "string_list"
+)
+
+// MeetingStatus tracks a meeting through its lifecycle
+type MeetingStatus string
+
+const (
+	IN_PROGRESS MeetingStatus = "in_progress"
+	COMPLETED   MeetingStatus = "completed"
+	ABANDONED   MeetingStatus = "abandoned"
+)
 
 type Answer struct {
 	QuestionID string      `json:"questionID" bson:"questionID"`
@@ -16,15 +31,20 @@ type Answer struct {
 }
 
 type Meeting struct {
-	ID             string    `json:"id" bson:"_id"`
-	Beneficiary    string    `json:"beneficiary"`
-	User           string    `json:"user"`
-	OutcomeSetID   string    `json:"outcomeSetID" bson:"outcomeSetID"`
-	OrganisationID string    `json:"organisationID" bson:"organisationID"`
-	Answers        []Answer  `json:"answers"`
-	Conducted      time.Time `json:"conducted"`
-	Created        time.Time `json:"created"`
-	Modified       time.Time `json:"modified"`
+	ID             string        `json:"id" bson:"_id"`
+	Beneficiary    string        `json:"beneficiary"`
+	User           string        `json:"user"`
+	OutcomeSetID   string        `json:"outcomeSetID" bson:"outcomeSetID"`
+	OrganisationID string        `json:"organisationID" bson:"organisationID"`
+	Answers        []Answer      `json:"answers"`
+	Conducted      time.Time     `json:"conducted"`
+	Created        time.Time     `json:"created"`
+	Modified       time.Time     `json:"modified"`
+	Deleted        bool          `json:"deleted"`
+	Status         MeetingStatus `json:"status"`
+	// OutcomeSetVersion is the published version of the outcome set the meeting was conducted against,
+	// 0 if the outcome set did not exist when the meeting was created
+	OutcomeSetVersion int `json:"outcomeSetVersion" bson:"outcomeSetVersion"`
 }
 
 // CategoryAggregate aggregates multiple questions belonging to the same category to a question category level
@@ -39,7 +59,7 @@ type Aggregates struct {
 }
 
 func (a Answer) IsNumeric() bool {
-	return a.Type == INT
+	return a.Type == INT || a.Type == FLOAT
 }
 
 func (a Answer) ToFloat() (float32, error) {
@@ -59,6 +79,36 @@ func (a Answer) ToFloat() (float32, error) {
 	}
 }
 
+// ToStrings returns the values of a STRING_LIST answer, or nil if the answer is not a list of strings
+func (a Answer) ToStrings() []string {
+	return toStrings(a.Answer)
+}
+
+// toStrings converts a list of strings, stores may decode lists as []interface{}
+func toStrings(v interface{}) []string {
+	switch l := v.(type) {
+	case []string:
+		return l
+	case []interface{}:
+		out := make([]string, 0, len(l))
+		for _, i := range l {
+			s, ok := i.(string)
+			if !ok {
+				return nil
+			}
+			out = append(out, s)
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// IsComplete returns true if the meeting has been completed, only completed meetings should be reported on
+func (m *Meeting) IsComplete() bool {
+	return m.Status == COMPLETED
+}
+
 func (m *Meeting) GetAnswer(questionID string) *Answer {
 	for _, a := range m.Answers {
 		if a.QuestionID == questionID {
@@ -67,3 +117,14 @@ func (m *Meeting) GetAnswer(questionID string) *Answer {
 	}
 	return nil
 }
+
+// SetAnswer stores the answer, replacing any existing answer to the same question
+func (m *Meeting) SetAnswer(answer Answer) {
+	for i, a := range m.Answers {
+		if a.QuestionID == answer.QuestionID {
+			m.Answers[i] = answer
+			return
+		}
+	}
+	m.Answers = append(m.Answers, answer)
+}
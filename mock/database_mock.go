@@ -11,6 +11,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	server "github.com/impactasaurus/server"
 	auth "github.com/impactasaurus/server/auth"
+	data "github.com/impactasaurus/server/data"
 )
 
 // MockBase is a mock of Base interface
@@ -36,6 +37,19 @@ func (m *MockBase) EXPECT() *MockBaseMockRecorder {
 	return m.recorder
 }
 
+// CreateOrganisation mocks base method
+func (m *MockBase) CreateOrganisation(arg0 string, arg1 auth.User) (server.Organisation, error) {
+	ret := m.ctrl.Call(m, "CreateOrganisation", arg0, arg1)
+	ret0, _ := ret[0].(server.Organisation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrganisation indicates an expected call of CreateOrganisation
+func (mr *MockBaseMockRecorder) CreateOrganisation(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrganisation", reflect.TypeOf((*MockBase)(nil).CreateOrganisation), arg0, arg1)
+}
+
 // DeleteCategory mocks base method
 func (m *MockBase) DeleteCategory(arg0, arg1 string, arg2 auth.User) error {
 	ret := m.ctrl.Call(m, "DeleteCategory", arg0, arg1, arg2)
@@ -48,6 +62,18 @@ func (mr *MockBaseMockRecorder) DeleteCategory(arg0, arg1, arg2 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockBase)(nil).DeleteCategory), arg0, arg1, arg2)
 }
 
+// DeleteMeeting mocks base method
+func (m *MockBase) DeleteMeeting(arg0 string, arg1 auth.User) error {
+	ret := m.ctrl.Call(m, "DeleteMeeting", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMeeting indicates an expected call of DeleteMeeting
+func (mr *MockBaseMockRecorder) DeleteMeeting(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMeeting", reflect.TypeOf((*MockBase)(nil).DeleteMeeting), arg0, arg1)
+}
+
 // DeleteOutcomeSet mocks base method
 func (m *MockBase) DeleteOutcomeSet(arg0 string, arg1 auth.User) error {
 	ret := m.ctrl.Call(m, "DeleteOutcomeSet", arg0, arg1)
@@ -72,6 +98,58 @@ func (mr *MockBaseMockRecorder) DeleteQuestion(arg0, arg1, arg2 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteQuestion", reflect.TypeOf((*MockBase)(nil).DeleteQuestion), arg0, arg1, arg2)
 }
 
+// EditBeneficiary mocks base method
+func (m *MockBase) EditBeneficiary(arg0 string, arg1 []string, arg2 server.BeneficiaryStatus, arg3 auth.User) (server.Beneficiary, error) {
+	ret := m.ctrl.Call(m, "EditBeneficiary", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(server.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditBeneficiary indicates an expected call of EditBeneficiary
+func (mr *MockBaseMockRecorder) EditBeneficiary(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditBeneficiary", reflect.TypeOf((*MockBase)(nil).EditBeneficiary), arg0, arg1, arg2, arg3)
+}
+
+// EditCategory mocks base method
+func (m *MockBase) EditCategory(arg0, arg1, arg2, arg3 string, arg4 server.Aggregation, arg5 auth.User) (server.Category, error) {
+	ret := m.ctrl.Call(m, "EditCategory", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(server.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditCategory indicates an expected call of EditCategory
+func (mr *MockBaseMockRecorder) EditCategory(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditCategory", reflect.TypeOf((*MockBase)(nil).EditCategory), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// EditMeeting mocks base method
+func (m *MockBase) EditMeeting(arg0, arg1 string, arg2 time.Time, arg3 auth.User) (server.Meeting, error) {
+	ret := m.ctrl.Call(m, "EditMeeting", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(server.Meeting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditMeeting indicates an expected call of EditMeeting
+func (mr *MockBaseMockRecorder) EditMeeting(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditMeeting", reflect.TypeOf((*MockBase)(nil).EditMeeting), arg0, arg1, arg2, arg3)
+}
+
+// EditOrganisation mocks base method
+func (m *MockBase) EditOrganisation(arg0, arg1 string, arg2 server.OrganisationSettings, arg3 auth.User) (server.Organisation, error) {
+	ret := m.ctrl.Call(m, "EditOrganisation", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(server.Organisation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditOrganisation indicates an expected call of EditOrganisation
+func (mr *MockBaseMockRecorder) EditOrganisation(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditOrganisation", reflect.TypeOf((*MockBase)(nil).EditOrganisation), arg0, arg1, arg2, arg3)
+}
+
 // EditOutcomeSet mocks base method
 func (m *MockBase) EditOutcomeSet(arg0, arg1, arg2 string, arg3 auth.User) (server.OutcomeSet, error) {
 	ret := m.ctrl.Call(m, "EditOutcomeSet", arg0, arg1, arg2, arg3)
@@ -98,6 +176,109 @@ func (mr *MockBaseMockRecorder) EditQuestion(arg0, arg1, arg2, arg3, arg4, arg5,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditQuestion", reflect.TypeOf((*MockBase)(nil).EditQuestion), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
 }
 
+// EraseBeneficiary mocks base method
+func (m *MockBase) EraseBeneficiary(arg0 string, arg1 auth.User) error {
+	ret := m.ctrl.Call(m, "EraseBeneficiary", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EraseBeneficiary indicates an expected call of EraseBeneficiary
+func (mr *MockBaseMockRecorder) EraseBeneficiary(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EraseBeneficiary", reflect.TypeOf((*MockBase)(nil).EraseBeneficiary), arg0, arg1)
+}
+
+// GetAPIKeyByHash mocks base method
+func (m *MockBase) GetAPIKeyByHash(arg0 string) (server.APIKey, error) {
+	ret := m.ctrl.Call(m, "GetAPIKeyByHash", arg0)
+	ret0, _ := ret[0].(server.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByHash indicates an expected call of GetAPIKeyByHash
+func (mr *MockBaseMockRecorder) GetAPIKeyByHash(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByHash", reflect.TypeOf((*MockBase)(nil).GetAPIKeyByHash), arg0)
+}
+
+// GetAPIKeys mocks base method
+func (m *MockBase) GetAPIKeys(arg0 auth.User) ([]server.APIKey, error) {
+	ret := m.ctrl.Call(m, "GetAPIKeys", arg0)
+	ret0, _ := ret[0].([]server.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeys indicates an expected call of GetAPIKeys
+func (mr *MockBaseMockRecorder) GetAPIKeys(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeys", reflect.TypeOf((*MockBase)(nil).GetAPIKeys), arg0)
+}
+
+// GetAllMeetingsForBeneficiary mocks base method
+func (m *MockBase) GetAllMeetingsForBeneficiary(arg0 string, arg1 auth.User) ([]server.Meeting, error) {
+	ret := m.ctrl.Call(m, "GetAllMeetingsForBeneficiary", arg0, arg1)
+	ret0, _ := ret[0].([]server.Meeting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllMeetingsForBeneficiary indicates an expected call of GetAllMeetingsForBeneficiary
+func (mr *MockBaseMockRecorder) GetAllMeetingsForBeneficiary(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMeetingsForBeneficiary", reflect.TypeOf((*MockBase)(nil).GetAllMeetingsForBeneficiary), arg0, arg1)
+}
+
+// GetAssessmentMeeting mocks base method
+func (m *MockBase) GetAssessmentMeeting(arg0 auth.User) (server.Meeting, error) {
+	ret := m.ctrl.Call(m, "GetAssessmentMeeting", arg0)
+	ret0, _ := ret[0].(server.Meeting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAssessmentMeeting indicates an expected call of GetAssessmentMeeting
+func (mr *MockBaseMockRecorder) GetAssessmentMeeting(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssessmentMeeting", reflect.TypeOf((*MockBase)(nil).GetAssessmentMeeting), arg0)
+}
+
+// GetAuditEntries mocks base method
+func (m *MockBase) GetAuditEntries(arg0 data.AuditFilter, arg1 auth.User) ([]server.AuditEntry, error) {
+	ret := m.ctrl.Call(m, "GetAuditEntries", arg0, arg1)
+	ret0, _ := ret[0].([]server.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditEntries indicates an expected call of GetAuditEntries
+func (mr *MockBaseMockRecorder) GetAuditEntries(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditEntries", reflect.TypeOf((*MockBase)(nil).GetAuditEntries), arg0, arg1)
+}
+
+// GetBeneficiaries mocks base method
+func (m *MockBase) GetBeneficiaries(arg0 auth.User) ([]server.Beneficiary, error) {
+	ret := m.ctrl.Call(m, "GetBeneficiaries", arg0)
+	ret0, _ := ret[0].([]server.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBeneficiaries indicates an expected call of GetBeneficiaries
+func (mr *MockBaseMockRecorder) GetBeneficiaries(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBeneficiaries", reflect.TypeOf((*MockBase)(nil).GetBeneficiaries), arg0)
+}
+
+// GetBeneficiary mocks base method
+func (m *MockBase) GetBeneficiary(arg0 string, arg1 auth.User) (server.Beneficiary, error) {
+	ret := m.ctrl.Call(m, "GetBeneficiary", arg0, arg1)
+	ret0, _ := ret[0].(server.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBeneficiary indicates an expected call of GetBeneficiary
+func (mr *MockBaseMockRecorder) GetBeneficiary(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBeneficiary", reflect.TypeOf((*MockBase)(nil).GetBeneficiary), arg0, arg1)
+}
+
 // GetCategory mocks base method
 func (m *MockBase) GetCategory(arg0, arg1 string, arg2 auth.User) (server.Category, error) {
 	ret := m.ctrl.Call(m, "GetCategory", arg0, arg1, arg2)
@@ -189,6 +370,32 @@ func (mr *MockBaseMockRecorder) GetOutcomeSet(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutcomeSet", reflect.TypeOf((*MockBase)(nil).GetOutcomeSet), arg0, arg1)
 }
 
+// GetOutcomeSetVersion mocks base method
+func (m *MockBase) GetOutcomeSetVersion(arg0 string, arg1 int, arg2 auth.User) (server.OutcomeSet, error) {
+	ret := m.ctrl.Call(m, "GetOutcomeSetVersion", arg0, arg1, arg2)
+	ret0, _ := ret[0].(server.OutcomeSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutcomeSetVersion indicates an expected call of GetOutcomeSetVersion
+func (mr *MockBaseMockRecorder) GetOutcomeSetVersion(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutcomeSetVersion", reflect.TypeOf((*MockBase)(nil).GetOutcomeSetVersion), arg0, arg1, arg2)
+}
+
+// GetOutcomeSetVersions mocks base method
+func (m *MockBase) GetOutcomeSetVersions(arg0 string, arg1 auth.User) ([]server.OutcomeSet, error) {
+	ret := m.ctrl.Call(m, "GetOutcomeSetVersions", arg0, arg1)
+	ret0, _ := ret[0].([]server.OutcomeSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutcomeSetVersions indicates an expected call of GetOutcomeSetVersions
+func (mr *MockBaseMockRecorder) GetOutcomeSetVersions(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutcomeSetVersions", reflect.TypeOf((*MockBase)(nil).GetOutcomeSetVersions), arg0, arg1)
+}
+
 // GetOutcomeSets mocks base method
 func (m *MockBase) GetOutcomeSets(arg0 auth.User) ([]server.OutcomeSet, error) {
 	ret := m.ctrl.Call(m, "GetOutcomeSets", arg0)
@@ -215,6 +422,32 @@ func (mr *MockBaseMockRecorder) GetQuestion(arg0, arg1, arg2 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuestion", reflect.TypeOf((*MockBase)(nil).GetQuestion), arg0, arg1, arg2)
 }
 
+// GetShortCode mocks base method
+func (m *MockBase) GetShortCode(arg0 string) (server.ShortCode, error) {
+	ret := m.ctrl.Call(m, "GetShortCode", arg0)
+	ret0, _ := ret[0].(server.ShortCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShortCode indicates an expected call of GetShortCode
+func (mr *MockBaseMockRecorder) GetShortCode(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShortCode", reflect.TypeOf((*MockBase)(nil).GetShortCode), arg0)
+}
+
+// IsAssessmentRevoked mocks base method
+func (m *MockBase) IsAssessmentRevoked(arg0 string) (bool, error) {
+	ret := m.ctrl.Call(m, "IsAssessmentRevoked", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsAssessmentRevoked indicates an expected call of IsAssessmentRevoked
+func (mr *MockBaseMockRecorder) IsAssessmentRevoked(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAssessmentRevoked", reflect.TypeOf((*MockBase)(nil).IsAssessmentRevoked), arg0)
+}
+
 // MoveQuestion mocks base method
 func (m *MockBase) MoveQuestion(arg0, arg1 string, arg2 uint, arg3 auth.User) error {
 	ret := m.ctrl.Call(m, "MoveQuestion", arg0, arg1, arg2, arg3)
@@ -227,6 +460,19 @@ func (mr *MockBaseMockRecorder) MoveQuestion(arg0, arg1, arg2, arg3 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveQuestion", reflect.TypeOf((*MockBase)(nil).MoveQuestion), arg0, arg1, arg2, arg3)
 }
 
+// NewAPIKey mocks base method
+func (m *MockBase) NewAPIKey(arg0, arg1 string, arg2 []string, arg3 auth.User) (server.APIKey, error) {
+	ret := m.ctrl.Call(m, "NewAPIKey", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(server.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewAPIKey indicates an expected call of NewAPIKey
+func (mr *MockBaseMockRecorder) NewAPIKey(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewAPIKey", reflect.TypeOf((*MockBase)(nil).NewAPIKey), arg0, arg1, arg2, arg3)
+}
+
 // NewAnswer mocks base method
 func (m *MockBase) NewAnswer(arg0 string, arg1 server.Answer, arg2 auth.User) (server.Meeting, error) {
 	ret := m.ctrl.Call(m, "NewAnswer", arg0, arg1, arg2)
@@ -240,6 +486,19 @@ func (mr *MockBaseMockRecorder) NewAnswer(arg0, arg1, arg2 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewAnswer", reflect.TypeOf((*MockBase)(nil).NewAnswer), arg0, arg1, arg2)
 }
 
+// NewAuditEntry mocks base method
+func (m *MockBase) NewAuditEntry(arg0 server.AuditEntry) (server.AuditEntry, error) {
+	ret := m.ctrl.Call(m, "NewAuditEntry", arg0)
+	ret0, _ := ret[0].(server.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewAuditEntry indicates an expected call of NewAuditEntry
+func (mr *MockBaseMockRecorder) NewAuditEntry(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewAuditEntry", reflect.TypeOf((*MockBase)(nil).NewAuditEntry), arg0)
+}
+
 // NewCategory mocks base method
 func (m *MockBase) NewCategory(arg0, arg1, arg2 string, arg3 server.Aggregation, arg4 auth.User) (server.Category, error) {
 	ret := m.ctrl.Call(m, "NewCategory", arg0, arg1, arg2, arg3, arg4)
@@ -292,6 +551,32 @@ func (mr *MockBaseMockRecorder) NewQuestion(arg0, arg1, arg2, arg3, arg4, arg5 i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewQuestion", reflect.TypeOf((*MockBase)(nil).NewQuestion), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
+// NewShortCode mocks base method
+func (m *MockBase) NewShortCode(arg0, arg1, arg2 string, arg3 time.Time, arg4 auth.User) (server.ShortCode, error) {
+	ret := m.ctrl.Call(m, "NewShortCode", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(server.ShortCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewShortCode indicates an expected call of NewShortCode
+func (mr *MockBaseMockRecorder) NewShortCode(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewShortCode", reflect.TypeOf((*MockBase)(nil).NewShortCode), arg0, arg1, arg2, arg3, arg4)
+}
+
+// PublishOutcomeSet mocks base method
+func (m *MockBase) PublishOutcomeSet(arg0 string, arg1 auth.User) (server.OutcomeSet, error) {
+	ret := m.ctrl.Call(m, "PublishOutcomeSet", arg0, arg1)
+	ret0, _ := ret[0].(server.OutcomeSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishOutcomeSet indicates an expected call of PublishOutcomeSet
+func (mr *MockBaseMockRecorder) PublishOutcomeSet(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishOutcomeSet", reflect.TypeOf((*MockBase)(nil).PublishOutcomeSet), arg0, arg1)
+}
+
 // RemoveCategory mocks base method
 func (m *MockBase) RemoveCategory(arg0, arg1 string, arg2 auth.User) (server.Question, error) {
 	ret := m.ctrl.Call(m, "RemoveCategory", arg0, arg1, arg2)
@@ -305,6 +590,43 @@ func (mr *MockBaseMockRecorder) RemoveCategory(arg0, arg1, arg2 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCategory", reflect.TypeOf((*MockBase)(nil).RemoveCategory), arg0, arg1, arg2)
 }
 
+// RenameBeneficiary mocks base method
+func (m *MockBase) RenameBeneficiary(arg0, arg1 string, arg2 auth.User) (server.Beneficiary, error) {
+	ret := m.ctrl.Call(m, "RenameBeneficiary", arg0, arg1, arg2)
+	ret0, _ := ret[0].(server.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameBeneficiary indicates an expected call of RenameBeneficiary
+func (mr *MockBaseMockRecorder) RenameBeneficiary(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameBeneficiary", reflect.TypeOf((*MockBase)(nil).RenameBeneficiary), arg0, arg1, arg2)
+}
+
+// RevokeAPIKey mocks base method
+func (m *MockBase) RevokeAPIKey(arg0 string, arg1 auth.User) error {
+	ret := m.ctrl.Call(m, "RevokeAPIKey", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey
+func (mr *MockBaseMockRecorder) RevokeAPIKey(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockBase)(nil).RevokeAPIKey), arg0, arg1)
+}
+
+// RevokeAssessment mocks base method
+func (m *MockBase) RevokeAssessment(arg0 string, arg1 auth.User) error {
+	ret := m.ctrl.Call(m, "RevokeAssessment", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAssessment indicates an expected call of RevokeAssessment
+func (mr *MockBaseMockRecorder) RevokeAssessment(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAssessment", reflect.TypeOf((*MockBase)(nil).RevokeAssessment), arg0, arg1)
+}
+
 // SetCategory mocks base method
 func (m *MockBase) SetCategory(arg0, arg1, arg2 string, arg3 auth.User) (server.Question, error) {
 	ret := m.ctrl.Call(m, "SetCategory", arg0, arg1, arg2, arg3)
@@ -317,3 +639,16 @@ func (m *MockBase) SetCategory(arg0, arg1, arg2 string, arg3 auth.User) (server.
 func (mr *MockBaseMockRecorder) SetCategory(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCategory", reflect.TypeOf((*MockBase)(nil).SetCategory), arg0, arg1, arg2, arg3)
 }
+
+// SetMeetingStatus mocks base method
+func (m *MockBase) SetMeetingStatus(arg0 string, arg1 server.MeetingStatus, arg2 auth.User) (server.Meeting, error) {
+	ret := m.ctrl.Call(m, "SetMeetingStatus", arg0, arg1, arg2)
+	ret0, _ := ret[0].(server.Meeting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetMeetingStatus indicates an expected call of SetMeetingStatus
+func (mr *MockBaseMockRecorder) SetMeetingStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMeetingStatus", reflect.TypeOf((*MockBase)(nil).SetMeetingStatus), arg0, arg1, arg2)
+}
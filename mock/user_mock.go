@@ -8,6 +8,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	auth "github.com/impactasaurus/server/auth"
 )
 
 // MockUser is a mock of User interface
@@ -71,6 +72,30 @@ func (mr *MockUserMockRecorder) Organisation() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Organisation", reflect.TypeOf((*MockUser)(nil).Organisation))
 }
 
+// Organisations mocks base method
+func (m *MockUser) Organisations() []string {
+	ret := m.ctrl.Call(m, "Organisations")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Organisations indicates an expected call of Organisations
+func (mr *MockUserMockRecorder) Organisations() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Organisations", reflect.TypeOf((*MockUser)(nil).Organisations))
+}
+
+// Role mocks base method
+func (m *MockUser) Role() auth.Role {
+	ret := m.ctrl.Call(m, "Role")
+	ret0, _ := ret[0].(auth.Role)
+	return ret0
+}
+
+// Role indicates an expected call of Role
+func (mr *MockUserMockRecorder) Role() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Role", reflect.TypeOf((*MockUser)(nil).Role))
+}
+
 // UserID mocks base method
 func (m *MockUser) UserID() string {
 	ret := m.ctrl.Call(m, "UserID")
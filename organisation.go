@@ -1,6 +1,17 @@
 package server
 
 type Organisation struct {
-	Name string `json:"name"`
-	ID   string `json:"id" bson:"_id"`
+	Name     string               `json:"name"`
+	ID       string               `json:"id" bson:"_id"`
+	Settings OrganisationSettings `json:"settings"`
+}
+
+// OrganisationSettings configures how the organisation uses impactasaurus, unset values are empty strings
+type OrganisationSettings struct {
+	// Timezone is an IANA time zone name, e.g. Europe/London
+	Timezone string `json:"timezone"`
+	// DefaultOutcomeSetID is the outcome set selected by default when starting a meeting
+	DefaultOutcomeSetID string `json:"defaultOutcomeSetID" bson:"defaultOutcomeSetID"`
+	// Locale is a BCP 47 language tag, e.g. en-GB
+	Locale string `json:"locale"`
 }
@@ -1,8 +1,17 @@
 package server
 
+import "time"
+
 type QuestionType string
 
-const LIKERT QuestionType = "likert"
+const (
+	LIKERT        QuestionType = "likert"
+	FREE_TEXT     QuestionType = "free_text"
+	YES_NO        QuestionType = "yes_no"
+	SINGLE_CHOICE QuestionType = "single_choice"
+	MULTI_CHOICE  QuestionType = "multi_choice"
+	NUMERIC       QuestionType = "numeric"
+)
 
 type Aggregation string
 
@@ -21,6 +30,38 @@ type Question struct {
 	CategoryID  string                 `json:"categoryID"  bson:"categoryID"`
 }
 
+// IsNumeric returns true if the question's answers are numbers, only numeric questions are aggregated
+func (q Question) IsNumeric() bool {
+	return q.Type == LIKERT || q.Type == NUMERIC
+}
+
+// IsReverseScored returns true if the question is a Likert scale where a low score is good
+func (q Question) IsReverseScored() bool {
+	reverse, _ := q.Options["reverseScored"].(bool)
+	return q.Type == LIKERT && reverse
+}
+
+// Choices returns the choices offered by single and multi choice questions
+func (q Question) Choices() []string {
+	return toStrings(q.Options["choices"])
+}
+
+// NumericOption returns the option as a float64, stores may decode numbers as any numeric type
+func (q Question) NumericOption(key string) (float64, bool) {
+	switch v := q.Options[key].(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
 type Category struct {
 	ID          string      `json:"id"`
 	Name        string      `json:"name"`
@@ -28,6 +69,8 @@ type Category struct {
 	Aggregation Aggregation `json:"aggregation"`
 }
 
+// OutcomeSet is the editable draft of an outcome set, publishing it stores an immutable copy as its next version.
+// Published copies are also represented as an OutcomeSet, with Version and Published describing that copy.
 type OutcomeSet struct {
 	ID             string     `json:"id" bson:"_id"`
 	OrganisationID string     `json:"organisationID" bson:"organisationID"`
@@ -36,6 +79,9 @@ type OutcomeSet struct {
 	Questions      []Question `json:"questions"`
 	Categories     []Category `json:"categories"`
 	Deleted        bool       `json:"deleted"`
+	// Version is the latest published version, 0 if the outcome set has never been published
+	Version   int       `json:"version"`
+	Published time.Time `json:"published"`
 }
 
 func (os *OutcomeSet) GetCategory(catID string) *Category {
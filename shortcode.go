@@ -0,0 +1,17 @@
+package server
+
+import "time"
+
+// ShortCode is a short, shareable code which can be exchanged for a beneficiary JWT scoped to a meeting
+type ShortCode struct {
+	Code           string    `json:"code" bson:"_id"`
+	MeetingID      string    `json:"meetingID" bson:"meetingID"`
+	Beneficiary    string    `json:"beneficiary"`
+	OrganisationID string    `json:"organisationID" bson:"organisationID"`
+	Expiry         time.Time `json:"expiry"`
+}
+
+// IsExpired returns true if the code can no longer be exchanged
+func (s *ShortCode) IsExpired() bool {
+	return time.Now().After(s.Expiry)
+}